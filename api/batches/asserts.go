@@ -0,0 +1,54 @@
+package batches
+
+import (
+	"io"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// RequireSliceOfBatches asserts that the value decoded from 'r' is a slice of
+// valid Batches; the slice is returned.
+func RequireSliceOfBatches(t *testing.T, r io.Reader) []Batch {
+	b, err := DecodeBatchSlice(r)
+	require.Nil(t, err, "Error decoding slice of batches from reader")
+	AssertBatchSlice(t, b)
+	return b
+}
+
+// RequireBatch asserts that the value decoded from 'r' is a valid Batch; the
+// Batch is returned.
+func RequireBatch(t *testing.T, r io.Reader) Batch {
+	b, err := DecodeBatch(r)
+	require.Nil(t, err, "Error decoding batch from reader")
+	AssertBatch(t, b)
+	return b
+}
+
+// AssertBatchSlice asserts that all Batches 'bats' have valid content.
+func AssertBatchSlice(t *testing.T, bats []Batch) {
+	for _, b := range bats {
+		AssertBatch(t, b)
+	}
+}
+
+// AssertBatch asserts that a Batch 'b' has valid content.
+func AssertBatch(t *testing.T, b Batch) {
+	assert.NotEmpty(t, b.ID, "Batch.ID")
+	assert.NotEmpty(t, b.OrderID, "Batch.OrderID")
+	assert.NotEmpty(t, b.Description, "Batch.Description")
+	assert.NotEmpty(t, b.State, "Batch.State")
+	assert.True(t, b.LastModified > 0, "Batch.LastModified")
+}
+
+// AssertBatchesEqual asserts that 'exp' and 'act' contain the same Batches. If
+// 'orderless' is true then the slices are ordered by ID first.
+func AssertBatchesEqual(t *testing.T, exp []Batch, act []Batch, orderless bool) {
+	if orderless {
+		sort.Sort(ByBatchID(exp))
+		sort.Sort(ByBatchID(act))
+	}
+	assert.Equal(t, exp, act)
+}
@@ -0,0 +1,137 @@
+package batches
+
+import (
+	"database/sql"
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/PaulioRandall/go-cookies/cookies"
+	"github.com/PaulioRandall/go-qlueless-api/shared/wrapped"
+)
+
+// Batch represents a Batch, aka, unit of work within an Order.
+type Batch struct {
+	ID           string `json:"id,omitempty"`
+	LastModified int64  `json:"last_modified"`
+	OrderID      string `json:"order_id"`
+	Description  string `json:"description"`
+	State        string `json:"state"`
+	Dead         bool   `json:"dead,omitempty"`
+	Extra        string `json:"extra,omitempty"`
+}
+
+// DecodeBatch decodes a Batch from data obtained via a Reader.
+func DecodeBatch(r io.Reader) (Batch, error) {
+	var b Batch
+	d := json.NewDecoder(r)
+	err := d.Decode(&b)
+	return b, err
+}
+
+// DecodeBatchSlice decodes a slice of Batches from data obtained via a Reader.
+func DecodeBatchSlice(r io.Reader) ([]Batch, error) {
+	var b []Batch
+	d := json.NewDecoder(r)
+	err := d.Decode(&b)
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// Clean removes redundent whitespace from property values within a Batch
+// except where whitespace is allowable.
+func (b *Batch) Clean() {
+	b.Description = strings.TrimSpace(b.Description)
+	b.ID = strings.TrimSpace(b.ID)
+	b.OrderID = strings.TrimSpace(b.OrderID)
+	b.State = strings.TrimSpace(b.State)
+}
+
+// Validate checks each field contains valid content returning the Violations
+// found or an empty slice if all is well. The message of each Violation is
+// suitable for returning to clients.
+func (b *Batch) Validate(isNew bool) wrapped.Violations {
+	r := wrapped.Violations{}
+
+	if b.Description == "" {
+		r.Add("description", "required", "Batches must have a description.")
+	}
+
+	if !isNew {
+		if !cookies.IsUint(b.ID) {
+			r.Add("id", "invalid", "Batches must have a positive integer ID.")
+		}
+
+		if b.LastModified < 1 {
+			r.Add("last_modified", "invalid", "Batches must have a last modified Unix date in milliseconds")
+		}
+	}
+
+	if !cookies.IsUint(b.OrderID) {
+		r.Add("order_id", "invalid", "Batches must belong to an Order with a positive integer ID.")
+	}
+
+	if b.State == "" {
+		r.Add("state", "required", "Batches must have a state.")
+	}
+
+	return r
+}
+
+// Update updates the Batch within the database.
+func (b *Batch) Update(db *sql.DB) error {
+	stmt, err := db.Prepare(`INSERT INTO batch (
+		id, order_id, description, state, is_dead, extra
+	) VALUES (
+		$1, $2, $3, $4, $5, $6
+	);`)
+
+	if stmt != nil {
+		defer stmt.Close()
+	}
+
+	if err != nil {
+		return err
+	}
+
+	_, err = stmt.Exec(b.ID,
+		b.OrderID,
+		b.Description,
+		b.State,
+		b.Dead,
+		b.Extra)
+
+	return err
+}
+
+// stamp is a file private function that sets the last modified time of the
+// Batch 'b', about to become a new revision, to the Unix time 'now' in
+// milliseconds. If the revision it replaces was made at or after 'now' then a
+// millisecond after it is used instead so no two revisions of a Batch share a
+// last modified time.
+func stamp(b *Batch, now int64) {
+	if now <= b.LastModified {
+		now = b.LastModified + 1
+	}
+	b.LastModified = now
+}
+
+// ByBatchID is a slice of Batches
+type ByBatchID []Batch
+
+// Len implements from sort.Interface
+func (bb ByBatchID) Len() int {
+	return len(bb)
+}
+
+// Swap implements from sort.Interface
+func (bb ByBatchID) Swap(i, j int) {
+	bb[i], bb[j] = bb[j], bb[i]
+}
+
+// Less implements from sort.Interface
+func (bb ByBatchID) Less(i, j int) bool {
+	return bb[i].ID < bb[j].ID
+}
@@ -0,0 +1,8 @@
+// Package batches collects together the handling of all Batch specific
+// requests. Functionality in this package is primarily tested using API tests
+// within the /tests directory of this project.
+//
+// A Batch is a unit of work belonging to a single Order. Breaking Orders down
+// into Batches allows work to be visualised below the Order level so it's
+// easier to see where work is flowing and where it's sat waiting.
+package batches
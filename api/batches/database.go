@@ -0,0 +1,347 @@
+package batches
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/PaulioRandall/go-cookies/cookies"
+	"github.com/PaulioRandall/go-qlueless-api/api/database"
+)
+
+// CreateTables creates all the Batch tables, views and triggers within the
+// supplied database using the SQL dialect 'd'.
+func CreateTables(db database.Executor, d database.Dialect) (err error) {
+	if d == database.Postgres {
+		return createPostgresTables(db)
+	}
+
+	err = createBatchTable(db)
+	if err != nil {
+		return
+	}
+
+	err = createQlBatchTable(db)
+	if err != nil {
+		return
+	}
+
+	err = createInsertOnLivingBatchTrigger(db)
+	if err != nil {
+		return
+	}
+
+	err = createInsertOnDeadBatchTrigger(db)
+	if err != nil {
+		return
+	}
+
+	err = createUpdateOnBatchTrigger(db)
+	if err != nil {
+		return
+	}
+
+	err = createDeleteOnBatchTrigger(db)
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+// DropTables drops all the Batch tables, views and triggers from the supplied
+// database using the SQL dialect 'd'. All Batch data is lost.
+func DropTables(db database.Executor, d database.Dialect) (err error) {
+	if d == database.Postgres {
+		return dropPostgresTables(db)
+	}
+
+	err = execStmt(db, `DROP TABLE IF EXISTS ql_batch;`)
+	if err != nil {
+		return
+	}
+
+	return execStmt(db, `DROP TABLE IF EXISTS batch;`)
+}
+
+// CreateSeqTable creates the table from which new Batch IDs are allocated
+// within the supplied database, seeding it with the highest ID in use. The SQL
+// is the same for every dialect so 'd' is ignored.
+func CreateSeqTable(db database.Executor, d database.Dialect) (err error) {
+	err = execStmt(db, `CREATE TABLE batch_seq (
+		last_id INTEGER NOT NULL
+	);`)
+	if err != nil {
+		return
+	}
+
+	return execStmt(db, `INSERT INTO batch_seq (last_id)
+		SELECT COALESCE(MAX(id), 0)
+		FROM batch;`)
+}
+
+// DropSeqTable drops the table from which new Batch IDs are allocated from the
+// supplied database. The SQL is the same for every dialect so 'd' is ignored.
+func DropSeqTable(db database.Executor, d database.Dialect) error {
+	return execStmt(db, `DROP TABLE IF EXISTS batch_seq;`)
+}
+
+// createBatchTable creates the Batch table within the supplied database.
+func createBatchTable(db database.Executor) error {
+	return execStmt(db, `CREATE TABLE batch (
+		id INTEGER NOT NULL,
+		order_id INTEGER NOT NULL,
+		last_modified INTEGER NOT NULL DEFAULT(CAST(ROUND((julianday('now') - 2440587.5)*86400000) As INTEGER)),
+		description TEXT NOT NULL,
+		state TEXT NOT NULL,
+		is_dead BOOL NOT NULL DEFAULT FALSE,
+		extra TEXT NOT NULL DEFAULT "",
+		PRIMARY KEY(id, last_modified)
+	);`)
+}
+
+// createQlBatchTable creates the query layer Batch table within the supplied
+// database.
+func createQlBatchTable(db database.Executor) error {
+	return execStmt(db, `CREATE TABLE ql_batch (
+		id INTEGER NOT NULL PRIMARY KEY,
+		order_id INTEGER NOT NULL,
+		last_modified INTEGER NOT NULL,
+		description TEXT NOT NULL,
+		state TEXT NOT NULL,
+		is_dead BOOL NOT NULL,
+		extra TEXT NOT NULL
+	);`)
+}
+
+// createInsertOnLivingBatchTrigger creates a trigger within the supplied
+// database that updates the ql_batch table when ever a new, and living, Batch
+// is inserted into the batch table.
+func createInsertOnLivingBatchTrigger(db database.Executor) error {
+	return execStmt(db, `CREATE TRIGGER insert_on_living_batch
+		AFTER INSERT ON batch
+		FOR EACH ROW
+		WHEN (NEW.is_dead = false)
+		BEGIN
+			REPLACE INTO ql_batch (
+				id, order_id, last_modified, description, state, is_dead, extra
+			) VALUES (
+				NEW.id, NEW.order_id, NEW.last_modified, NEW.description, NEW.state, NEW.is_dead, NEW.extra
+			);
+		END;`)
+}
+
+// createInsertOnDeadBatchTrigger creates a trigger within the supplied
+// database that removes from the ql_batch table the dead Batch inserted into
+// the order table.
+func createInsertOnDeadBatchTrigger(db database.Executor) error {
+	return execStmt(db, `CREATE TRIGGER insert_on_dead_batch
+		AFTER INSERT ON batch
+		FOR EACH ROW
+		WHEN (NEW.is_dead = true)
+		BEGIN
+			DELETE FROM ql_batch
+			WHERE id = NEW.id;
+		END;`)
+}
+
+// createUpdateOnBatchTrigger creates a trigger within the supplied database
+// that raises an error if an update is attempted.
+func createUpdateOnBatchTrigger(db database.Executor) error {
+	return execStmt(db, `CREATE TRIGGER update_on_batch
+		BEFORE UPDATE ON batch
+		BEGIN
+			SELECT RAISE(FAIL, "Updates not allowed, insert with the same Batch ID!");
+		END;`)
+}
+
+// createDeleteOnBatchTrigger creates a trigger within the supplied database
+// that raises an error if a delete is attempted.
+func createDeleteOnBatchTrigger(db database.Executor) error {
+	return execStmt(db, `CREATE TRIGGER delete_on_batch
+		BEFORE DELETE ON batch
+		BEGIN
+			SELECT RAISE(FAIL, "Deletions not allowed!");
+		END;`)
+}
+
+// execStmt executes a SQL statment ensuring it is closed afterwards
+func execStmt(db database.Executor, sql string) error {
+	stmt, err := db.Prepare(sql)
+
+	if stmt != nil {
+		defer stmt.Close()
+	}
+
+	if err != nil {
+		return err
+	}
+
+	_, err = stmt.Exec()
+	return err
+}
+
+// QueryFor queries the database for a single Batch.
+func QueryFor(db *sql.DB, id string) (*Batch, error) {
+	b := Batch{}
+	err := db.QueryRow(`SELECT
+		id,
+		order_id,
+		last_modified,
+		description,
+		state,
+		extra
+	FROM ql_batch
+	WHERE id = $1`, id).Scan(&b.ID,
+		&b.OrderID,
+		&b.LastModified,
+		&b.Description,
+		&b.State,
+		&b.Extra)
+
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, nil
+	case cookies.LogIfErr(err):
+		return nil, err
+	}
+
+	return &b, nil
+}
+
+// QueryMany queries the database for all specified Batches.
+func QueryMany(db database.Executor, ids []interface{}) ([]Batch, error) {
+	posParams := database.Params(1, len(ids))
+	sql := fmt.Sprintf(`SELECT
+			id,
+			order_id,
+			last_modified,
+			description,
+			state,
+			extra
+		FROM ql_batch
+		WHERE id IN (%s)
+		ORDER BY id ASC`, posParams)
+
+	rows, err := db.Query(sql, ids...)
+
+	if rows != nil {
+		defer rows.Close()
+	}
+
+	if cookies.LogIfErr(err) {
+		return nil, err
+	}
+
+	return mapRows(rows)
+}
+
+// QueryAll queries the database for all Batches.
+func QueryAll(db *sql.DB) ([]Batch, error) {
+	rows, err := db.Query(`SELECT
+		id,
+		order_id,
+		last_modified,
+		description,
+		state,
+		extra
+	FROM ql_batch
+	ORDER BY id ASC`)
+
+	if rows != nil {
+		defer rows.Close()
+	}
+
+	if cookies.LogIfErr(err) {
+		return nil, err
+	}
+
+	return mapRows(rows)
+}
+
+// QueryForOrder queries the database for all Batches belonging to the
+// specified Order.
+func QueryForOrder(db *sql.DB, orderID string) ([]Batch, error) {
+	rows, err := db.Query(`SELECT
+		id,
+		order_id,
+		last_modified,
+		description,
+		state,
+		extra
+	FROM ql_batch
+	WHERE order_id = $1
+	ORDER BY id ASC`, orderID)
+
+	if rows != nil {
+		defer rows.Close()
+	}
+
+	if cookies.LogIfErr(err) {
+		return nil, err
+	}
+
+	return mapRows(rows)
+}
+
+// queryForOrders is a file private function that queries the database for the
+// living Batches belonging to any of the Orders with IDs within 'orderIDs'.
+func queryForOrders(db database.Executor, orderIDs []string) ([]Batch, error) {
+	args := make([]interface{}, len(orderIDs))
+	for i, id := range orderIDs {
+		args[i] = id
+	}
+
+	rows, err := db.Query(fmt.Sprintf(`SELECT
+			id,
+			order_id,
+			last_modified,
+			description,
+			state,
+			extra
+		FROM ql_batch
+		WHERE order_id IN (%s)
+		ORDER BY id ASC`, database.Params(1, len(args))), args...)
+
+	if rows != nil {
+		defer rows.Close()
+	}
+
+	if cookies.LogIfErr(err) {
+		return nil, err
+	}
+
+	return mapRows(rows)
+}
+
+// mapRows is a file private function that maps rows from a database query into
+// a slice of Batches.
+func mapRows(rows *sql.Rows) ([]Batch, error) {
+	bats := []Batch{}
+
+	for rows.Next() {
+		b, err := mapRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		bats = append(bats, *b)
+	}
+
+	return bats, nil
+}
+
+// mapRow is a file private function that maps a single row from a database
+// query into a Batch.
+func mapRow(rows *sql.Rows) (*Batch, error) {
+	b := Batch{}
+	err := rows.Scan(&b.ID,
+		&b.OrderID,
+		&b.LastModified,
+		&b.Description,
+		&b.State,
+		&b.Extra)
+
+	if err != nil {
+		return nil, err
+	}
+	return &b, err
+}
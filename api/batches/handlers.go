@@ -0,0 +1,177 @@
+package batches
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/PaulioRandall/go-cookies/cookies"
+	"github.com/PaulioRandall/go-cookies/uhttp"
+	"github.com/PaulioRandall/go-qlueless-api/shared/writers"
+)
+
+// cors contains the CORS headers for /batches responses.
+var cors uhttp.CorsHeaders = uhttp.CorsHeaders{
+	Origin:  "*",
+	Headers: "*",
+	Methods: "GET, POST, PUT, DELETE, OPTIONS",
+}
+
+// Handler handles requests to do with collections of, or individual, Batches.
+type Handler struct {
+	db *sql.DB
+}
+
+// NewHandler returns a new Handler that reads and writes Batches within 'db'.
+func NewHandler(db *sql.DB) *Handler {
+	return &Handler{
+		db: db,
+	}
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(res http.ResponseWriter, req *http.Request) {
+	uhttp.LogRequest(req)
+	uhttp.UseCors(&res, &cors)
+
+	switch {
+	case req.Method == "GET":
+		get(h.db, &res, req)
+	case req.Method == "POST":
+		post(h.db, &res, req)
+	case req.Method == "PUT":
+		put(h.db, &res, req)
+	case req.Method == "DELETE":
+		del(h.db, &res, req)
+	case req.Method == "OPTIONS":
+		res.WriteHeader(http.StatusOK)
+	default:
+		res.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// get handles client requests for any amount of living Batches.
+func get(db *sql.DB, res *http.ResponseWriter, req *http.Request) {
+
+	ids := req.FormValue("ids")
+	ids = cookies.StripWhitespace(ids)
+	orderID := req.FormValue("order_id")
+	orderID = cookies.StripWhitespace(orderID)
+	var bats []Batch
+
+	if ids != "" {
+		_, ok := idCsvToSlice(ids, res, req)
+		if !ok {
+			return
+		}
+	}
+
+	switch {
+	case orderID != "":
+		var ok bool
+		bats, ok = findForOrder(db, orderID, ids, res, req)
+		if !ok {
+			return
+		}
+	case ids == "":
+		var err error
+		bats, err = QueryAll(db)
+		if err != nil {
+			writers.WriteServerError(res, req)
+			return
+		}
+	default:
+		var ok bool
+		bats, ok = find(db, ids, res, req)
+		if !ok {
+			return
+		}
+	}
+
+	m := fmt.Sprintf("Found %d Batches", len(bats))
+	writers.WriteSuccessReply(res, req, http.StatusOK, bats, m)
+}
+
+// post handles client requests for creating new Batches.
+func post(db *sql.DB, res *http.ResponseWriter, req *http.Request) {
+	new, ok := decodeNew(res, req)
+	if !ok {
+		return
+	}
+
+	new.Clean()
+	ok = validateNew(&new, res, req)
+	if !ok {
+		return
+	}
+
+	ok = checkOrderExists(db, "order_id", new.OrderID, res, req)
+	if !ok {
+		return
+	}
+
+	b, ok := insertNew(db, &new, res, req)
+	if !ok {
+		return
+	}
+
+	m := fmt.Sprintf("New Batch with ID '%s' created", b.ID)
+	log.Println(m)
+	writers.WriteSuccessReply(res, req, http.StatusCreated, b, m)
+}
+
+// put handles client requests for updating Batches.
+func put(db *sql.DB, res *http.ResponseWriter, req *http.Request) {
+	mb, ok := decodeMod(res, req)
+	if !ok {
+		return
+	}
+
+	mb.Clean()
+	ok = validateMod(mb, res, req)
+	if !ok {
+		return
+	}
+
+	if mb.Sets("order_id") {
+		ok = checkOrderExists(db, "values.order_id", mb.Values.OrderID, res, req)
+		if !ok {
+			return
+		}
+	}
+
+	bats, ok := pushMod(db, mb, res, req)
+	if !ok {
+		return
+	}
+
+	ids := idsToCSV(bats)
+	m := fmt.Sprintf("Updated Batches with the following IDs '%s'", ids)
+	log.Println(m)
+	writers.WriteSuccessReply(res, req, http.StatusOK, bats, m)
+}
+
+// del handles client requests for deleting Batches.
+func del(db *sql.DB, res *http.ResponseWriter, req *http.Request) {
+	ids, ok := idCsvToSlice(req.FormValue("ids"), res, req)
+	if !ok {
+		return
+	}
+
+	bats, ok := pushKill(db, ids, res, req)
+	if !ok {
+		return
+	}
+
+	m := fmt.Sprintf("Deleted Batches with the following IDs '%s'", idsToCSV(bats))
+	missing := findMissing(ids, bats)
+	if len(missing) > 0 {
+		m += fmt.Sprintf(", the following IDs could not be found '%s'",
+			strings.Join(missing, ", "))
+	}
+
+	log.Println(m)
+	writers.WriteSuccessReply(res, req, http.StatusOK, bats, m)
+}
@@ -0,0 +1,215 @@
+package batches
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/PaulioRandall/go-cookies/cookies"
+	"github.com/PaulioRandall/go-qlueless-api/api/orders"
+	"github.com/PaulioRandall/go-qlueless-api/shared/wrapped"
+	"github.com/PaulioRandall/go-qlueless-api/shared/writers"
+)
+
+// find finds the Batches with the specified IDs.
+func find(db *sql.DB, ids string, res *http.ResponseWriter, req *http.Request) ([]Batch, bool) {
+	idSlice := strings.Split(ids, ",")
+	s := make([]interface{}, len(idSlice))
+
+	for i, id := range idSlice {
+		s[i] = id
+	}
+
+	bats, err := QueryMany(db, s)
+
+	if err != nil {
+		writers.WriteServerError(res, req)
+		return nil, false
+	}
+
+	return bats, true
+}
+
+// findForOrder finds the Batches belonging to the specified Order. If 'ids' is
+// not empty then only Batches with those IDs are returned.
+func findForOrder(db *sql.DB, orderID string, ids string, res *http.ResponseWriter, req *http.Request) ([]Batch, bool) {
+	if !cookies.IsUint(orderID) {
+		r := wrapped.Violations{}
+		r.Add("order_id", "invalid", fmt.Sprintf("Could not parse query parameter"+
+			" 'order_id=%s' into an Order ID", orderID))
+		writers.WriteInvalid(res, req, r)
+		return nil, false
+	}
+
+	bats, err := QueryForOrder(db, orderID)
+	if err != nil {
+		writers.WriteServerError(res, req)
+		return nil, false
+	}
+
+	if ids == "" {
+		return bats, true
+	}
+
+	wanted := map[string]bool{}
+	for _, id := range strings.Split(ids, ",") {
+		wanted[id] = true
+	}
+
+	r := []Batch{}
+	for _, b := range bats {
+		if wanted[b.ID] {
+			r = append(r, b)
+		}
+	}
+
+	return r, true
+}
+
+// checkOrderExists checks the living Order a Batch is to belong to exists.
+// 'field' is the name of the request field holding the Order ID.
+func checkOrderExists(db *sql.DB, field string, orderID string, res *http.ResponseWriter, req *http.Request) bool {
+	o, err := orders.QueryFor(db, orderID)
+	if err != nil {
+		writers.WriteServerError(res, req)
+		return false
+	}
+
+	if o == nil {
+		r := wrapped.Violations{}
+		r.Add(field, "not_found", fmt.Sprintf("Batches must belong to an"+
+			" existing Order, no Order with ID '%s' could be found", orderID))
+		writers.WriteInvalid(res, req, r)
+		return false
+	}
+
+	return true
+}
+
+// decodeNew decodes a NewBatch from a Request.Body.
+func decodeNew(res *http.ResponseWriter, req *http.Request) (NewBatch, bool) {
+	b, err := DecodeNewBatch(req.Body)
+	if err != nil {
+		writers.WriteBadRequest(res, req, "Unable to decode request body into a Batch")
+		return NewBatch{}, false
+	}
+	return b, true
+}
+
+// validateNew validates a NewBatch that has yet to be assigned an ID.
+func validateNew(b *NewBatch, res *http.ResponseWriter, req *http.Request) bool {
+	r := b.Validate()
+	if len(r) != 0 {
+		writers.WriteInvalid(res, req, r)
+		return false
+	}
+	return true
+}
+
+// insertNew inserts a new Batch into the database.
+func insertNew(db *sql.DB, new *NewBatch, res *http.ResponseWriter, req *http.Request) (*Batch, bool) {
+	b, ok := new.Insert(db)
+	if !ok {
+		writers.WriteServerError(res, req)
+	}
+	return b, ok
+}
+
+// decodeMod decodes modifications to Batches from a Request.Body.
+func decodeMod(res *http.ResponseWriter, req *http.Request) (*ModBatch, bool) {
+	mb, err := DecodeModBatch(req.Body)
+	if err != nil {
+		writers.WriteBadRequest(res, req,
+			"Unable to decode request body into a Batch update")
+		return nil, false
+	}
+	return &mb, true
+}
+
+// validateMod validates a Batch update.
+func validateMod(mb *ModBatch, res *http.ResponseWriter, req *http.Request) bool {
+	r := mb.Validate()
+	if len(r) != 0 {
+		writers.WriteInvalid(res, req, r)
+		return false
+	}
+	return true
+}
+
+// idCsvToSlice validates then parses a CSV string of IDs into a slice.
+func idCsvToSlice(idCsv string, res *http.ResponseWriter, req *http.Request) ([]string, bool) {
+	idCsv = cookies.StripWhitespace(idCsv)
+	r := wrapped.Violations{}
+
+	switch {
+	case idCsv == "":
+		r.Add("ids", "required", "Query parameter 'ids' is missing or empty")
+	case !cookies.IsUintCSV(idCsv):
+		r.Add("ids", "invalid", fmt.Sprintf("Could not parse query parameter"+
+			" 'ids=%s' into a list of Batch IDs", idCsv))
+	}
+
+	if len(r) != 0 {
+		writers.WriteInvalid(res, req, r)
+		return nil, false
+	}
+
+	ids := strings.Split(idCsv, ",")
+	return ids, true
+}
+
+// idsToCSV returns a CSV string of all Batch IDs within the given slice.
+func idsToCSV(bats []Batch) string {
+	ids := ""
+	for i, b := range bats {
+		switch i {
+		case 0:
+			ids = b.ID
+		default:
+			ids += ", " + b.ID
+		}
+	}
+	return ids
+}
+
+// pushMod performs the specified modification operation and pushes the result
+// to the database.
+func pushMod(db *sql.DB, mb *ModBatch, res *http.ResponseWriter, req *http.Request) ([]Batch, bool) {
+	bats, ok := mb.Update(db)
+	if !ok {
+		writers.WriteServerError(res, req)
+		return nil, false
+	}
+	return bats, true
+}
+
+// pushKill marks the Batches with the specified IDs as dead by pushing a new
+// revision of each to the database.
+func pushKill(db *sql.DB, ids []string, res *http.ResponseWriter, req *http.Request) ([]Batch, bool) {
+	mb := ModBatch{
+		IDs:   strings.Join(ids, ","),
+		Props: "dead",
+		Values: Batch{
+			Dead: true,
+		},
+	}
+	return pushMod(db, &mb, res, req)
+}
+
+// findMissing returns the IDs within 'ids' that do not belong to any of the
+// Batches within 'bats'.
+func findMissing(ids []string, bats []Batch) []string {
+	found := make(map[string]bool, len(bats))
+	for _, b := range bats {
+		found[b.ID] = true
+	}
+
+	missing := []string{}
+	for _, id := range ids {
+		if !found[id] {
+			missing = append(missing, id)
+		}
+	}
+	return missing
+}
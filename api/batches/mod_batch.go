@@ -0,0 +1,250 @@
+package batches
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/PaulioRandall/go-cookies/cookies"
+	"github.com/PaulioRandall/go-qlueless-api/api/database"
+	"github.com/PaulioRandall/go-qlueless-api/shared/wrapped"
+)
+
+// ModBatch represents an update to a Batch.
+type ModBatch struct {
+	IDs    string `json:"ids"`
+	Props  string `json:"set"`
+	Values Batch  `json:"values"`
+}
+
+// DecodeModBatch decodes a ModBatch from data obtained via a Reader.
+func DecodeModBatch(r io.Reader) (ModBatch, error) {
+	var mb ModBatch
+	d := json.NewDecoder(r)
+	err := d.Decode(&mb)
+	return mb, err
+}
+
+// SplitIDs returns the IDs of the Batches to update as a slice.
+func (mb *ModBatch) SplitIDs() []string {
+	if mb.IDs == "" {
+		return []string{}
+	}
+	return strings.Split(mb.IDs, ",")
+}
+
+// SplitProps returns the property names of the properties to update.
+func (mb *ModBatch) SplitProps() []string {
+	if mb.Props == "" {
+		return []string{}
+	}
+	return strings.Split(mb.Props, ",")
+}
+
+// Sets returns true if the property 'prop' is one of the properties to update.
+func (mb *ModBatch) Sets(prop string) bool {
+	for _, p := range mb.SplitProps() {
+		if p == prop {
+			return true
+		}
+	}
+	return false
+}
+
+// Clean cleans up the ModBatch by removing whitespace where applicable.
+func (mb *ModBatch) Clean() {
+	mb.IDs = cookies.StripWhitespace(mb.IDs)
+	mb.Props = cookies.StripWhitespace(mb.Props)
+	mb.Values.Clean()
+}
+
+// validateProps checks the properties declared for change are valid and the
+// property value for each is valid. The Violations found are appended to 'r'.
+func (mb *ModBatch) validateProps(r *wrapped.Violations) {
+	for _, prop := range mb.SplitProps() {
+		switch prop {
+		case "dead", "extra":
+		case "order_id":
+			if !cookies.IsUint(mb.Values.OrderID) {
+				r.Add("values.order_id", "invalid", "Batches must belong to an Order with a positive integer ID.")
+			}
+		case "description":
+			if mb.Values.Description == "" {
+				r.Add("values.description", "required", "Batches must have a description.")
+			}
+		case "state":
+			if mb.Values.State == "" {
+				r.Add("values.state", "required", "Batches must have a state.")
+			}
+		default:
+			r.Add("set", "immutable", fmt.Sprintf("Can't update unknown or immutable property '%s'.", prop))
+		}
+	}
+}
+
+// Validate checks each field contains valid content returning the Violations
+// found or an empty slice if all is well. The message of each Violation is
+// suitable for returning to clients.
+func (mb *ModBatch) Validate() wrapped.Violations {
+	r := wrapped.Violations{}
+
+	switch {
+	case mb.IDs == "":
+		r.Add("ids", "required", "'ids' must be supplied so the Batches to update can be determined.")
+	case !cookies.IsUintCSV(mb.IDs):
+		r.Add("ids", "invalid", "'ids' must be a CSV of positive integers.")
+	}
+
+	if mb.Props == "" {
+		r.Add("set", "required", "Some properties must be 'set' for any updating to take place.")
+	}
+
+	mb.validateProps(&r)
+	return r
+}
+
+// ApplyMod applies the modifications to the supplied Batch only touching
+// those properties the user has specified
+func (mb *ModBatch) ApplyMod(b *Batch) {
+	mod := mb.Values
+	for _, p := range mb.SplitProps() {
+		switch p {
+		case "order_id":
+			b.OrderID = mod.OrderID
+		case "description":
+			b.Description = mod.Description
+		case "state":
+			b.State = mod.State
+		case "dead":
+			b.Dead = mod.Dead
+		case "extra":
+			b.Extra = mod.Extra
+		}
+	}
+}
+
+// Update pushes the modification of changes to the database within a single
+// transaction.
+func (mb *ModBatch) Update(db *sql.DB) ([]Batch, bool) {
+	var bats []Batch
+
+	err := database.InTx(db, func(tx *sql.Tx) (err error) {
+		bats, err = mb.update(tx)
+		return
+	})
+
+	if cookies.LogIfErr(err) {
+		return nil, false
+	}
+	return bats, true
+}
+
+// KillForOrders kills every living Batch belonging to the Orders with IDs
+// within 'orderIDs' within the transaction 'tx'. It allows the Batches to be
+// killed alongside their Orders so no living Batch belongs to a dead Order.
+func KillForOrders(tx *sql.Tx, orderIDs []string) error {
+	err := lockWrites(tx)
+	if err != nil {
+		return err
+	}
+
+	bats, err := queryForOrders(tx, orderIDs)
+	if err != nil || len(bats) == 0 {
+		return err
+	}
+
+	ids := make([]string, len(bats))
+	for i, b := range bats {
+		ids[i] = b.ID
+	}
+
+	mb := ModBatch{
+		IDs:   strings.Join(ids, ","),
+		Props: "dead",
+		Values: Batch{
+			Dead: true,
+		},
+	}
+
+	_, err = mb.update(tx)
+	return err
+}
+
+// update is a file private function that performs the work of Update() within
+// the transaction 'tx' returning any error encountered. The write lock is taken
+// before the Batches are read so no other writer can push a revision between
+// reading and stamping them.
+func (mb *ModBatch) update(tx *sql.Tx) ([]Batch, error) {
+
+	err := lockWrites(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := mb.SplitIDs()
+	args := make([]interface{}, len(ids))
+	for i := range ids {
+		args[i] = ids[i]
+	}
+
+	bats, err := QueryMany(tx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	err = mb.insertEach(tx, bats, cookies.ToUnixMilli(time.Now()))
+	if err != nil {
+		return nil, err
+	}
+
+	return bats, nil
+}
+
+// insertEach is a file private function that performs the actual SQL operation
+// of pushing modifications to the database. Each new revision is stamped with
+// the Unix time 'now' in milliseconds.
+func (mb *ModBatch) insertEach(tx *sql.Tx, bats []Batch, now int64) error {
+
+	stmt, err := tx.Prepare(`INSERT INTO batch
+			(id, order_id, last_modified, description, state, is_dead, extra)
+		VALUES
+			($1, $2, $3, $4, $5, $6, $7);`)
+
+	if stmt != nil {
+		defer stmt.Close()
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return mb.execStmtForEach(stmt, bats, now)
+}
+
+// execStmtForEach executes the insert statment provided for each Batch
+// provided.
+func (mb *ModBatch) execStmtForEach(stmt *sql.Stmt, bats []Batch, now int64) error {
+	for i := range bats {
+
+		b := &bats[i]
+		mb.ApplyMod(b)
+		stamp(b, now)
+
+		_, err := stmt.Exec(b.ID,
+			b.OrderID,
+			b.LastModified,
+			b.Description,
+			b.State,
+			b.Dead,
+			b.Extra)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
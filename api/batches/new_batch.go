@@ -0,0 +1,154 @@
+package batches
+
+import (
+	"database/sql"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/PaulioRandall/go-cookies/cookies"
+	"github.com/PaulioRandall/go-qlueless-api/api/database"
+	"github.com/PaulioRandall/go-qlueless-api/shared/wrapped"
+)
+
+// NewBatch represents a new Batch.
+type NewBatch struct {
+	OrderID     string `json:"order_id"`
+	Description string `json:"description"`
+	State       string `json:"state"`
+	Extra       string `json:"extra"`
+}
+
+// DecodeNewBatch decodes a NewBatch from data obtained via a Reader
+func DecodeNewBatch(r io.Reader) (NewBatch, error) {
+	var b NewBatch
+	d := json.NewDecoder(r)
+	err := d.Decode(&b)
+	return b, err
+}
+
+// Clean removes redundent whitespace from property values within a Batch
+// except where whitespace is allowable.
+func (nb *NewBatch) Clean() {
+	nb.OrderID = strings.TrimSpace(nb.OrderID)
+	nb.Description = strings.TrimSpace(nb.Description)
+	nb.State = strings.TrimSpace(nb.State)
+}
+
+// Validate checks each field contains valid content returning the Violations
+// found or an empty slice if all is well. The message of each Violation is
+// suitable for returning to clients.
+func (nb *NewBatch) Validate() wrapped.Violations {
+	r := wrapped.Violations{}
+
+	if !cookies.IsUint(nb.OrderID) {
+		r.Add("order_id", "invalid", "Batches must belong to an Order with a positive integer ID.")
+	}
+
+	if nb.Description == "" {
+		r.Add("description", "required", "Batches must have a description.")
+	}
+
+	if nb.State == "" {
+		r.Add("state", "required", "Batches must have a state.")
+	}
+
+	return r
+}
+
+// Insert inserts the NewBatch into the database. Its ID is allocated within
+// the same transaction so concurrent inserts never share an ID.
+func (nb *NewBatch) Insert(db *sql.DB) (b *Batch, ok bool) {
+	ok = false
+
+	var id string
+	err := database.InTx(db, func(tx *sql.Tx) error {
+		var err error
+		id, err = nextID(tx)
+		if err != nil {
+			return err
+		}
+
+		stmt, err := tx.Prepare(`INSERT INTO batch (
+			id, order_id, description, state, extra
+		) VALUES (
+			$1, $2, $3, $4, $5
+		);`)
+
+		if stmt != nil {
+			defer stmt.Close()
+		}
+
+		if err != nil {
+			return err
+		}
+
+		_, err = nb.execInsert(id, stmt)
+		return err
+	})
+
+	if cookies.LogIfErr(err) {
+		return
+	}
+
+	b, err = QueryFor(db, id)
+	if cookies.LogIfErr(err) {
+		return
+	}
+
+	ok = true
+	return
+}
+
+// lockWrites is a file private function that takes the write lock, within the
+// transaction 'tx', that every transaction modifying Batches must hold. Only
+// one transaction may hold it at a time so reads made after taking it can't be
+// invalidated by another writer before the transaction ends.
+func lockWrites(tx *sql.Tx) error {
+	_, err := tx.Exec(`UPDATE batch_seq SET last_id = last_id;`)
+	return err
+}
+
+// nextID is a file private function that allocates the next free Batch ID
+// within the transaction 'tx'. Incrementing the sequence takes the same lock
+// as lockWrites() so concurrent transactions wait their turn rather than read
+// the same ID.
+func nextID(tx *sql.Tx) (string, error) {
+	_, err := tx.Exec(`UPDATE batch_seq SET last_id = last_id + 1;`)
+	if err != nil {
+		return "", err
+	}
+
+	var id int64
+	err = tx.QueryRow(`SELECT last_id FROM batch_seq;`).Scan(&id)
+	if err != nil {
+		return "", err
+	}
+
+	return strconv.FormatInt(id, 10), nil
+}
+
+// execInsert is a file private function that executes the supplied insert
+// statement
+func (nb *NewBatch) execInsert(id string, stmt *sql.Stmt) (b *Batch, err error) {
+	b = &Batch{
+		ID:          id,
+		OrderID:     nb.OrderID,
+		Description: nb.Description,
+		State:       nb.State,
+		Extra:       nb.Extra,
+	}
+
+	_, err = stmt.Exec(b.ID,
+		b.OrderID,
+		b.Description,
+		b.State,
+		b.Extra)
+
+	if err != nil {
+		b = nil
+	}
+
+	return
+}
@@ -0,0 +1,116 @@
+package batches
+
+import (
+	"github.com/PaulioRandall/go-qlueless-api/api/database"
+)
+
+// createPostgresTables creates all the Batch tables, functions and triggers
+// within the supplied PostgreSQL database. They behave exactly as their SQLite
+// counterparts do; the batch table is append only and the ql_batch table holds
+// the latest revision of each living Batch.
+func createPostgresTables(db database.Executor) error {
+	stmts := []string{
+		`CREATE TABLE batch (
+			id INTEGER NOT NULL,
+			order_id INTEGER NOT NULL,
+			last_modified BIGINT NOT NULL DEFAULT (FLOOR(EXTRACT(EPOCH FROM clock_timestamp()) * 1000)::BIGINT),
+			description TEXT NOT NULL,
+			state TEXT NOT NULL,
+			is_dead BOOL NOT NULL DEFAULT FALSE,
+			extra TEXT NOT NULL DEFAULT '',
+			PRIMARY KEY(id, last_modified)
+		);`,
+		`CREATE TABLE ql_batch (
+			id INTEGER NOT NULL PRIMARY KEY,
+			order_id INTEGER NOT NULL,
+			last_modified BIGINT NOT NULL,
+			description TEXT NOT NULL,
+			state TEXT NOT NULL,
+			is_dead BOOL NOT NULL,
+			extra TEXT NOT NULL
+		);`,
+		`CREATE FUNCTION upsert_ql_batch() RETURNS TRIGGER AS $$
+			BEGIN
+				INSERT INTO ql_batch (
+					id, order_id, last_modified, description, state, is_dead, extra
+				) VALUES (
+					NEW.id, NEW.order_id, NEW.last_modified, NEW.description, NEW.state, NEW.is_dead, NEW.extra
+				)
+				ON CONFLICT (id) DO UPDATE SET
+					order_id = EXCLUDED.order_id,
+					last_modified = EXCLUDED.last_modified,
+					description = EXCLUDED.description,
+					state = EXCLUDED.state,
+					is_dead = EXCLUDED.is_dead,
+					extra = EXCLUDED.extra;
+				RETURN NULL;
+			END;
+		$$ LANGUAGE plpgsql;`,
+		`CREATE TRIGGER insert_on_living_batch
+			AFTER INSERT ON batch
+			FOR EACH ROW
+			WHEN (NEW.is_dead = false)
+			EXECUTE PROCEDURE upsert_ql_batch();`,
+		`CREATE FUNCTION delete_ql_batch() RETURNS TRIGGER AS $$
+			BEGIN
+				DELETE FROM ql_batch
+				WHERE id = NEW.id;
+				RETURN NULL;
+			END;
+		$$ LANGUAGE plpgsql;`,
+		`CREATE TRIGGER insert_on_dead_batch
+			AFTER INSERT ON batch
+			FOR EACH ROW
+			WHEN (NEW.is_dead = true)
+			EXECUTE PROCEDURE delete_ql_batch();`,
+		`CREATE FUNCTION forbid_batch_update() RETURNS TRIGGER AS $$
+			BEGIN
+				RAISE EXCEPTION 'Updates not allowed, insert with the same Batch ID!';
+			END;
+		$$ LANGUAGE plpgsql;`,
+		`CREATE TRIGGER update_on_batch
+			BEFORE UPDATE ON batch
+			FOR EACH ROW
+			EXECUTE PROCEDURE forbid_batch_update();`,
+		`CREATE FUNCTION forbid_batch_delete() RETURNS TRIGGER AS $$
+			BEGIN
+				RAISE EXCEPTION 'Deletions not allowed!';
+			END;
+		$$ LANGUAGE plpgsql;`,
+		`CREATE TRIGGER delete_on_batch
+			BEFORE DELETE ON batch
+			FOR EACH ROW
+			EXECUTE PROCEDURE forbid_batch_delete();`,
+	}
+
+	for _, s := range stmts {
+		_, err := db.Exec(s)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// dropPostgresTables drops all the Batch tables, functions and triggers from
+// the supplied PostgreSQL database.
+func dropPostgresTables(db database.Executor) error {
+	stmts := []string{
+		`DROP TABLE IF EXISTS ql_batch;`,
+		`DROP TABLE IF EXISTS batch;`,
+		`DROP FUNCTION IF EXISTS upsert_ql_batch();`,
+		`DROP FUNCTION IF EXISTS delete_ql_batch();`,
+		`DROP FUNCTION IF EXISTS forbid_batch_update();`,
+		`DROP FUNCTION IF EXISTS forbid_batch_delete();`,
+	}
+
+	for _, s := range stmts {
+		_, err := db.Exec(s)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -13,23 +13,36 @@ import (
 
 const mime_md = "text/markdown; charset=utf-8"
 
-var changelog *[]byte = nil
-var once sync.Once
-
 var cors uhttp.CorsHeaders = uhttp.CorsHeaders{
 	Origin:  "*",
 	Headers: "*",
 	Methods: "GET, OPTIONS",
 }
 
-// ChangelogHandler handles requests for the APIs changelog
-func ChangelogHandler(res http.ResponseWriter, req *http.Request) {
+// Handler handles requests for the APIs changelog. The changelog is loaded
+// from file on the first request.
+type Handler struct {
+	path      string
+	changelog *[]byte
+	once      sync.Once
+}
+
+// NewHandler returns a new Handler that serves the changelog within the file
+// at 'path'.
+func NewHandler(path string) *Handler {
+	return &Handler{
+		path: path,
+	}
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(res http.ResponseWriter, req *http.Request) {
 	uhttp.LogRequest(req)
 	uhttp.UseCors(&res, &cors)
 
 	switch req.Method {
 	case "GET":
-		get(&res, req)
+		h.get(&res, req)
 	case "OPTIONS":
 		res.WriteHeader(http.StatusOK)
 	default:
@@ -38,10 +51,10 @@ func ChangelogHandler(res http.ResponseWriter, req *http.Request) {
 }
 
 // get generates responses for obtaining the CHANGELOG
-func get(res *http.ResponseWriter, req *http.Request) {
-	once.Do(load)
+func (h *Handler) get(res *http.ResponseWriter, req *http.Request) {
+	h.once.Do(h.load)
 
-	if changelog == nil {
+	if h.changelog == nil {
 		log.Println("[BUG] CHANGELOG not loaded")
 		writers.WriteServerError(res, req)
 		return
@@ -49,17 +62,17 @@ func get(res *http.ResponseWriter, req *http.Request) {
 
 	(*res).Header().Set("Content-Type", mime_md)
 	(*res).WriteHeader(http.StatusOK)
-	(*res).Write(*changelog)
+	(*res).Write(*h.changelog)
 }
 
 // load loads the changelog from a file
-func load() {
-	bytes, err := ioutil.ReadFile("./CHANGELOG.md")
+func (h *Handler) load() {
+	bytes, err := ioutil.ReadFile(h.path)
 	if cookies.LogIfErr(err) {
-		changelog = nil
+		h.changelog = nil
 		return
 	}
 
-	changelog = &bytes
+	h.changelog = &bytes
 	return
 }
@@ -0,0 +1,10 @@
+// Package config provides the configuration of the API; where it listens,
+// which database it uses, where its resource files live, and which origins
+// may call it.
+//
+// Configuration is layered, each layer overriding the one before it: the
+// defaults within this package, an optional JSON config file, environment
+// variables, and finally command line flags. This allows a sensible local
+// setup with no configuration at all while still allowing each deployment to
+// change only what it needs.
+package config
@@ -0,0 +1,221 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/PaulioRandall/go-cookies/strlist"
+)
+
+// DefaultFile is the config file read when no other is specified, it is not
+// an error for it to be missing.
+const DefaultFile = "./config.json"
+
+// Config represents the configuration of the API.
+type Config struct {
+	Addr          string   `json:"addr"`
+	DatabaseDSN   string   `json:"database_dsn"`
+	OpenAPIPath   string   `json:"openapi_path"`
+	ChangelogPath string   `json:"changelog_path"`
+	WorkflowPath  string   `json:"workflow_path"`
+	CorsOrigins   []string `json:"cors_origins"`
+}
+
+// env maps each environment variable to the setter of the property it
+// configures.
+var env = map[string]func(*Config, string){
+	"QLUELESS_ADDR":         func(c *Config, v string) { c.Addr = v },
+	"QLUELESS_DATABASE_DSN": func(c *Config, v string) { c.DatabaseDSN = v },
+	"QLUELESS_OPENAPI":      func(c *Config, v string) { c.OpenAPIPath = v },
+	"QLUELESS_CHANGELOG":    func(c *Config, v string) { c.ChangelogPath = v },
+	"QLUELESS_WORKFLOW":     func(c *Config, v string) { c.WorkflowPath = v },
+	"QLUELESS_CORS_ORIGINS": func(c *Config, v string) { c.CorsOrigins = splitCSV(v) },
+}
+
+// Default returns the configuration used when nothing else is specified.
+func Default() Config {
+	return Config{
+		Addr:          ":8080",
+		DatabaseDSN:   "./qlueless.db",
+		OpenAPIPath:   "./openapi.json",
+		ChangelogPath: "./CHANGELOG.md",
+		WorkflowPath:  "./workflow.json",
+		CorsOrigins:   []string{"*"},
+	}
+}
+
+// Load builds the configuration from the defaults, the config file, the
+// environment, and the command line arguments 'args' in that order. The config
+// file is specified by the '-config' flag, else the 'QLUELESS_CONFIG'
+// environment variable, else DefaultFile is used if it exists.
+func Load(args []string) (Config, error) {
+	c := Default()
+
+	fs, flags := newFlagSet()
+	err := fs.Parse(args)
+	if err != nil {
+		return c, err
+	}
+
+	err = c.loadFile(configFile(fs))
+	if err != nil {
+		return c, err
+	}
+
+	c.loadEnv()
+	fs.Visit(func(f *flag.Flag) {
+		if set, ok := flags[f.Name]; ok {
+			set(&c, f.Value.String())
+		}
+	})
+
+	errMsgs := c.Validate()
+	if len(errMsgs) != 0 {
+		return c, errors.New(strings.Join(errMsgs, " "))
+	}
+
+	return c, nil
+}
+
+// DecodeConfig decodes a Config from data obtained via a Reader. Properties
+// missing from the data are left as they are within 'c'.
+func DecodeConfig(r io.Reader, c *Config) error {
+	d := json.NewDecoder(r)
+	d.DisallowUnknownFields()
+	return d.Decode(c)
+}
+
+// Validate checks each property contains valid content returning a non-empty
+// slice of human readable error messages detailing the violations found or an
+// empty slice if all is well.
+func (c *Config) Validate() []string {
+	r := strlist.StrList{}
+
+	if c.Addr == "" {
+		r.Add("The listen address must not be empty.")
+	}
+
+	if c.DatabaseDSN == "" {
+		r.Add("The database DSN must not be empty.")
+	}
+
+	if c.OpenAPIPath == "" {
+		r.Add("The OpenAPI specification path must not be empty.")
+	}
+
+	if c.ChangelogPath == "" {
+		r.Add("The changelog path must not be empty.")
+	}
+
+	if c.WorkflowPath == "" {
+		r.Add("The workflow path must not be empty.")
+	}
+
+	if len(c.CorsOrigins) == 0 {
+		r.Add("At least one CORS origin must be allowed, use '*' to allow any.")
+	}
+
+	return r.Slice()
+}
+
+// AllowOrigin returns the value of the 'Access-Control-Allow-Origin' header
+// for a request from 'origin' or an empty string if the origin is not allowed.
+func (c *Config) AllowOrigin(origin string) string {
+	for _, o := range c.CorsOrigins {
+		switch {
+		case o == "*":
+			return "*"
+		case origin != "" && strings.EqualFold(o, origin):
+			return origin
+		}
+	}
+	return ""
+}
+
+// newFlagSet is a file private function that creates the command line flags
+// returning them along with a map of each flag name to the setter of the
+// property it configures.
+func newFlagSet() (*flag.FlagSet, map[string]func(*Config, string)) {
+	fs := flag.NewFlagSet("go-qlueless-api", flag.ContinueOnError)
+	fs.String("config", "", "path to a JSON config file")
+	fs.String("addr", "", "TCP address to listen on, e.g. ':8080'")
+	fs.String("db", "", "database DSN, e.g. './qlueless.db'")
+	fs.String("openapi", "", "path to the OpenAPI specification")
+	fs.String("changelog", "", "path to the changelog")
+	fs.String("workflow", "", "path to the workflow, the default workflow is used if it does not exist")
+	fs.String("cors-origins", "", "CSV of origins allowed to make CORS requests, '*' for any")
+
+	return fs, map[string]func(*Config, string){
+		"addr":         func(c *Config, v string) { c.Addr = v },
+		"db":           func(c *Config, v string) { c.DatabaseDSN = v },
+		"openapi":      func(c *Config, v string) { c.OpenAPIPath = v },
+		"changelog":    func(c *Config, v string) { c.ChangelogPath = v },
+		"workflow":     func(c *Config, v string) { c.WorkflowPath = v },
+		"cors-origins": func(c *Config, v string) { c.CorsOrigins = splitCSV(v) },
+	}
+}
+
+// configFile is a file private function that returns the path to the config
+// file and whether it must exist.
+func configFile(fs *flag.FlagSet) (string, bool) {
+	if f := fs.Lookup("config").Value.String(); f != "" {
+		return f, true
+	}
+
+	if f := os.Getenv("QLUELESS_CONFIG"); f != "" {
+		return f, true
+	}
+
+	return DefaultFile, false
+}
+
+// loadFile is a file private function that overrides the configuration with
+// the properties within the config file at 'path'. If 'required' is false then
+// a missing file is ignored.
+func (c *Config) loadFile(path string, required bool) error {
+	b, err := ioutil.ReadFile(path)
+
+	switch {
+	case os.IsNotExist(err) && !required:
+		return nil
+	case err != nil:
+		return err
+	}
+
+	err = DecodeConfig(bytes.NewReader(b), c)
+	if err != nil {
+		return fmt.Errorf("Could not decode config file '%s': %v", path, err)
+	}
+
+	return nil
+}
+
+// loadEnv is a file private function that overrides the configuration with
+// the environment variables that are set.
+func (c *Config) loadEnv() {
+	for name, set := range env {
+		if v, ok := os.LookupEnv(name); ok {
+			set(c, v)
+		}
+	}
+}
+
+// splitCSV is a file private function that splits a CSV into its trimmed,
+// non-empty values.
+func splitCSV(csv string) []string {
+	r := []string{}
+	for _, v := range strings.Split(csv, ",") {
+		v = strings.TrimSpace(v)
+		if v != "" {
+			r = append(r, v)
+		}
+	}
+	return r
+}
@@ -1,2 +1,9 @@
-// database package provides a shared database for the whole application.
+// database package provides functions for opening the applications database.
+// The database is owned by whoever opens it and handed to the handlers that
+// need it so many may be open within one process.
+//
+// SQLite and PostgreSQL databases are supported, the one used is chosen by the
+// scheme of the DSN. Queries shared by both use numbered parameters, i.e. '$1',
+// while each resource package creates its tables, and the triggers that keep
+// them append only, in the dialect of the database.
 package database
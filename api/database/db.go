@@ -2,61 +2,87 @@ package database
 
 import (
 	"database/sql"
+	"strconv"
+	"strings"
 
+	_ "github.com/lib/pq"
 	_ "github.com/mattn/go-sqlite3"
 )
 
-// db is the applications shared database.
-var db *sql.DB = nil
+// Dialect identifies the flavour of SQL spoken by a database. Its value is the
+// name of the driver used to open such databases.
+type Dialect string
 
-// Get returns the applications shared database.
-func Get() *sql.DB {
-	return db
+const (
+	// SQLite is the dialect of SQLite databases, the default.
+	SQLite Dialect = "sqlite3"
+
+	// Postgres is the dialect of PostgreSQL databases.
+	Postgres Dialect = "postgres"
+)
+
+// Executor is implemented by both *sql.DB and *sql.Tx allowing statements to
+// be executed, and queries made, with or without a transaction.
+type Executor interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Prepare(query string) (*sql.Stmt, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
 }
 
-// IsOpen returns true if the shared database is open.
-func IsOpen() bool {
-	if db == nil {
-		return false
+// DialectOf returns the dialect of the database at 'dsn'. DSNs with the
+// 'postgres' or 'postgresql' scheme are PostgreSQL databases while anything
+// else is treated as the path to a SQLite database.
+func DialectOf(dsn string) Dialect {
+	switch {
+	case strings.HasPrefix(dsn, "postgres://"):
+		return Postgres
+	case strings.HasPrefix(dsn, "postgresql://"):
+		return Postgres
+	default:
+		return SQLite
 	}
-	return true
 }
 
-// Open opens the database if not already open.
-func Open() *sql.DB {
-	if !IsOpen() {
-		var err error
-		db, err = openSQLiteDatabase("./qlueless.db")
-		if err != nil {
-			panic(err)
+// Open opens the database at 'dsn' using the driver of its dialect. SQLite
+// databases are created if they don't already exist.
+func Open(dsn string) (*sql.DB, error) {
+	db, err := sql.Open(string(DialectOf(dsn)), dsn)
+
+	if err != nil {
+		if db != nil {
+			db.Close()
 		}
+		db = nil
 	}
 
-	return db
+	return db, err
 }
 
-// Close closes the database if open.
-func Close() {
-	if IsOpen() {
-		err := db.Close()
-		db = nil
-
-		if err != nil {
-			panic(err)
-		}
+// Params returns a CSV of 'n' positional query parameters numbered from
+// 'first', e.g. '$3,$4,$5'. Numbered parameters are understood by both SQLite
+// and PostgreSQL so queries using them are portable between the two.
+func Params(first int, n int) string {
+	p := make([]string, n)
+	for i := range p {
+		p[i] = "$" + strconv.Itoa(first+i)
 	}
+	return strings.Join(p, ",")
 }
 
-// openSQLiteDatabase opens a SQLite database, creating it if it doesn't already
-// exist.
-func openSQLiteDatabase(path string) (db *sql.DB, err error) {
-	db, err = sql.Open("sqlite3", path)
+// InTx calls 'f' within a new transaction of 'db' committing it if 'f' succeeds
+// else rolling it back.
+func InTx(db *sql.DB, f func(*sql.Tx) error) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
 
+	err = f(tx)
 	if err != nil {
-		if db != nil {
-			db.Close()
-		}
+		tx.Rollback()
+		return err
 	}
 
-	return
+	return tx.Commit()
 }
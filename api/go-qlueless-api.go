@@ -3,10 +3,54 @@
 package main
 
 import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/PaulioRandall/go-qlueless-api/api/config"
 	"github.com/PaulioRandall/go-qlueless-api/api/server"
 )
 
 // Main is the primary entry point for the HTTP server.
 func main() {
-	server.StartUp(false)
+	cfg, err := config.Load(os.Args[1:])
+	if err != nil {
+		log.Fatalln("[Go Qlueless API]:", err)
+	}
+
+	s, err := server.New(cfg)
+	if err != nil {
+		log.Fatalln("[Go Qlueless API]:", err)
+	}
+
+	err = s.Start()
+	if err != nil {
+		log.Fatalln("[Go Qlueless API]:", err)
+	}
+
+	go shutdownOnSignal(s)
+
+	err = s.Wait()
+	if err != nil {
+		log.Fatalln("[Go Qlueless API]:", err)
+	}
+}
+
+// shutdownOnSignal shuts down the server 's' once an interrupt or termination
+// signal is received.
+func shutdownOnSignal(s *server.Server) {
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	<-sig
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	err := s.Shutdown(ctx)
+	if err != nil {
+		log.Println("[Go Qlueless API]:", err)
+	}
 }
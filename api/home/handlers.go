@@ -4,7 +4,6 @@ import (
 	"net/http"
 
 	uhttp "github.com/PaulioRandall/go-cookies/uhttp"
-	wrapped "github.com/PaulioRandall/go-qlueless-api/shared/wrapped"
 	writers "github.com/PaulioRandall/go-qlueless-api/shared/writers"
 )
 
@@ -16,9 +15,5 @@ func HomeHandler(res http.ResponseWriter, req *http.Request) {
 
 // notFound handles requests nothing (404s)
 func notFound(res *http.ResponseWriter, req *http.Request) {
-	r := wrapped.WrappedReply{
-		Message: "Resource not found",
-	}
-
-	writers.WriteWrappedReply(res, req, http.StatusNotFound, r)
+	writers.WriteNotFound(res, req, "Resource not found")
 }
@@ -0,0 +1,205 @@
+package metrics
+
+import (
+	"sort"
+
+	"github.com/PaulioRandall/go-qlueless-api/api/orders"
+	"github.com/PaulioRandall/go-qlueless-api/api/ventures"
+	"github.com/PaulioRandall/go-qlueless-api/api/workflow"
+)
+
+// BucketSizes maps the name of each supported time bucket to its length in
+// milliseconds.
+var BucketSizes = map[string]int64{
+	"hour": 60 * 60 * 1000,
+	"day":  24 * 60 * 60 * 1000,
+	"week": 7 * 24 * 60 * 60 * 1000,
+}
+
+// MaxBuckets is the maximum number of time buckets a single CFD may contain.
+const MaxBuckets = 10000
+
+// mondayOffset is the time between the Unix epoch, a Thursday, and the first
+// Monday after it so weekly buckets start on Mondays.
+const mondayOffset int64 = 4 * 24 * 60 * 60 * 1000
+
+// CFD represents the data behind a cumulative flow diagram; the number of
+// living Ventures and Orders in each state at the end of each time bucket.
+type CFD struct {
+	From    int64       `json:"from"`
+	To      int64       `json:"to"`
+	Bucket  string      `json:"bucket"`
+	Buckets []CFDBucket `json:"buckets"`
+}
+
+// CFDBucket represents a single time bucket within a CFD. The counts are of
+// the living Ventures and Orders in each state at the end of the bucket.
+type CFDBucket struct {
+	Start    int64          `json:"start"`
+	End      int64          `json:"end"`
+	Ventures map[string]int `json:"ventures"`
+	Orders   map[string]int `json:"orders"`
+}
+
+// revision represents a single revision of a Venture or Order stripped down to
+// what is needed to count states.
+type revision struct {
+	id    string
+	time  int64
+	state string
+	dead  bool
+}
+
+// tally replays revisions in the order they were made keeping a running count
+// of the living entities in each state so each bucket costs only the revisions
+// made within it.
+type tally struct {
+	revs   []revision
+	next   int
+	latest map[string]*revision
+	counts map[string]int
+}
+
+// AlignBucket returns the start of the bucket, of the named size, containing
+// the Unix time 'ms'. Days start at midnight UTC and weeks on Mondays.
+func AlignBucket(ms int64, bucket string) int64 {
+	size := BucketSizes[bucket]
+	offset := int64(0)
+	if bucket == "week" {
+		offset = mondayOffset
+	}
+
+	start := ms - offset
+	start -= ((start % size) + size) % size
+	return start + offset
+}
+
+// ComputeCFD replays the revisions of Ventures and Orders, which must both be
+// ordered by ID then time of revision, to count the number of living Ventures
+// and Orders in each state at the end of every bucket between 'from' and 'to'.
+// Every workflow state is counted for Ventures, even if zero, so the columns
+// of the diagram are stable.
+func ComputeCFD(vens []ventures.Venture, ords []orders.Order, wf *workflow.Workflow, from int64, to int64, bucket string) CFD {
+	c := CFD{
+		From:    from,
+		To:      to,
+		Bucket:  bucket,
+		Buckets: []CFDBucket{},
+	}
+
+	vTally := newTally(ventureRevisions(vens))
+	oTally := newTally(orderRevisions(ords))
+	size := BucketSizes[bucket]
+
+	for start := AlignBucket(from, bucket); start < to; start += size {
+		b := CFDBucket{
+			Start:    start,
+			End:      min(start+size, to),
+			Ventures: map[string]int{},
+		}
+
+		for _, s := range wf.States {
+			b.Ventures[s] = 0
+		}
+
+		vTally.countAt(b.End, b.Ventures)
+		b.Orders = oTally.countAt(b.End, map[string]int{})
+		c.Buckets = append(c.Buckets, b)
+	}
+
+	return c
+}
+
+// CountBuckets returns the number of buckets, of the named size, between
+// 'from' and 'to'.
+func CountBuckets(from int64, to int64, bucket string) int64 {
+	if from >= to {
+		return 0
+	}
+
+	size := BucketSizes[bucket]
+	start := AlignBucket(from, bucket)
+	return (to - start + size - 1) / size
+}
+
+// OrderStates returns the states counted for Orders within the CFD sorted
+// alphabetically.
+func (c *CFD) OrderStates() []string {
+	seen := map[string]bool{}
+	for _, b := range c.Buckets {
+		for s := range b.Orders {
+			seen[s] = true
+		}
+	}
+
+	states := []string{}
+	for s := range seen {
+		states = append(states, s)
+	}
+
+	sort.Strings(states)
+	return states
+}
+
+// newTally is a file private function that returns a tally of the revisions
+// 'revs', which must be ordered by ID then time of revision. The revisions are
+// sorted once, by time, keeping revisions made at the same time in the order
+// given.
+func newTally(revs []revision) *tally {
+	sort.SliceStable(revs, func(i, j int) bool {
+		return revs[i].time < revs[j].time
+	})
+
+	return &tally{
+		revs:   revs,
+		latest: map[string]*revision{},
+		counts: map[string]int{},
+	}
+}
+
+// countAt is a file private function that adds to 'counts' the number of living
+// entities in each state at the Unix time 'at' returning 'counts'. Calls must
+// be made in ascending order of 'at'.
+func (t *tally) countAt(at int64, counts map[string]int) map[string]int {
+	for ; t.next < len(t.revs) && t.revs[t.next].time <= at; t.next++ {
+		rev := &t.revs[t.next]
+
+		if prev := t.latest[rev.id]; prev != nil && !prev.dead {
+			t.counts[prev.state]--
+		}
+
+		if !rev.dead {
+			t.counts[rev.state]++
+		}
+
+		t.latest[rev.id] = rev
+	}
+
+	for s, n := range t.counts {
+		if n > 0 {
+			counts[s] += n
+		}
+	}
+
+	return counts
+}
+
+// ventureRevisions is a file private function that converts Venture revisions
+// into revisions that can be counted.
+func ventureRevisions(vens []ventures.Venture) []revision {
+	revs := make([]revision, len(vens))
+	for i, v := range vens {
+		revs[i] = revision{v.ID, v.LastModified, v.State, v.Dead}
+	}
+	return revs
+}
+
+// orderRevisions is a file private function that converts Order revisions
+// into revisions that can be counted.
+func orderRevisions(ords []orders.Order) []revision {
+	revs := make([]revision, len(ords))
+	for i, o := range ords {
+		revs[i] = revision{o.ID, o.LastModified, o.State, o.Dead}
+	}
+	return revs
+}
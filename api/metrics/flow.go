@@ -0,0 +1,228 @@
+package metrics
+
+import (
+	"github.com/PaulioRandall/go-qlueless-api/api/ventures"
+	"github.com/PaulioRandall/go-qlueless-api/api/workflow"
+)
+
+// Flow represents the flow of Ventures through the workflow within a time
+// window. All durations are in milliseconds.
+type Flow struct {
+	Since     int64         `json:"since"`
+	Until     int64         `json:"until"`
+	Ventures  []VentureFlow `json:"ventures"`
+	Aggregate FlowAggregate `json:"aggregate"`
+}
+
+// VentureFlow represents the flow of a single Venture through the workflow.
+// 'CycleTime' and 'LeadTime' are nil unless the Venture was done within the
+// time window and 'AgeInState' is nil if the Venture is dead.
+type VentureFlow struct {
+	ID          string           `json:"id"`
+	State       string           `json:"state"`
+	Dead        bool             `json:"dead,omitempty"`
+	TimeInState map[string]int64 `json:"time_in_state"`
+	CycleTime   *int64           `json:"cycle_time"`
+	LeadTime    *int64           `json:"lead_time"`
+	AgeInState  *int64           `json:"age_in_state"`
+}
+
+// FlowAggregate represents the flow of all Ventures within a time window.
+type FlowAggregate struct {
+	Count          int              `json:"count"`
+	WIP            map[string]int   `json:"wip"`
+	TimeInState    map[string]int64 `json:"time_in_state"`
+	MeanCycleTime  *int64           `json:"mean_cycle_time"`
+	MeanLeadTime   *int64           `json:"mean_lead_time"`
+	MeanAgeInState map[string]int64 `json:"mean_age_in_state"`
+}
+
+// ComputeFlow replays the revisions of Ventures, which must be ordered by
+// Venture ID then time of revision, to compute their flow through the workflow
+// between 'since' and 'until'. If 'states' is not empty then only Ventures in
+// one of those states at 'until' are included.
+func ComputeFlow(revs []ventures.Venture, wf *workflow.Workflow, since int64, until int64, states []string) Flow {
+	f := Flow{
+		Since:    since,
+		Until:    until,
+		Ventures: []VentureFlow{},
+	}
+
+	for _, vr := range groupByID(revs) {
+		vf, ok := replay(vr, wf, since, until)
+		if ok && inStates(vf.State, states) {
+			f.Ventures = append(f.Ventures, vf)
+		}
+	}
+
+	f.Aggregate = aggregate(f.Ventures)
+	return f
+}
+
+// groupByID is a file private function that splits a slice of revisions,
+// ordered by Venture ID, into a slice of revisions per Venture.
+func groupByID(revs []ventures.Venture) [][]ventures.Venture {
+	groups := [][]ventures.Venture{}
+
+	for i := 0; i < len(revs); {
+		j := i + 1
+		for j < len(revs) && revs[j].ID == revs[i].ID {
+			j++
+		}
+		groups = append(groups, revs[i:j])
+		i = j
+	}
+
+	return groups
+}
+
+// replay is a file private function that replays the revisions of a single
+// Venture returning false if the Venture did not exist, or was dead, for the
+// whole time window.
+func replay(revs []ventures.Venture, wf *workflow.Workflow, since int64, until int64) (VentureFlow, bool) {
+	vf := VentureFlow{
+		ID:          revs[0].ID,
+		TimeInState: map[string]int64{},
+	}
+
+	created := revs[0].LastModified
+	started, done := int64(-1), int64(-1)
+	entered := created
+	overlaps := false
+
+	for i, rev := range revs {
+		if i > 0 && (revs[i-1].Dead || revs[i-1].State != rev.State) {
+			entered = rev.LastModified
+		}
+
+		vf.State = rev.State
+		vf.Dead = rev.Dead
+
+		if rev.Dead {
+			continue
+		}
+
+		if started < 0 && rev.State != wf.Initial() {
+			started = rev.LastModified
+		}
+
+		if done < 0 && wf.IsDone(rev.State) {
+			done = rev.LastModified
+			if started < 0 {
+				started = done
+			}
+		}
+
+		end := until
+		if i+1 < len(revs) {
+			end = revs[i+1].LastModified
+		}
+
+		from, to := max(rev.LastModified, since), min(end, until)
+		if from <= to {
+			vf.TimeInState[rev.State] += to - from
+			overlaps = true
+		}
+	}
+
+	if !overlaps {
+		return vf, false
+	}
+
+	if done >= since {
+		vf.LeadTime = ptr(done - created)
+		vf.CycleTime = ptr(done - started)
+	}
+
+	if !vf.Dead {
+		vf.AgeInState = ptr(until - entered)
+	}
+
+	return vf, true
+}
+
+// aggregate is a file private function that aggregates the flow of many
+// Ventures.
+func aggregate(vfs []VentureFlow) FlowAggregate {
+	a := FlowAggregate{
+		Count:          len(vfs),
+		WIP:            map[string]int{},
+		TimeInState:    map[string]int64{},
+		MeanAgeInState: map[string]int64{},
+	}
+
+	var cycle, lead []int64
+	for _, vf := range vfs {
+		for s, ms := range vf.TimeInState {
+			a.TimeInState[s] += ms
+		}
+
+		if vf.CycleTime != nil {
+			cycle = append(cycle, *vf.CycleTime)
+			lead = append(lead, *vf.LeadTime)
+		}
+
+		if vf.AgeInState != nil {
+			a.WIP[vf.State]++
+			a.MeanAgeInState[vf.State] += *vf.AgeInState
+		}
+	}
+
+	for s, n := range a.WIP {
+		a.MeanAgeInState[s] /= int64(n)
+	}
+
+	a.MeanCycleTime = mean(cycle)
+	a.MeanLeadTime = mean(lead)
+	return a
+}
+
+// inStates is a file private function that returns true if 'states' is empty
+// or 'state' is one of them.
+func inStates(state string, states []string) bool {
+	if len(states) == 0 {
+		return true
+	}
+
+	for _, s := range states {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
+
+// mean is a file private function that returns the mean of 'vals' or nil if
+// there are none.
+func mean(vals []int64) *int64 {
+	if len(vals) == 0 {
+		return nil
+	}
+
+	var sum int64
+	for _, v := range vals {
+		sum += v
+	}
+	return ptr(sum / int64(len(vals)))
+}
+
+// ptr is a file private function that returns a pointer to a copy of 'v'.
+func ptr(v int64) *int64 {
+	return &v
+}
+
+// min is a file private function that returns the smaller of 'a' and 'b'.
+func min(a int64, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// max is a file private function that returns the larger of 'a' and 'b'.
+func max(a int64, b int64) int64 {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,143 @@
+package metrics
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/PaulioRandall/go-cookies/cookies"
+	"github.com/PaulioRandall/go-cookies/uhttp"
+	"github.com/PaulioRandall/go-qlueless-api/api/orders"
+	"github.com/PaulioRandall/go-qlueless-api/api/ventures"
+	"github.com/PaulioRandall/go-qlueless-api/api/workflow"
+	"github.com/PaulioRandall/go-qlueless-api/shared/wrapped"
+	"github.com/PaulioRandall/go-qlueless-api/shared/writers"
+)
+
+var cors uhttp.CorsHeaders = uhttp.CorsHeaders{
+	Origin:  "*",
+	Headers: "*",
+	Methods: "GET, OPTIONS",
+}
+
+// Handler handles requests for metrics of Ventures and Orders.
+type Handler struct {
+	db    *sql.DB
+	store ventures.VentureStore
+	wf    *workflow.Workflow
+}
+
+// NewHandler returns a new Handler that measures the Orders within 'db' and
+// the Ventures within 'store' as they move through the workflow 'wf'.
+func NewHandler(db *sql.DB, store ventures.VentureStore, wf *workflow.Workflow) *Handler {
+	return &Handler{
+		db:    db,
+		store: store,
+		wf:    wf,
+	}
+}
+
+// Flow handles requests for the flow of Ventures through the workflow.
+func (h *Handler) Flow(res http.ResponseWriter, req *http.Request) {
+	uhttp.LogRequest(req)
+	uhttp.UseCors(&res, &cors)
+
+	switch req.Method {
+	case "GET":
+		getFlow(h.store, h.wf, &res, req)
+	case "OPTIONS":
+		res.WriteHeader(http.StatusOK)
+	default:
+		res.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// getFlow handles client requests for the flow of Ventures through the
+// workflow.
+func getFlow(s ventures.VentureStore, wf *workflow.Workflow, res *http.ResponseWriter, req *http.Request) {
+	r := wrapped.Violations{}
+	until := ventures.MillisParam("until", cookies.ToUnixMilli(time.Now()), req, &r)
+	since := ventures.MillisParam("since", 0, req, &r)
+	states := parseStates(wf, req, &r)
+
+	if len(r) == 0 && since > until {
+		r.Add("since", "invalid", "Query parameter 'since' must not be after 'until'")
+	}
+
+	if len(r) != 0 {
+		writers.WriteInvalid(res, req, r)
+		return
+	}
+
+	revs, err := s.History(nil, 0, until)
+	if err != nil {
+		writers.WriteServerError(res, req)
+		return
+	}
+
+	f := ComputeFlow(revs, wf, since, until, states)
+	m := fmt.Sprintf("Computed the flow of %d Ventures", len(f.Ventures))
+	writers.WriteSuccessReply(res, req, http.StatusOK, f, m)
+}
+
+// CFD handles requests for cumulative flow diagram data.
+func (h *Handler) CFD(res http.ResponseWriter, req *http.Request) {
+	uhttp.LogRequest(req)
+	uhttp.UseCors(&res, &cors)
+
+	switch req.Method {
+	case "GET":
+		getCFD(h.db, h.store, h.wf, &res, req)
+	case "OPTIONS":
+		res.WriteHeader(http.StatusOK)
+	default:
+		res.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// getCFD handles client requests for the number of living Ventures and Orders
+// in each state within each time bucket.
+func getCFD(db *sql.DB, s ventures.VentureStore, wf *workflow.Workflow, res *http.ResponseWriter, req *http.Request) {
+	r := wrapped.Violations{}
+	bucket := parseBucket(req, &r)
+	to := ventures.MillisParam("to", cookies.ToUnixMilli(time.Now()), req, &r)
+	from := ventures.MillisParam("from", max(to-30*BucketSizes[bucket], 0), req, &r)
+
+	switch {
+	case len(r) != 0:
+	case from >= to:
+		r.Add("from", "invalid", "Query parameter 'from' must be before 'to'")
+	case CountBuckets(from, to, bucket) > MaxBuckets:
+		r.Add("bucket", "too_many", fmt.Sprintf("No more than %d buckets"+
+			" may be requested, use a larger 'bucket' or a shorter time window",
+			MaxBuckets))
+	}
+
+	if len(r) != 0 {
+		writers.WriteInvalid(res, req, r)
+		return
+	}
+
+	vens, err := s.History(nil, 0, to)
+	if err != nil {
+		writers.WriteServerError(res, req)
+		return
+	}
+
+	ords, err := orders.QueryAllHistory(db, to)
+	if err != nil {
+		writers.WriteServerError(res, req)
+		return
+	}
+
+	c := ComputeCFD(vens, ords, wf, from, to, bucket)
+
+	if wantsCSV(req) {
+		writeCSV(res, req, &c, wf)
+		return
+	}
+
+	m := fmt.Sprintf("Computed %d CFD buckets", len(c.Buckets))
+	writers.WriteSuccessReply(res, req, http.StatusOK, c, m)
+}
@@ -0,0 +1,93 @@
+package metrics
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/PaulioRandall/go-cookies/cookies"
+	"github.com/PaulioRandall/go-qlueless-api/api/workflow"
+	"github.com/PaulioRandall/go-qlueless-api/shared/wrapped"
+)
+
+// parseStates parses the 'state' query parameter as a CSV of workflow states
+// returning an empty slice if the parameter is missing or empty. A Violation is
+// added to 'r' for each state not within the workflow.
+func parseStates(wf *workflow.Workflow, req *http.Request, r *wrapped.Violations) []string {
+	states := []string{}
+
+	v := strings.TrimSpace(req.FormValue("state"))
+	if v == "" {
+		return states
+	}
+
+	for _, st := range strings.Split(v, ",") {
+		st = wf.Canonical(strings.TrimSpace(st))
+		if !wf.Has(st) {
+			r.Add("state", "not_in_workflow", fmt.Sprintf("Query parameter"+
+				" 'state' may only contain states within the workflow, '%s' is"+
+				" not one of them.", st))
+			continue
+		}
+		states = append(states, st)
+	}
+
+	return states
+}
+
+// parseBucket parses the 'bucket' query parameter as the name of a bucket size
+// returning 'day' if the parameter is missing, empty, or not a bucket size. A
+// Violation is added to 'r' if it's not a bucket size.
+func parseBucket(req *http.Request, r *wrapped.Violations) string {
+	v := strings.ToLower(cookies.StripWhitespace(req.FormValue("bucket")))
+	if v == "" {
+		return "day"
+	}
+
+	if _, ok := BucketSizes[v]; !ok {
+		r.Add("bucket", "invalid", fmt.Sprintf("Query parameter 'bucket'"+
+			" must be one of 'hour', 'day', or 'week', not '%s'", v))
+		return "day"
+	}
+
+	return v
+}
+
+// wantsCSV returns true if the client has asked for CSV, either with the query
+// parameter 'format=csv' or via the 'Accept' header.
+func wantsCSV(req *http.Request) bool {
+	if f := cookies.StripWhitespace(req.FormValue("format")); f != "" {
+		return strings.ToLower(f) == "csv"
+	}
+	return strings.Contains(req.Header.Get("Accept"), "text/csv")
+}
+
+// writeCSV writes the CFD to the client as CSV with one row per bucket,
+// resource, and state. Venture states are written in workflow order and Order
+// states alphabetically.
+func writeCSV(res *http.ResponseWriter, req *http.Request, c *CFD, wf *workflow.Workflow) {
+	(*res).Header().Set("Content-Type", "text/csv; charset=utf-8")
+	(*res).WriteHeader(http.StatusOK)
+
+	w := csv.NewWriter(*res)
+	w.Write([]string{"start", "end", "resource", "state", "count"})
+
+	orderStates := c.OrderStates()
+	for _, b := range c.Buckets {
+		start := strconv.FormatInt(b.Start, 10)
+		end := strconv.FormatInt(b.End, 10)
+
+		for _, s := range wf.States {
+			w.Write([]string{start, end, "ventures", s, strconv.Itoa(b.Ventures[s])})
+		}
+
+		for _, s := range orderStates {
+			w.Write([]string{start, end, "orders", s, strconv.Itoa(b.Orders[s])})
+		}
+	}
+
+	w.Flush()
+	cookies.LogIfErr(w.Error())
+}
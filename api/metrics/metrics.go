@@ -0,0 +1,9 @@
+// Package metrics provides handlers for measuring the flow of Ventures through
+// the workflow. Functionality in this package is primarily tested using API
+// tests within the /tests directory of this project.
+//
+// The venture table is append only so every state change a Venture has been
+// through is recorded along with the time it was made. Replaying those
+// revisions makes visible the work in progress that is not, in fact, being
+// progressed.
+package metrics
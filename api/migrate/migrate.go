@@ -0,0 +1,187 @@
+// Package migrate versions the schema of the database. Each Migration is a
+// numbered change to the schema that can be applied, 'up', or rolled back,
+// 'down'. The version of each applied Migration is recorded within the
+// schema_migrations table so only pending Migrations are applied when the API
+// starts.
+//
+// Migrations are compiled into the application so a build always carries the
+// schema it expects. Once released a Migration must never change; alter the
+// schema by appending a new one.
+package migrate
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/PaulioRandall/go-cookies/cookies"
+	"github.com/PaulioRandall/go-qlueless-api/api/database"
+)
+
+// Migration represents a single versioned change to the database schema.
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(database.Executor, database.Dialect) error
+	Down    func(database.Executor, database.Dialect) error
+}
+
+// Record represents the status of a Migration within a database.
+type Record struct {
+	Version int
+	Name    string
+	Applied int64
+}
+
+// Pending returns true if the Migration has not been applied.
+func (r Record) Pending() bool {
+	return r.Applied == 0
+}
+
+// Up applies all pending Migrations to the database 'db', of dialect 'd', in
+// version order returning those applied. Each Migration is applied within its
+// own transaction so a failure leaves the database at the last good version.
+func Up(db *sql.DB, d database.Dialect) ([]Migration, error) {
+	applied, err := queryApplied(db)
+	if err != nil {
+		return nil, err
+	}
+
+	done := []Migration{}
+	for _, m := range migrations {
+		if _, ok := applied[m.Version]; ok {
+			continue
+		}
+
+		err = database.InTx(db, func(tx *sql.Tx) error {
+			err := m.Up(tx, d)
+			if err != nil {
+				return err
+			}
+
+			_, err = tx.Exec(`INSERT INTO schema_migrations (
+				version, name, applied
+			) VALUES (
+				$1, $2, $3
+			);`, m.Version, m.Name, cookies.ToUnixMilli(time.Now()))
+			return err
+		})
+
+		if err != nil {
+			return done, fmt.Errorf("Migration %d '%s' failed: %v", m.Version, m.Name, err)
+		}
+
+		done = append(done, m)
+	}
+
+	return done, nil
+}
+
+// Down rolls back the latest applied Migration within the database 'db', of
+// dialect 'd', returning it or nil if no Migrations have been applied.
+func Down(db *sql.DB, d database.Dialect) (*Migration, error) {
+	applied, err := queryApplied(db)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := len(migrations) - 1; i >= 0; i-- {
+		m := migrations[i]
+		if _, ok := applied[m.Version]; !ok {
+			continue
+		}
+
+		err = database.InTx(db, func(tx *sql.Tx) error {
+			err := m.Down(tx, d)
+			if err != nil {
+				return err
+			}
+
+			_, err = tx.Exec(`DELETE FROM schema_migrations
+				WHERE version = $1;`, m.Version)
+			return err
+		})
+
+		if err != nil {
+			return nil, fmt.Errorf("Rollback of migration %d '%s' failed: %v", m.Version, m.Name, err)
+		}
+
+		return &m, nil
+	}
+
+	return nil, nil
+}
+
+// Status returns a Record of every Migration, in version order, stating
+// whether and when each was applied to the database 'db'.
+func Status(db *sql.DB) ([]Record, error) {
+	applied, err := queryApplied(db)
+	if err != nil {
+		return nil, err
+	}
+
+	recs := make([]Record, len(migrations))
+	for i, m := range migrations {
+		recs[i] = Record{
+			Version: m.Version,
+			Name:    m.Name,
+			Applied: applied[m.Version],
+		}
+	}
+
+	return recs, nil
+}
+
+// queryApplied is a file private function that returns a map of the version
+// of each applied Migration to when it was applied. The schema_migrations
+// table is created if it doesn't exist. An error is returned if the database
+// has a Migration applied that this build knows nothing about, i.e. the
+// database has been migrated by a newer build.
+func queryApplied(db *sql.DB) (map[int]int64, error) {
+	_, err := db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER NOT NULL PRIMARY KEY,
+		name TEXT NOT NULL,
+		applied BIGINT NOT NULL
+	);`)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query(`SELECT version, applied
+		FROM schema_migrations
+		ORDER BY version ASC;`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := map[int]int64{}
+	for rows.Next() {
+		var v int
+		var t int64
+
+		err = rows.Scan(&v, &t)
+		if err != nil {
+			return nil, err
+		}
+
+		if !isKnown(v) {
+			return nil, fmt.Errorf("Database schema version %d is unknown to this build", v)
+		}
+
+		applied[v] = t
+	}
+
+	return applied, rows.Err()
+}
+
+// isKnown is a file private function that returns true if 'v' is the version
+// of a Migration within this build.
+func isKnown(v int) bool {
+	for _, m := range migrations {
+		if m.Version == v {
+			return true
+		}
+	}
+	return false
+}
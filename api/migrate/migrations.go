@@ -0,0 +1,48 @@
+package migrate
+
+import (
+	"github.com/PaulioRandall/go-qlueless-api/api/batches"
+	"github.com/PaulioRandall/go-qlueless-api/api/orders"
+	"github.com/PaulioRandall/go-qlueless-api/api/ventures"
+)
+
+// migrations holds every Migration in the order they're applied. Versions
+// must be unique and ascending; append new Migrations to the end.
+var migrations = []Migration{
+	{
+		Version: 1,
+		Name:    "create_ventures",
+		Up:      ventures.CreateTables,
+		Down:    ventures.DropTables,
+	},
+	{
+		Version: 2,
+		Name:    "create_orders",
+		Up:      orders.CreateTables,
+		Down:    orders.DropTables,
+	},
+	{
+		Version: 3,
+		Name:    "create_batches",
+		Up:      batches.CreateTables,
+		Down:    batches.DropTables,
+	},
+	{
+		Version: 4,
+		Name:    "create_venture_seq",
+		Up:      ventures.CreateSeqTable,
+		Down:    ventures.DropSeqTable,
+	},
+	{
+		Version: 5,
+		Name:    "create_order_seq",
+		Up:      orders.CreateSeqTable,
+		Down:    orders.DropSeqTable,
+	},
+	{
+		Version: 6,
+		Name:    "create_batch_seq",
+		Up:      batches.CreateSeqTable,
+		Down:    batches.DropSeqTable,
+	},
+}
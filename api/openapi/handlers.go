@@ -12,23 +12,36 @@ import (
 	writers "github.com/PaulioRandall/go-qlueless-api/shared/writers"
 )
 
-var spec map[string]interface{} = nil
-var once sync.Once
-
 var cors uhttp.CorsHeaders = uhttp.CorsHeaders{
 	Origin:  "*",
 	Headers: "*",
 	Methods: "GET, OPTIONS",
 }
 
-// OpenAPIHandler handles requests for the services OpenAPI specification
-func OpenAPIHandler(res http.ResponseWriter, req *http.Request) {
+// Handler handles requests for the services OpenAPI specification. The
+// specification is loaded from file on the first request.
+type Handler struct {
+	path string
+	spec map[string]interface{}
+	once sync.Once
+}
+
+// NewHandler returns a new Handler that serves the OpenAPI specification within
+// the file at 'path'.
+func NewHandler(path string) *Handler {
+	return &Handler{
+		path: path,
+	}
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(res http.ResponseWriter, req *http.Request) {
 	uhttp.LogRequest(req)
 	uhttp.UseCors(&res, &cors)
 
 	switch req.Method {
 	case "GET":
-		get(&res, req)
+		h.get(&res, req)
 	case "OPTIONS":
 		res.WriteHeader(http.StatusOK)
 	default:
@@ -37,10 +50,10 @@ func OpenAPIHandler(res http.ResponseWriter, req *http.Request) {
 }
 
 // get generates responses for obtaining the OpenAPI specification
-func get(res *http.ResponseWriter, req *http.Request) {
-	once.Do(load)
+func (h *Handler) get(res *http.ResponseWriter, req *http.Request) {
+	h.once.Do(h.load)
 
-	if spec == nil {
+	if h.spec == nil {
 		log.Println("[BUG] OpenAPI specification not loaded")
 		writers.WriteServerError(res, req)
 		return
@@ -48,21 +61,20 @@ func get(res *http.ResponseWriter, req *http.Request) {
 
 	uhttp.UseUTF8Json(res, "vnd.oai.openapi")
 	(*res).WriteHeader(http.StatusOK)
-	json.NewEncoder(*res).Encode(spec)
+	json.NewEncoder(*res).Encode(h.spec)
 }
 
 // load loads the OpenAPI specification from a file
-func load() {
-	path := "./openapi.json"
-	bytes, err := ioutil.ReadFile(path)
+func (h *Handler) load() {
+	bytes, err := ioutil.ReadFile(h.path)
 
 	if cookies.LogIfErr(err) {
-		spec = nil
+		h.spec = nil
 		return
 	}
 
-	err = json.Unmarshal(bytes, &spec)
+	err = json.Unmarshal(bytes, &h.spec)
 	if cookies.LogIfErr(err) {
-		spec = nil
+		h.spec = nil
 	}
 }
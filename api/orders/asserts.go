@@ -0,0 +1,53 @@
+package orders
+
+import (
+	"io"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// RequireSliceOfOrders asserts that the value decoded from 'r' is a slice of
+// valid Orders; the slice is returned.
+func RequireSliceOfOrders(t *testing.T, r io.Reader) []Order {
+	o, err := DecodeOrderSlice(r)
+	require.Nil(t, err, "Error decoding slice of orders from reader")
+	AssertOrderSlice(t, o)
+	return o
+}
+
+// RequireOrder asserts that the value decoded from 'r' is a valid Order; the
+// Order is returned.
+func RequireOrder(t *testing.T, r io.Reader) Order {
+	o, err := DecodeOrder(r)
+	require.Nil(t, err, "Error decoding order from reader")
+	AssertOrder(t, o)
+	return o
+}
+
+// AssertOrderSlice asserts that all Orders 'ords' have valid content.
+func AssertOrderSlice(t *testing.T, ords []Order) {
+	for _, o := range ords {
+		AssertOrder(t, o)
+	}
+}
+
+// AssertOrder asserts that an Order 'o' has valid content.
+func AssertOrder(t *testing.T, o Order) {
+	assert.NotEmpty(t, o.ID, "Order.ID")
+	assert.NotEmpty(t, o.Description, "Order.Description")
+	assert.NotEmpty(t, o.State, "Order.State")
+	assert.True(t, o.LastModified > 0, "Order.LastModified")
+}
+
+// AssertOrdersEqual asserts that 'exp' and 'act' contain the same Orders. If
+// 'orderless' is true then the slices are ordered by ID first.
+func AssertOrdersEqual(t *testing.T, exp []Order, act []Order, orderless bool) {
+	if orderless {
+		sort.Sort(ByOrderID(exp))
+		sort.Sort(ByOrderID(act))
+	}
+	assert.Equal(t, exp, act)
+}
@@ -0,0 +1,327 @@
+package orders
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/PaulioRandall/go-cookies/cookies"
+	"github.com/PaulioRandall/go-qlueless-api/api/database"
+)
+
+// CreateTables creates all the Order tables, views and triggers within the
+// supplied database using the SQL dialect 'd'.
+func CreateTables(db database.Executor, d database.Dialect) (err error) {
+	if d == database.Postgres {
+		return createPostgresTables(db)
+	}
+
+	err = createOrderTable(db)
+	if err != nil {
+		return
+	}
+
+	err = createQlOrderTable(db)
+	if err != nil {
+		return
+	}
+
+	err = createInsertOnLivingOrderTrigger(db)
+	if err != nil {
+		return
+	}
+
+	err = createInsertOnDeadOrderTrigger(db)
+	if err != nil {
+		return
+	}
+
+	err = createUpdateOnOrderTrigger(db)
+	if err != nil {
+		return
+	}
+
+	err = createDeleteOnOrderTrigger(db)
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+// DropTables drops all the Order tables, views and triggers from the supplied
+// database using the SQL dialect 'd'. All Order data is lost.
+func DropTables(db database.Executor, d database.Dialect) (err error) {
+	if d == database.Postgres {
+		return dropPostgresTables(db)
+	}
+
+	err = execStmt(db, `DROP TABLE IF EXISTS ql_order;`)
+	if err != nil {
+		return
+	}
+
+	return execStmt(db, `DROP TABLE IF EXISTS "order";`)
+}
+
+// CreateSeqTable creates the table from which new Order IDs are allocated
+// within the supplied database, seeding it with the highest ID in use. The SQL
+// is the same for every dialect so 'd' is ignored.
+func CreateSeqTable(db database.Executor, d database.Dialect) (err error) {
+	err = execStmt(db, `CREATE TABLE order_seq (
+		last_id INTEGER NOT NULL
+	);`)
+	if err != nil {
+		return
+	}
+
+	return execStmt(db, `INSERT INTO order_seq (last_id)
+		SELECT COALESCE(MAX(id), 0)
+		FROM "order";`)
+}
+
+// DropSeqTable drops the table from which new Order IDs are allocated from the
+// supplied database. The SQL is the same for every dialect so 'd' is ignored.
+func DropSeqTable(db database.Executor, d database.Dialect) error {
+	return execStmt(db, `DROP TABLE IF EXISTS order_seq;`)
+}
+
+// createOrderTable creates the Order table within the supplied database.
+func createOrderTable(db database.Executor) error {
+	return execStmt(db, `CREATE TABLE "order" (
+		id INTEGER NOT NULL,
+		last_modified INTEGER NOT NULL DEFAULT(CAST(ROUND((julianday('now') - 2440587.5)*86400000) As INTEGER)),
+		description TEXT NOT NULL,
+		state TEXT NOT NULL,
+		is_dead BOOL NOT NULL DEFAULT FALSE,
+		extra TEXT NOT NULL DEFAULT "",
+		PRIMARY KEY(id, last_modified)
+	);`)
+}
+
+// createQlOrderTable creates the query layer Order table within the supplied
+// database.
+func createQlOrderTable(db database.Executor) error {
+	return execStmt(db, `CREATE TABLE ql_order (
+		id INTEGER NOT NULL PRIMARY KEY,
+		last_modified INTEGER NOT NULL,
+		description TEXT NOT NULL,
+		state TEXT NOT NULL,
+		is_dead BOOL NOT NULL,
+		extra TEXT NOT NULL
+	);`)
+}
+
+// createInsertOnLivingOrderTrigger creates a trigger within the supplied
+// database that updates the ql_order table when ever a new, and living, Order
+// is inserted into the order table.
+func createInsertOnLivingOrderTrigger(db database.Executor) error {
+	return execStmt(db, `CREATE TRIGGER insert_on_living_order
+		AFTER INSERT ON "order"
+		FOR EACH ROW
+		WHEN (NEW.is_dead = false)
+		BEGIN
+			REPLACE INTO ql_order (
+				id, last_modified, description, state, is_dead, extra
+			) VALUES (
+				NEW.id, NEW.last_modified, NEW.description, NEW.state, NEW.is_dead, NEW.extra
+			);
+		END;`)
+}
+
+// createInsertOnDeadOrderTrigger creates a trigger within the supplied
+// database that removes from the ql_order table the dead Order inserted into
+// the order table.
+func createInsertOnDeadOrderTrigger(db database.Executor) error {
+	return execStmt(db, `CREATE TRIGGER insert_on_dead_order
+		AFTER INSERT ON "order"
+		FOR EACH ROW
+		WHEN (NEW.is_dead = true)
+		BEGIN
+			DELETE FROM ql_order
+			WHERE id = NEW.id;
+		END;`)
+}
+
+// createUpdateOnOrderTrigger creates a trigger within the supplied database
+// that raises an error if an update is attempted.
+func createUpdateOnOrderTrigger(db database.Executor) error {
+	return execStmt(db, `CREATE TRIGGER update_on_order
+		BEFORE UPDATE ON "order"
+		BEGIN
+			SELECT RAISE(FAIL, "Updates not allowed, insert with the same Order ID!");
+		END;`)
+}
+
+// createDeleteOnOrderTrigger creates a trigger within the supplied database
+// that raises an error if a delete is attempted.
+func createDeleteOnOrderTrigger(db database.Executor) error {
+	return execStmt(db, `CREATE TRIGGER delete_on_order
+		BEFORE DELETE ON "order"
+		BEGIN
+			SELECT RAISE(FAIL, "Deletions not allowed!");
+		END;`)
+}
+
+// execStmt executes a SQL statment ensuring it is closed afterwards
+func execStmt(db database.Executor, sql string) error {
+	stmt, err := db.Prepare(sql)
+
+	if stmt != nil {
+		defer stmt.Close()
+	}
+
+	if err != nil {
+		return err
+	}
+
+	_, err = stmt.Exec()
+	return err
+}
+
+// QueryFor queries the database for a single Order.
+func QueryFor(db *sql.DB, id string) (*Order, error) {
+	o := Order{}
+	err := db.QueryRow(`SELECT
+		id,
+		last_modified,
+		description,
+		state,
+		extra
+	FROM ql_order
+	WHERE id = $1`, id).Scan(&o.ID,
+		&o.LastModified,
+		&o.Description,
+		&o.State,
+		&o.Extra)
+
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, nil
+	case cookies.LogIfErr(err):
+		return nil, err
+	}
+
+	return &o, nil
+}
+
+// QueryMany queries the database for all specified Orders.
+func QueryMany(db database.Executor, ids []interface{}) ([]Order, error) {
+	posParams := database.Params(1, len(ids))
+	sql := fmt.Sprintf(`SELECT
+			id,
+			last_modified,
+			description,
+			state,
+			extra
+		FROM ql_order
+		WHERE id IN (%s)
+		ORDER BY id ASC`, posParams)
+
+	rows, err := db.Query(sql, ids...)
+
+	if rows != nil {
+		defer rows.Close()
+	}
+
+	if cookies.LogIfErr(err) {
+		return nil, err
+	}
+
+	return mapRows(rows)
+}
+
+// QueryAll queries the database for all Orders.
+func QueryAll(db *sql.DB) ([]Order, error) {
+	rows, err := db.Query(`SELECT
+		id,
+		last_modified,
+		description,
+		state,
+		extra
+	FROM ql_order
+	ORDER BY id ASC`)
+
+	if rows != nil {
+		defer rows.Close()
+	}
+
+	if cookies.LogIfErr(err) {
+		return nil, err
+	}
+
+	return mapRows(rows)
+}
+
+// QueryAllHistory queries the database for every revision, dead or alive, of
+// every Order made at or before 'until'. The revisions are ordered by Order ID
+// then by the time they were made.
+func QueryAllHistory(db *sql.DB, until int64) ([]Order, error) {
+	rows, err := db.Query(`SELECT
+		id,
+		last_modified,
+		description,
+		state,
+		is_dead,
+		extra
+	FROM "order"
+	WHERE last_modified <= $1
+	ORDER BY id ASC, last_modified ASC`, until)
+
+	if rows != nil {
+		defer rows.Close()
+	}
+
+	if cookies.LogIfErr(err) {
+		return nil, err
+	}
+
+	ords := []Order{}
+	for rows.Next() {
+		o := Order{}
+		err = rows.Scan(&o.ID,
+			&o.LastModified,
+			&o.Description,
+			&o.State,
+			&o.Dead,
+			&o.Extra)
+
+		if err != nil {
+			return nil, err
+		}
+		ords = append(ords, o)
+	}
+
+	return ords, nil
+}
+
+// mapRows is a file private function that maps rows from a database query into
+// a slice of Orders.
+func mapRows(rows *sql.Rows) ([]Order, error) {
+	ords := []Order{}
+
+	for rows.Next() {
+		o, err := mapRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		ords = append(ords, *o)
+	}
+
+	return ords, nil
+}
+
+// mapRow is a file private function that maps a single row from a database
+// query into an Order.
+func mapRow(rows *sql.Rows) (*Order, error) {
+	o := Order{}
+	err := rows.Scan(&o.ID,
+		&o.LastModified,
+		&o.Description,
+		&o.State,
+		&o.Extra)
+
+	if err != nil {
+		return nil, err
+	}
+	return &o, err
+}
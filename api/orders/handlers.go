@@ -0,0 +1,162 @@
+package orders
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/PaulioRandall/go-cookies/cookies"
+	"github.com/PaulioRandall/go-cookies/uhttp"
+	"github.com/PaulioRandall/go-qlueless-api/shared/writers"
+)
+
+// cors contains the CORS headers for /orders responses.
+var cors uhttp.CorsHeaders = uhttp.CorsHeaders{
+	Origin:  "*",
+	Headers: "*",
+	Methods: "GET, POST, PUT, DELETE, OPTIONS",
+}
+
+// Handler handles requests to do with collections of, or individual, Orders.
+type Handler struct {
+	db      *sql.DB
+	pruners []Pruner
+}
+
+// NewHandler returns a new Handler that reads and writes Orders within 'db'.
+// Dead Orders are pruned, in turn, from the resources of each Pruner within
+// 'pruners', e.g. the Ventures that reference them and their Batches; each
+// must share the same database.
+func NewHandler(db *sql.DB, pruners ...Pruner) *Handler {
+	return &Handler{
+		db:      db,
+		pruners: pruners,
+	}
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(res http.ResponseWriter, req *http.Request) {
+	uhttp.LogRequest(req)
+	uhttp.UseCors(&res, &cors)
+
+	switch {
+	case req.Method == "GET":
+		get(h.db, &res, req)
+	case req.Method == "POST":
+		post(h.db, &res, req)
+	case req.Method == "PUT":
+		put(h.db, h.pruners, &res, req)
+	case req.Method == "DELETE":
+		del(h.db, h.pruners, &res, req)
+	case req.Method == "OPTIONS":
+		res.WriteHeader(http.StatusOK)
+	default:
+		res.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// get handles client requests for any amount of living Orders.
+func get(db *sql.DB, res *http.ResponseWriter, req *http.Request) {
+
+	ids := req.FormValue("ids")
+	ids = cookies.StripWhitespace(ids)
+	var ords []Order
+
+	if ids != "" {
+		_, ok := idCsvToSlice(ids, res, req)
+		if !ok {
+			return
+		}
+	}
+
+	switch {
+	case ids == "":
+		var err error
+		ords, err = QueryAll(db)
+		if err != nil {
+			writers.WriteServerError(res, req)
+			return
+		}
+	default:
+		var ok bool
+		ords, ok = find(db, ids, res, req)
+		if !ok {
+			return
+		}
+	}
+
+	m := fmt.Sprintf("Found %d Orders", len(ords))
+	writers.WriteSuccessReply(res, req, http.StatusOK, ords, m)
+}
+
+// post handles client requests for creating new Orders.
+func post(db *sql.DB, res *http.ResponseWriter, req *http.Request) {
+	new, ok := decodeNew(res, req)
+	if !ok {
+		return
+	}
+
+	new.Clean()
+	ok = validateNew(&new, res, req)
+	if !ok {
+		return
+	}
+
+	o, ok := insertNew(db, &new, res, req)
+	if !ok {
+		return
+	}
+
+	m := fmt.Sprintf("New Order with ID '%s' created", o.ID)
+	log.Println(m)
+	writers.WriteSuccessReply(res, req, http.StatusCreated, o, m)
+}
+
+// put handles client requests for updating Orders.
+func put(db *sql.DB, pruners []Pruner, res *http.ResponseWriter, req *http.Request) {
+	mo, ok := decodeMod(res, req)
+	if !ok {
+		return
+	}
+
+	mo.Clean()
+	ok = validateMod(mo, res, req)
+	if !ok {
+		return
+	}
+
+	ords, ok := pushMod(db, pruners, mo, res, req)
+	if !ok {
+		return
+	}
+
+	ids := idsToCSV(ords)
+	m := fmt.Sprintf("Updated Orders with the following IDs '%s'", ids)
+	log.Println(m)
+	writers.WriteSuccessReply(res, req, http.StatusOK, ords, m)
+}
+
+// del handles client requests for deleting Orders.
+func del(db *sql.DB, pruners []Pruner, res *http.ResponseWriter, req *http.Request) {
+	ids, ok := idCsvToSlice(req.FormValue("ids"), res, req)
+	if !ok {
+		return
+	}
+
+	ords, ok := pushKill(db, pruners, ids, res, req)
+	if !ok {
+		return
+	}
+
+	m := fmt.Sprintf("Deleted Orders with the following IDs '%s'", idsToCSV(ords))
+	missing := findMissing(ids, ords)
+	if len(missing) > 0 {
+		m += fmt.Sprintf(", the following IDs could not be found '%s'",
+			strings.Join(missing, ", "))
+	}
+
+	log.Println(m)
+	writers.WriteSuccessReply(res, req, http.StatusOK, ords, m)
+}
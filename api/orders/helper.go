@@ -0,0 +1,195 @@
+package orders
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/PaulioRandall/go-cookies/cookies"
+	"github.com/PaulioRandall/go-qlueless-api/api/database"
+	"github.com/PaulioRandall/go-qlueless-api/shared/wrapped"
+	"github.com/PaulioRandall/go-qlueless-api/shared/writers"
+)
+
+// find finds the Orders with the specified IDs.
+func find(db *sql.DB, ids string, res *http.ResponseWriter, req *http.Request) ([]Order, bool) {
+	idSlice := strings.Split(ids, ",")
+	s := make([]interface{}, len(idSlice))
+
+	for i, id := range idSlice {
+		s[i] = id
+	}
+
+	ords, err := QueryMany(db, s)
+
+	if err != nil {
+		writers.WriteServerError(res, req)
+		return nil, false
+	}
+
+	return ords, true
+}
+
+// decodeNew decodes a NewOrder from a Request.Body.
+func decodeNew(res *http.ResponseWriter, req *http.Request) (NewOrder, bool) {
+	o, err := DecodeNewOrder(req.Body)
+	if err != nil {
+		writers.WriteBadRequest(res, req, "Unable to decode request body into an Order")
+		return NewOrder{}, false
+	}
+	return o, true
+}
+
+// validateNew validates a NewOrder that has yet to be assigned an ID.
+func validateNew(o *NewOrder, res *http.ResponseWriter, req *http.Request) bool {
+	r := o.Validate()
+	if len(r) != 0 {
+		writers.WriteInvalid(res, req, r)
+		return false
+	}
+	return true
+}
+
+// insertNew inserts a new Order into the database.
+func insertNew(db *sql.DB, new *NewOrder, res *http.ResponseWriter, req *http.Request) (*Order, bool) {
+	o, ok := new.Insert(db)
+	if !ok {
+		writers.WriteServerError(res, req)
+	}
+	return o, ok
+}
+
+// decodeMod decodes modifications to Orders from a Request.Body.
+func decodeMod(res *http.ResponseWriter, req *http.Request) (*ModOrder, bool) {
+	mo, err := DecodeModOrder(req.Body)
+	if err != nil {
+		writers.WriteBadRequest(res, req,
+			"Unable to decode request body into an Order update")
+		return nil, false
+	}
+	return &mo, true
+}
+
+// validateMod validates an Order update.
+func validateMod(mo *ModOrder, res *http.ResponseWriter, req *http.Request) bool {
+	r := mo.Validate()
+	if len(r) != 0 {
+		writers.WriteInvalid(res, req, r)
+		return false
+	}
+	return true
+}
+
+// idCsvToSlice validates then parses a CSV string of IDs into a slice.
+func idCsvToSlice(idCsv string, res *http.ResponseWriter, req *http.Request) ([]string, bool) {
+	idCsv = cookies.StripWhitespace(idCsv)
+	r := wrapped.Violations{}
+
+	switch {
+	case idCsv == "":
+		r.Add("ids", "required", "Query parameter 'ids' is missing or empty")
+	case !cookies.IsUintCSV(idCsv):
+		r.Add("ids", "invalid", fmt.Sprintf("Could not parse query parameter"+
+			" 'ids=%s' into a list of Order IDs", idCsv))
+	}
+
+	if len(r) != 0 {
+		writers.WriteInvalid(res, req, r)
+		return nil, false
+	}
+
+	ids := strings.Split(idCsv, ",")
+	return ids, true
+}
+
+// idsToCSV returns a CSV string of all Order IDs within the given slice.
+func idsToCSV(ords []Order) string {
+	ids := ""
+	for i, o := range ords {
+		switch i {
+		case 0:
+			ids = o.ID
+		default:
+			ids += ", " + o.ID
+		}
+	}
+	return ids
+}
+
+// pushMod performs the specified modification operation and pushes the result
+// to the database. If the modification sets 'dead' then the dead Orders are
+// pruned by each Pruner within 'pruners' within the same transaction so either
+// all happen or none do.
+func pushMod(db *sql.DB, pruners []Pruner, mo *ModOrder, res *http.ResponseWriter, req *http.Request) ([]Order, bool) {
+	var ords []Order
+
+	err := database.InTx(db, func(tx *sql.Tx) (err error) {
+		ords, err = mo.update(tx)
+		if err != nil || !mo.Sets("dead") {
+			return
+		}
+		return prune(pruners, tx, ords)
+	})
+
+	if cookies.LogIfErr(err) {
+		writers.WriteServerError(res, req)
+		return nil, false
+	}
+
+	return ords, true
+}
+
+// pushKill marks the Orders with the specified IDs as dead by pushing a new
+// revision of each to the database.
+func pushKill(db *sql.DB, pruners []Pruner, ids []string, res *http.ResponseWriter, req *http.Request) ([]Order, bool) {
+	mo := ModOrder{
+		IDs:   strings.Join(ids, ","),
+		Props: "dead",
+		Values: Order{
+			Dead: true,
+		},
+	}
+	return pushMod(db, pruners, &mo, res, req)
+}
+
+// prune prunes the dead Orders within 'ords' using each Pruner within
+// 'pruners', in turn, within the transaction 'tx'.
+func prune(pruners []Pruner, tx *sql.Tx, ords []Order) error {
+	ids := []string{}
+	for _, o := range ords {
+		if o.Dead {
+			ids = append(ids, o.ID)
+		}
+	}
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	for _, p := range pruners {
+		err := p.PruneOrders(tx, ids)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// findMissing returns the IDs within 'ids' that do not belong to any of the
+// Orders within 'ords'.
+func findMissing(ids []string, ords []Order) []string {
+	found := make(map[string]bool, len(ords))
+	for _, o := range ords {
+		found[o.ID] = true
+	}
+
+	missing := []string{}
+	for _, id := range ids {
+		if !found[id] {
+			missing = append(missing, id)
+		}
+	}
+	return missing
+}
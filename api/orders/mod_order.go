@@ -0,0 +1,212 @@
+package orders
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/PaulioRandall/go-cookies/cookies"
+	"github.com/PaulioRandall/go-qlueless-api/api/database"
+	"github.com/PaulioRandall/go-qlueless-api/shared/wrapped"
+)
+
+// ModOrder represents an update to an Order.
+type ModOrder struct {
+	IDs    string `json:"ids"`
+	Props  string `json:"set"`
+	Values Order  `json:"values"`
+}
+
+// DecodeModOrder decodes a ModOrder from data obtained via a Reader.
+func DecodeModOrder(r io.Reader) (ModOrder, error) {
+	var mo ModOrder
+	d := json.NewDecoder(r)
+	err := d.Decode(&mo)
+	return mo, err
+}
+
+// SplitIDs returns the IDs of the Orders to update as a slice.
+func (mo *ModOrder) SplitIDs() []string {
+	if mo.IDs == "" {
+		return []string{}
+	}
+	return strings.Split(mo.IDs, ",")
+}
+
+// SplitProps returns the property names of the properties to update.
+func (mo *ModOrder) SplitProps() []string {
+	if mo.Props == "" {
+		return []string{}
+	}
+	return strings.Split(mo.Props, ",")
+}
+
+// Sets returns true if the property 'prop' is one of the properties to update.
+func (mo *ModOrder) Sets(prop string) bool {
+	for _, p := range mo.SplitProps() {
+		if p == prop {
+			return true
+		}
+	}
+	return false
+}
+
+// Clean cleans up the ModOrder by removing whitespace where applicable.
+func (mo *ModOrder) Clean() {
+	mo.IDs = cookies.StripWhitespace(mo.IDs)
+	mo.Props = cookies.StripWhitespace(mo.Props)
+	mo.Values.Clean()
+}
+
+// validateProps checks the properties declared for change are valid and the
+// property value for each is valid. The Violations found are appended to 'r'.
+func (mo *ModOrder) validateProps(r *wrapped.Violations) {
+	for _, prop := range mo.SplitProps() {
+		switch prop {
+		case "dead", "extra":
+		case "description":
+			if mo.Values.Description == "" {
+				r.Add("values.description", "required", "Orders must have a description.")
+			}
+		case "state":
+			if mo.Values.State == "" {
+				r.Add("values.state", "required", "Orders must have a state.")
+			}
+		default:
+			r.Add("set", "immutable", fmt.Sprintf("Can't update unknown or immutable property '%s'.", prop))
+		}
+	}
+}
+
+// Validate checks each field contains valid content returning the Violations
+// found or an empty slice if all is well. The message of each Violation is
+// suitable for returning to clients.
+func (mo *ModOrder) Validate() wrapped.Violations {
+	r := wrapped.Violations{}
+
+	switch {
+	case mo.IDs == "":
+		r.Add("ids", "required", "'ids' must be supplied so the Orders to update can be determined.")
+	case !cookies.IsUintCSV(mo.IDs):
+		r.Add("ids", "invalid", "'ids' must be a CSV of positive integers.")
+	}
+
+	if mo.Props == "" {
+		r.Add("set", "required", "Some properties must be 'set' for any updating to take place.")
+	}
+
+	mo.validateProps(&r)
+	return r
+}
+
+// ApplyMod applies the modifications to the supplied Order only touching
+// those properties the user has specified
+func (mo *ModOrder) ApplyMod(o *Order) {
+	mod := mo.Values
+	for _, p := range mo.SplitProps() {
+		switch p {
+		case "description":
+			o.Description = mod.Description
+		case "state":
+			o.State = mod.State
+		case "dead":
+			o.Dead = mod.Dead
+		case "extra":
+			o.Extra = mod.Extra
+		}
+	}
+}
+
+// Update pushes the modification of changes to the database within a single
+// transaction.
+func (mo *ModOrder) Update(db *sql.DB) ([]Order, bool) {
+	var ords []Order
+
+	err := database.InTx(db, func(tx *sql.Tx) (err error) {
+		ords, err = mo.update(tx)
+		return
+	})
+
+	if cookies.LogIfErr(err) {
+		return nil, false
+	}
+	return ords, true
+}
+
+// update is a file private function that performs the work of Update() within
+// the transaction 'tx' returning any error encountered. The write lock is taken
+// before the Orders are read so no other writer can push a revision between
+// reading and stamping them.
+func (mo *ModOrder) update(tx *sql.Tx) ([]Order, error) {
+
+	err := lockWrites(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := mo.SplitIDs()
+	args := make([]interface{}, len(ids))
+	for i := range ids {
+		args[i] = ids[i]
+	}
+
+	ords, err := QueryMany(tx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	err = mo.insertEach(tx, ords, cookies.ToUnixMilli(time.Now()))
+	if err != nil {
+		return nil, err
+	}
+
+	return ords, nil
+}
+
+// insertEach is a file private function that performs the actual SQL operation
+// of pushing modifications to the database. Each new revision is stamped with
+// the Unix time 'now' in milliseconds.
+func (mo *ModOrder) insertEach(tx *sql.Tx, ords []Order, now int64) error {
+
+	stmt, err := tx.Prepare(`INSERT INTO "order"
+			(id, last_modified, description, state, is_dead, extra)
+		VALUES
+			($1, $2, $3, $4, $5, $6);`)
+
+	if stmt != nil {
+		defer stmt.Close()
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return mo.execStmtForEach(stmt, ords, now)
+}
+
+// execStmtForEach executes the insert statment provided for each Order
+// provided.
+func (mo *ModOrder) execStmtForEach(stmt *sql.Stmt, ords []Order, now int64) error {
+	for i := range ords {
+
+		o := &ords[i]
+		mo.ApplyMod(o)
+		stamp(o, now)
+
+		_, err := stmt.Exec(o.ID,
+			o.LastModified,
+			o.Description,
+			o.State,
+			o.Dead,
+			o.Extra)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,146 @@
+package orders
+
+import (
+	"database/sql"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/PaulioRandall/go-cookies/cookies"
+	"github.com/PaulioRandall/go-qlueless-api/api/database"
+	"github.com/PaulioRandall/go-qlueless-api/shared/wrapped"
+)
+
+// NewOrder represents a new Order.
+type NewOrder struct {
+	Description string `json:"description"`
+	State       string `json:"state"`
+	Extra       string `json:"extra"`
+}
+
+// DecodeNewOrder decodes a NewOrder from data obtained via a Reader
+func DecodeNewOrder(r io.Reader) (NewOrder, error) {
+	var o NewOrder
+	d := json.NewDecoder(r)
+	err := d.Decode(&o)
+	return o, err
+}
+
+// Clean removes redundent whitespace from property values within an Order
+// except where whitespace is allowable.
+func (no *NewOrder) Clean() {
+	no.Description = strings.TrimSpace(no.Description)
+	no.State = strings.TrimSpace(no.State)
+}
+
+// Validate checks each field contains valid content returning the Violations
+// found or an empty slice if all is well. The message of each Violation is
+// suitable for returning to clients.
+func (no *NewOrder) Validate() wrapped.Violations {
+	r := wrapped.Violations{}
+
+	if no.Description == "" {
+		r.Add("description", "required", "Orders must have a description.")
+	}
+
+	if no.State == "" {
+		r.Add("state", "required", "Orders must have a state.")
+	}
+
+	return r
+}
+
+// Insert inserts the NewOrder into the database. Its ID is allocated within
+// the same transaction so concurrent inserts never share an ID.
+func (no *NewOrder) Insert(db *sql.DB) (o *Order, ok bool) {
+	ok = false
+
+	var id string
+	err := database.InTx(db, func(tx *sql.Tx) error {
+		var err error
+		id, err = nextID(tx)
+		if err != nil {
+			return err
+		}
+
+		stmt, err := tx.Prepare(`INSERT INTO "order" (
+			id, description, state, extra
+		) VALUES (
+			$1, $2, $3, $4
+		);`)
+
+		if stmt != nil {
+			defer stmt.Close()
+		}
+
+		if err != nil {
+			return err
+		}
+
+		_, err = no.execInsert(id, stmt)
+		return err
+	})
+
+	if cookies.LogIfErr(err) {
+		return
+	}
+
+	o, err = QueryFor(db, id)
+	if cookies.LogIfErr(err) {
+		return
+	}
+
+	ok = true
+	return
+}
+
+// lockWrites is a file private function that takes the write lock, within the
+// transaction 'tx', that every transaction modifying Orders must hold. Only one
+// transaction may hold it at a time so reads made after taking it can't be
+// invalidated by another writer before the transaction ends.
+func lockWrites(tx *sql.Tx) error {
+	_, err := tx.Exec(`UPDATE order_seq SET last_id = last_id;`)
+	return err
+}
+
+// nextID is a file private function that allocates the next free Order ID
+// within the transaction 'tx'. Incrementing the sequence takes the same lock
+// as lockWrites() so concurrent transactions wait their turn rather than read
+// the same ID.
+func nextID(tx *sql.Tx) (string, error) {
+	_, err := tx.Exec(`UPDATE order_seq SET last_id = last_id + 1;`)
+	if err != nil {
+		return "", err
+	}
+
+	var id int64
+	err = tx.QueryRow(`SELECT last_id FROM order_seq;`).Scan(&id)
+	if err != nil {
+		return "", err
+	}
+
+	return strconv.FormatInt(id, 10), nil
+}
+
+// execInsert is a file private function that executes the supplied insert
+// statement
+func (no *NewOrder) execInsert(id string, stmt *sql.Stmt) (o *Order, err error) {
+	o = &Order{
+		ID:          id,
+		Description: no.Description,
+		State:       no.State,
+		Extra:       no.Extra,
+	}
+
+	_, err = stmt.Exec(o.ID,
+		o.Description,
+		o.State,
+		o.Extra)
+
+	if err != nil {
+		o = nil
+	}
+
+	return
+}
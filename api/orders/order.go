@@ -0,0 +1,130 @@
+package orders
+
+import (
+	"database/sql"
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/PaulioRandall/go-cookies/cookies"
+	"github.com/PaulioRandall/go-qlueless-api/shared/wrapped"
+)
+
+// Order represents an Order, aka, deliverable.
+type Order struct {
+	ID           string `json:"id,omitempty"`
+	LastModified int64  `json:"last_modified"`
+	Description  string `json:"description"`
+	State        string `json:"state"`
+	Dead         bool   `json:"dead,omitempty"`
+	Extra        string `json:"extra,omitempty"`
+}
+
+// DecodeOrder decodes an Order from data obtained via a Reader.
+func DecodeOrder(r io.Reader) (Order, error) {
+	var o Order
+	d := json.NewDecoder(r)
+	err := d.Decode(&o)
+	return o, err
+}
+
+// DecodeOrderSlice decodes a slice of Orders from data obtained via a Reader.
+func DecodeOrderSlice(r io.Reader) ([]Order, error) {
+	var o []Order
+	d := json.NewDecoder(r)
+	err := d.Decode(&o)
+	if err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// Clean removes redundent whitespace from property values within an Order
+// except where whitespace is allowable.
+func (o *Order) Clean() {
+	o.Description = strings.TrimSpace(o.Description)
+	o.ID = strings.TrimSpace(o.ID)
+	o.State = strings.TrimSpace(o.State)
+}
+
+// Validate checks each field contains valid content returning the Violations
+// found or an empty slice if all is well. The message of each Violation is
+// suitable for returning to clients.
+func (o *Order) Validate(isNew bool) wrapped.Violations {
+	r := wrapped.Violations{}
+
+	if o.Description == "" {
+		r.Add("description", "required", "Orders must have a description.")
+	}
+
+	if !isNew {
+		if !cookies.IsUint(o.ID) {
+			r.Add("id", "invalid", "Orders must have a positive integer ID.")
+		}
+
+		if o.LastModified < 1 {
+			r.Add("last_modified", "invalid", "Orders must have a last modified Unix date in milliseconds")
+		}
+	}
+
+	if o.State == "" {
+		r.Add("state", "required", "Orders must have a state.")
+	}
+
+	return r
+}
+
+// Update updates the Order within the database.
+func (o *Order) Update(db *sql.DB) error {
+	stmt, err := db.Prepare(`INSERT INTO "order" (
+		id, description, state, is_dead, extra
+	) VALUES (
+		$1, $2, $3, $4, $5
+	);`)
+
+	if stmt != nil {
+		defer stmt.Close()
+	}
+
+	if err != nil {
+		return err
+	}
+
+	_, err = stmt.Exec(o.ID,
+		o.Description,
+		o.State,
+		o.Dead,
+		o.Extra)
+
+	return err
+}
+
+// stamp is a file private function that sets the last modified time of the
+// Order 'o', about to become a new revision, to the Unix time 'now' in
+// milliseconds. If the revision it replaces was made at or after 'now' then a
+// millisecond after it is used instead so no two revisions of an Order share a
+// last modified time.
+func stamp(o *Order, now int64) {
+	if now <= o.LastModified {
+		now = o.LastModified + 1
+	}
+	o.LastModified = now
+}
+
+// ByOrderID is a slice of Orders
+type ByOrderID []Order
+
+// Len implements from sort.Interface
+func (bo ByOrderID) Len() int {
+	return len(bo)
+}
+
+// Swap implements from sort.Interface
+func (bo ByOrderID) Swap(i, j int) {
+	bo[i], bo[j] = bo[j], bo[i]
+}
+
+// Less implements from sort.Interface
+func (bo ByOrderID) Less(i, j int) bool {
+	return bo[i].ID < bo[j].ID
+}
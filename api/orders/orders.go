@@ -0,0 +1,8 @@
+// Package orders collects together the handling of all Order specific
+// requests. Functionality in this package is primarily tested using API tests
+// within the /tests directory of this project.
+//
+// An Order is a single deliverable piece of work. Ventures reference the Orders
+// that contribute towards them so work can be tracked at a finer grain than a
+// whole Venture without losing sight of why the work is being done.
+package orders
@@ -0,0 +1,113 @@
+package orders
+
+import (
+	"github.com/PaulioRandall/go-qlueless-api/api/database"
+)
+
+// createPostgresTables creates all the Order tables, functions and triggers
+// within the supplied PostgreSQL database. They behave exactly as their SQLite
+// counterparts do; the order table is append only and the ql_order table holds
+// the latest revision of each living Order.
+func createPostgresTables(db database.Executor) error {
+	stmts := []string{
+		`CREATE TABLE "order" (
+			id INTEGER NOT NULL,
+			last_modified BIGINT NOT NULL DEFAULT (FLOOR(EXTRACT(EPOCH FROM clock_timestamp()) * 1000)::BIGINT),
+			description TEXT NOT NULL,
+			state TEXT NOT NULL,
+			is_dead BOOL NOT NULL DEFAULT FALSE,
+			extra TEXT NOT NULL DEFAULT '',
+			PRIMARY KEY(id, last_modified)
+		);`,
+		`CREATE TABLE ql_order (
+			id INTEGER NOT NULL PRIMARY KEY,
+			last_modified BIGINT NOT NULL,
+			description TEXT NOT NULL,
+			state TEXT NOT NULL,
+			is_dead BOOL NOT NULL,
+			extra TEXT NOT NULL
+		);`,
+		`CREATE FUNCTION upsert_ql_order() RETURNS TRIGGER AS $$
+			BEGIN
+				INSERT INTO ql_order (
+					id, last_modified, description, state, is_dead, extra
+				) VALUES (
+					NEW.id, NEW.last_modified, NEW.description, NEW.state, NEW.is_dead, NEW.extra
+				)
+				ON CONFLICT (id) DO UPDATE SET
+					last_modified = EXCLUDED.last_modified,
+					description = EXCLUDED.description,
+					state = EXCLUDED.state,
+					is_dead = EXCLUDED.is_dead,
+					extra = EXCLUDED.extra;
+				RETURN NULL;
+			END;
+		$$ LANGUAGE plpgsql;`,
+		`CREATE TRIGGER insert_on_living_order
+			AFTER INSERT ON "order"
+			FOR EACH ROW
+			WHEN (NEW.is_dead = false)
+			EXECUTE PROCEDURE upsert_ql_order();`,
+		`CREATE FUNCTION delete_ql_order() RETURNS TRIGGER AS $$
+			BEGIN
+				DELETE FROM ql_order
+				WHERE id = NEW.id;
+				RETURN NULL;
+			END;
+		$$ LANGUAGE plpgsql;`,
+		`CREATE TRIGGER insert_on_dead_order
+			AFTER INSERT ON "order"
+			FOR EACH ROW
+			WHEN (NEW.is_dead = true)
+			EXECUTE PROCEDURE delete_ql_order();`,
+		`CREATE FUNCTION forbid_order_update() RETURNS TRIGGER AS $$
+			BEGIN
+				RAISE EXCEPTION 'Updates not allowed, insert with the same Order ID!';
+			END;
+		$$ LANGUAGE plpgsql;`,
+		`CREATE TRIGGER update_on_order
+			BEFORE UPDATE ON "order"
+			FOR EACH ROW
+			EXECUTE PROCEDURE forbid_order_update();`,
+		`CREATE FUNCTION forbid_order_delete() RETURNS TRIGGER AS $$
+			BEGIN
+				RAISE EXCEPTION 'Deletions not allowed!';
+			END;
+		$$ LANGUAGE plpgsql;`,
+		`CREATE TRIGGER delete_on_order
+			BEFORE DELETE ON "order"
+			FOR EACH ROW
+			EXECUTE PROCEDURE forbid_order_delete();`,
+	}
+
+	for _, s := range stmts {
+		_, err := db.Exec(s)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// dropPostgresTables drops all the Order tables, functions and triggers from
+// the supplied PostgreSQL database.
+func dropPostgresTables(db database.Executor) error {
+	stmts := []string{
+		`DROP TABLE IF EXISTS ql_order;`,
+		`DROP TABLE IF EXISTS "order";`,
+		`DROP FUNCTION IF EXISTS upsert_ql_order();`,
+		`DROP FUNCTION IF EXISTS delete_ql_order();`,
+		`DROP FUNCTION IF EXISTS forbid_order_update();`,
+		`DROP FUNCTION IF EXISTS forbid_order_delete();`,
+	}
+
+	for _, s := range stmts {
+		_, err := db.Exec(s)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,24 @@
+package orders
+
+import (
+	"database/sql"
+)
+
+// Pruner represents a store of resources that reference Orders. When Orders
+// die the resources referencing them are pruned within the same transaction so
+// either both happen or neither do.
+type Pruner interface {
+
+	// PruneOrders prunes the references to the Orders with IDs within
+	// 'orderIDs' within the transaction 'tx'.
+	PruneOrders(tx *sql.Tx, orderIDs []string) error
+}
+
+// PrunerFunc is an adapter allowing an ordinary function to be used as a
+// Pruner.
+type PrunerFunc func(tx *sql.Tx, orderIDs []string) error
+
+// PruneOrders implements Pruner.
+func (f PrunerFunc) PruneOrders(tx *sql.Tx, orderIDs []string) error {
+	return f(tx, orderIDs)
+}
@@ -0,0 +1,64 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/PaulioRandall/go-qlueless-api/api/config"
+)
+
+// corsWriter wraps a ResponseWriter so the 'Access-Control-Allow-Origin'
+// header, set by each handler, is replaced with the configured value just
+// before the response header is written.
+type corsWriter struct {
+	http.ResponseWriter
+	origin  string
+	applied bool
+}
+
+// useCorsOrigins wraps the handler 'h' so responses only allow the origins
+// within the configuration 'cfg'.
+func useCorsOrigins(cfg *config.Config, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		w := &corsWriter{
+			ResponseWriter: res,
+			origin:         cfg.AllowOrigin(req.Header.Get("Origin")),
+		}
+		h.ServeHTTP(w, req)
+	})
+}
+
+// WriteHeader implements http.ResponseWriter.
+func (w *corsWriter) WriteHeader(status int) {
+	w.apply()
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write implements http.ResponseWriter.
+func (w *corsWriter) Write(b []byte) (int, error) {
+	w.apply()
+	return w.ResponseWriter.Write(b)
+}
+
+// apply is a file private function that replaces the allowed origin header,
+// if the handler set one, the first time it is called.
+func (w *corsWriter) apply() {
+	if w.applied {
+		return
+	}
+	w.applied = true
+
+	h := w.Header()
+	if h.Get("Access-Control-Allow-Origin") == "" {
+		return
+	}
+
+	switch w.origin {
+	case "":
+		h.Del("Access-Control-Allow-Origin")
+	case "*":
+		h.Set("Access-Control-Allow-Origin", "*")
+	default:
+		h.Set("Access-Control-Allow-Origin", w.origin)
+		h.Add("Vary", "Origin")
+	}
+}
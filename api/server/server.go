@@ -2,100 +2,174 @@ package server
 
 import (
 	"context"
+	"database/sql"
 	"log"
 	"net"
 	"net/http"
 
+	"github.com/PaulioRandall/go-qlueless-api/api/batches"
 	"github.com/PaulioRandall/go-qlueless-api/api/changelog"
+	"github.com/PaulioRandall/go-qlueless-api/api/config"
 	"github.com/PaulioRandall/go-qlueless-api/api/database"
 	"github.com/PaulioRandall/go-qlueless-api/api/home"
+	"github.com/PaulioRandall/go-qlueless-api/api/metrics"
+	"github.com/PaulioRandall/go-qlueless-api/api/migrate"
 	"github.com/PaulioRandall/go-qlueless-api/api/openapi"
+	"github.com/PaulioRandall/go-qlueless-api/api/orders"
 	"github.com/PaulioRandall/go-qlueless-api/api/ventures"
+	"github.com/PaulioRandall/go-qlueless-api/api/workflow"
 )
 
-var server *http.Server = nil
-var onShutdownHandlerComplete chan bool = make(chan bool)
-
-// init attaches the endpoints to the default server.
-func init() {
-	http.HandleFunc("/", home.HomeHandler)
-	http.HandleFunc("/changelog", changelog.ChangelogHandler)
-	http.HandleFunc("/openapi", openapi.OpenAPIHandler)
-	http.HandleFunc("/ventures", ventures.Handler)
+// Server represents a single instance of the API. Each Server owns its own
+// configuration, database, and routes so many may run within one process.
+type Server struct {
+	cfg  config.Config
+	db   *sql.DB
+	wf   *workflow.Workflow
+	vens ventures.VentureStore
+	http *http.Server
+	ln   net.Listener
+	done chan error
 }
 
-// StartUp initialises and starts the HTTP server, blocking to handle requests
-// if 'async' is true else the function will return once listening has started.
-func StartUp(async bool) {
-	initServer()
-	registerShutdownHandler()
+// New creates a new Server using the configuration 'cfg' opening its database
+// and applying any pending schema migrations. The Server does not accept
+// requests until it is started.
+func New(cfg config.Config) (*Server, error) {
+	log.Println("[Go Qlueless API]: Initialising server")
 
-	log.Println("[Go Qlueless API]: Starting server")
-	database.Open()
-	ln := listen()
+	wf, err := workflow.Load(cfg.WorkflowPath)
+	if err != nil {
+		return nil, err
+	}
 
-	if async {
-		go serve(ln)
-	} else {
-		serve(ln)
+	db, err := database.Open(cfg.DatabaseDSN)
+	if err != nil {
+		return nil, err
 	}
-}
 
-// Shutdown attempts to shutdown the server gracefully.
-func Shutdown() {
-	err := server.Shutdown(context.Background())
-	if err != nil && err != http.ErrServerClosed {
-		panic(err)
+	d := database.DialectOf(cfg.DatabaseDSN)
+	err = migrateUp(db, d)
+	if err != nil {
+		db.Close()
+		return nil, err
 	}
 
-	ok := <-onShutdownHandlerComplete
-	if !ok {
-		panic("Something went wrong while attempting to shutdown")
+	s := &Server{
+		cfg:  cfg,
+		db:   db,
+		wf:   wf,
+		vens: ventures.NewSQLStore(db, d, wf),
+		done: make(chan error, 1),
 	}
+
+	s.http = &http.Server{
+		Addr:    cfg.Addr,
+		Handler: useCorsOrigins(&s.cfg, s.routes()),
+	}
+
+	return s, nil
 }
 
-// initServer creates and initialises the HTTP server.
-func initServer() {
-	log.Println("[Go Qlueless API]: Initialising server")
+// DB returns the database owned by the Server.
+func (s *Server) DB() *sql.DB {
+	return s.db
+}
+
+// Workflow returns the workflow Ventures move through on the Server.
+func (s *Server) Workflow() *workflow.Workflow {
+	return s.wf
+}
 
-	if server != nil {
-		panic("Server already in use")
+// Ventures returns the VentureStore shared by the handlers of the Server.
+func (s *Server) Ventures() ventures.VentureStore {
+	return s.vens
+}
+
+// Addr returns the address the Server is listening on or an empty string if
+// it has not been started. This differs from the configured address when the
+// port was chosen by the OS, i.e. port 0.
+func (s *Server) Addr() string {
+	if s.ln == nil {
+		return ""
+	}
+	return s.ln.Addr().String()
+}
+
+// Start starts listening and handling requests returning once listening has
+// begun. Requests are handled by a separate Go routine until the Server is
+// shutdown, errors while serving are returned by Wait().
+func (s *Server) Start() error {
+	log.Println("[Go Qlueless API]: Starting server")
+
+	ln, err := net.Listen("tcp", s.http.Addr)
+	if err != nil {
+		return err
 	}
+	s.ln = ln
 
-	server = &http.Server{Addr: ":8080"}
+	go s.serve()
+	return nil
 }
 
-// registerShutdownHandler registers a shutdown handler to the current server.
-func registerShutdownHandler() {
-	server.RegisterOnShutdown(func() {
-		assumeTheWorst := false
-		var ok *bool = &assumeTheWorst
-
-		defer func() {
-			onShutdownHandlerComplete <- *ok
-		}()
-
-		log.Println("[Go Qlueless API]: Stopping server")
-		database.Close()
-		server = nil
-		*ok = true
-	})
+// Wait blocks until the Server stops handling requests returning the reason if
+// it was not shutdown.
+func (s *Server) Wait() error {
+	err := <-s.done
+	s.done <- err
+	return err
 }
 
-// listen wraps http.Server.Listen() with any returned errors causing a panic.
-func listen() net.Listener {
-	ln, err := net.Listen("tcp", server.Addr)
+// Shutdown attempts to shutdown the Server gracefully, within the deadline of
+// 'ctx', then closes its database.
+func (s *Server) Shutdown(ctx context.Context) error {
+	log.Println("[Go Qlueless API]: Stopping server")
+
+	err := s.http.Shutdown(ctx)
 	if err != nil {
-		panic(err)
+		s.db.Close()
+		return err
+	}
+
+	return s.db.Close()
+}
+
+// routes creates the multiplexer that routes requests to the handlers of the
+// Server.
+func (s *Server) routes() *http.ServeMux {
+	m := metrics.NewHandler(s.db, s.vens, s.wf)
+	v := ventures.NewHandler(s.vens, s.wf)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", home.HomeHandler)
+	mux.Handle("/changelog", changelog.NewHandler(s.cfg.ChangelogPath))
+	mux.Handle("/openapi", openapi.NewHandler(s.cfg.OpenAPIPath))
+	mux.Handle("/ventures", v)
+	mux.Handle("/ventures/", v)
+	mux.Handle("/orders", orders.NewHandler(s.db, s.vens, orders.PrunerFunc(batches.KillForOrders)))
+	mux.Handle("/batches", batches.NewHandler(s.db))
+	mux.Handle("/workflow", workflow.NewHandler(s.wf))
+	mux.HandleFunc("/metrics/flow", m.Flow)
+	mux.HandleFunc("/metrics/cfd", m.CFD)
+	return mux
+}
+
+// migrateUp is a file private function that applies all pending schema
+// migrations to the database 'db' logging each one applied.
+func migrateUp(db *sql.DB, d database.Dialect) error {
+	done, err := migrate.Up(db, d)
+	for _, m := range done {
+		log.Printf("[Go Qlueless API]: Applied migration %d '%s'\n", m.Version, m.Name)
 	}
-	return ln
+	return err
 }
 
-// serve wraps http.Server.Serve() so that it can be run as a separate Go
-// routine if needed.
-func serve(ln net.Listener) {
-	err := server.Serve(ln.(*net.TCPListener))
-	if err != http.ErrServerClosed {
-		panic(err)
+// serve is a file private function that handles requests until the Server is
+// shutdown.
+func (s *Server) serve() {
+	err := s.http.Serve(s.ln)
+	if err == http.ErrServerClosed {
+		err = nil
 	}
+	s.done <- err
 }
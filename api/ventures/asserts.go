@@ -155,6 +155,31 @@ func AssertWrappedVentureSliceFromReader(t *testing.T, r io.Reader) (wrapped.Wra
 	return wr, v
 }
 
+// AssertConflictFromReader asserts that a problem response decoded from an
+// io.Reader has the required fields populated and holds the current revision
+// of each Venture within its 'current' member
+func AssertConflictFromReader(t *testing.T, r io.Reader) (wrapped.Problem, []Venture) {
+	p, err := wrapped.DecodeProblem(r)
+	require.Nil(t, err)
+	wrapped.AssertGenericProblem(t, p)
+	require.NotEmpty(t, p.Current)
+
+	var v []Venture
+	config := ms.DecoderConfig{
+		TagName: "json",
+		Result:  &v,
+	}
+
+	d, err := ms.NewDecoder(&config)
+	require.Nil(t, err)
+
+	err = d.Decode(p.Current)
+	require.Nil(t, err)
+
+	AssertGenericVentureSlice(t, v)
+	return p, v
+}
+
 // AssertWrappedVentureFromReader asserts that a Venture wrapped within a
 // WrappedReply and decoded from an io.Reader has the required fields populated
 // and in the correct format
@@ -0,0 +1,84 @@
+package ventures
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/PaulioRandall/go-qlueless-api/api/workflow"
+)
+
+// Comparator compares Ventures 'a' and 'b' returning a negative number if 'a'
+// comes first, a positive number if 'b' comes first, else zero.
+type Comparator func(a *Venture, b *Venture) int
+
+// comparators holds the Comparator for each sortable property other than
+// 'state' which depends on the workflow, see comparatorOf().
+var comparators = map[string]Comparator{
+	"id":            CompareID,
+	"last_modified": CompareLastModified,
+	"description":   CompareDescription,
+}
+
+// comparatorOf is a file private function that returns the Comparator of the
+// sortable property 'prop' ordering states as they are within the workflow
+// 'wf'.
+func comparatorOf(wf *workflow.Workflow, prop string) Comparator {
+	if prop == "state" {
+		return CompareState(wf)
+	}
+	return comparators[prop]
+}
+
+// CompareID compares Ventures by ID numerically so '2' comes before '10'.
+func CompareID(a *Venture, b *Venture) int {
+	x, _ := strconv.ParseInt(a.ID, 10, 64)
+	y, _ := strconv.ParseInt(b.ID, 10, 64)
+	return compareInt64(x, y)
+}
+
+// CompareLastModified compares Ventures by the time they were last modified.
+func CompareLastModified(a *Venture, b *Venture) int {
+	return compareInt64(a.LastModified, b.LastModified)
+}
+
+// CompareDescription compares Ventures by description byte-wise, i.e. case
+// sensitive with upper case first.
+func CompareDescription(a *Venture, b *Venture) int {
+	return strings.Compare(a.Description, b.Description)
+}
+
+// CompareState returns a Comparator that compares Ventures by state in the
+// order the states are listed within the workflow 'wf'. States not within the
+// workflow come last ordered byte-wise.
+func CompareState(wf *workflow.Workflow) Comparator {
+	return func(a *Venture, b *Venture) int {
+		x, y := stateRank(wf, a.State), stateRank(wf, b.State)
+
+		if c := compareInt64(int64(x), int64(y)); c != 0 {
+			return c
+		}
+		return strings.Compare(a.State, b.State)
+	}
+}
+
+// stateRank is a file private function that returns the position of 'state'
+// within the workflow 'wf' or, if it's not within the workflow, the number of
+// states.
+func stateRank(wf *workflow.Workflow, state string) int {
+	if i := wf.Index(state); i != -1 {
+		return i
+	}
+	return len(wf.States)
+}
+
+// compareInt64 is a file private function that compares 'a' and 'b' returning
+// -1, 0, or 1.
+func compareInt64(a int64, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,60 @@
+package ventures
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ConflictError is returned when a modification expects revisions of Ventures
+// other than the latest, i.e. someone else modified them first. Nothing is
+// modified when it is returned.
+type ConflictError struct {
+
+	// Precondition is true if the 'If-Match' ETag of the modification did not
+	// match rather than the expected last modified time of a Venture.
+	Precondition bool
+
+	// Stale holds the IDs of the Ventures whose expected last modified time
+	// did not match.
+	Stale []string
+
+	// Current holds the latest revision of each Venture being modified.
+	Current []Venture
+}
+
+// Error implements error.
+func (e *ConflictError) Error() string {
+	if e.Precondition {
+		return "The 'If-Match' ETag does not match the current revisions of the Ventures"
+	}
+	return fmt.Sprintf("The following Ventures have been modified since they"+
+		" were last read '%s'", strings.Join(e.Stale, ", "))
+}
+
+// ETag returns the strong entity tag representing the revisions of the
+// Ventures within 'vens'. The order of 'vens' does not matter, the ETag only
+// changes when the set of Ventures or the revision of any of them changes.
+func ETag(vens []Venture) string {
+	revs := make([]string, len(vens))
+	for i, ven := range vens {
+		revs[i] = fmt.Sprintf("%s:%d", ven.ID, ven.LastModified)
+	}
+	sort.Strings(revs)
+
+	sum := sha1.Sum([]byte(strings.Join(revs, ",")))
+	return fmt.Sprintf(`"%x"`, sum)
+}
+
+// matchesETag returns true if the value of an 'If-Match' header, 'ifMatch',
+// matches the ETag 'etag'. 'ifMatch' may be '*' or a CSV of ETags.
+func matchesETag(ifMatch string, etag string) bool {
+	for _, t := range strings.Split(ifMatch, ",") {
+		t = strings.TrimSpace(t)
+		if t == "*" || t == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,102 @@
+package ventures
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/PaulioRandall/go-cookies/cookies"
+)
+
+// DefaultLimit is the number of Ventures within a page when a Cursor is given
+// without a limit.
+const DefaultLimit = 100
+
+// Cursor represents a position between two living Ventures ordered by last
+// modified time then ID. Paginating by both keeps pages stable while
+// Ventures are written since a modified Venture moves to the end rather than
+// shifting those around it.
+//
+// The page starts after the Venture identified unless Before is true in which
+// case it ends before it.
+type Cursor struct {
+	LastModified int64
+	ID           string
+	Before       bool
+}
+
+// After returns the Cursor that starts a page after the Venture 'ven'.
+func After(ven *Venture) Cursor {
+	return Cursor{
+		LastModified: ven.LastModified,
+		ID:           ven.ID,
+	}
+}
+
+// Before returns the Cursor that ends a page before the Venture 'ven'.
+func Before(ven *Venture) Cursor {
+	return Cursor{
+		LastModified: ven.LastModified,
+		ID:           ven.ID,
+		Before:       true,
+	}
+}
+
+// Reverse returns the Cursor at the same position as 'c' but facing the
+// opposite direction.
+func (c Cursor) Reverse() Cursor {
+	c.Before = !c.Before
+	return c
+}
+
+// Encode returns the Cursor as an opaque URL safe string; clients should not
+// rely on its content.
+func (c Cursor) Encode() string {
+	dir := "a"
+	if c.Before {
+		dir = "b"
+	}
+
+	s := fmt.Sprintf("%s:%d:%s", dir, c.LastModified, c.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(s))
+}
+
+// DecodeCursor decodes a Cursor previously encoded by Cursor.Encode().
+func DecodeCursor(s string) (Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, err
+	}
+
+	parts := strings.Split(string(b), ":")
+	if len(parts) != 3 || (parts[0] != "a" && parts[0] != "b") || !cookies.IsUint(parts[2]) {
+		return Cursor{}, errors.New("Malformed cursor")
+	}
+
+	lm, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return Cursor{}, err
+	}
+
+	return Cursor{
+		LastModified: lm,
+		ID:           parts[2],
+		Before:       parts[0] == "b",
+	}, nil
+}
+
+// Includes returns true if the Venture 'ven' is on the side of the Cursor the
+// page is, ignoring the page size.
+func (c Cursor) Includes(ven *Venture) bool {
+	cmp := compareInt64(ven.LastModified, c.LastModified)
+	if cmp == 0 {
+		cmp = CompareID(ven, &Venture{ID: c.ID})
+	}
+
+	if c.Before {
+		return cmp < 0
+	}
+	return cmp > 0
+}
@@ -1,43 +1,42 @@
 package ventures
 
 import (
-	"database/sql"
-	"fmt"
-	"strings"
-
-	"github.com/PaulioRandall/go-cookies/cookies"
 	"github.com/PaulioRandall/go-qlueless-api/api/database"
 )
 
 // CreateTables creates all the Venture tables, views and triggers within the
-// supplied database.
-func CreateTables() (err error) {
-	err = createVentureTable()
+// supplied database using the SQL dialect 'd'.
+func CreateTables(db database.Executor, d database.Dialect) (err error) {
+	if d == database.Postgres {
+		return createPostgresTables(db)
+	}
+
+	err = createVentureTable(db)
 	if err != nil {
 		return
 	}
 
-	err = createQlVentureTable()
+	err = createQlVentureTable(db)
 	if err != nil {
 		return
 	}
 
-	err = createInsertOnLivingVentureTrigger()
+	err = createInsertOnLivingVentureTrigger(db)
 	if err != nil {
 		return
 	}
 
-	err = createInsertOnDeadVentureTrigger()
+	err = createInsertOnDeadVentureTrigger(db)
 	if err != nil {
 		return
 	}
 
-	err = createUpdateOnVentureTrigger()
+	err = createUpdateOnVentureTrigger(db)
 	if err != nil {
 		return
 	}
 
-	err = createDeleteOnVentureTrigger()
+	err = createDeleteOnVentureTrigger(db)
 	if err != nil {
 		return
 	}
@@ -45,9 +44,47 @@ func CreateTables() (err error) {
 	return
 }
 
+// DropTables drops all the Venture tables, views and triggers from the supplied
+// database using the SQL dialect 'd'. All Venture data is lost.
+func DropTables(db database.Executor, d database.Dialect) (err error) {
+	if d == database.Postgres {
+		return dropPostgresTables(db)
+	}
+
+	err = execStmt(db, `DROP TABLE IF EXISTS ql_venture;`)
+	if err != nil {
+		return
+	}
+
+	return execStmt(db, `DROP TABLE IF EXISTS venture;`)
+}
+
+// CreateSeqTable creates the table from which new Venture IDs are allocated
+// within the supplied database, seeding it with the highest ID in use. The SQL
+// is the same for every dialect so 'd' is ignored.
+func CreateSeqTable(db database.Executor, d database.Dialect) (err error) {
+	err = execStmt(db, `CREATE TABLE venture_seq (
+		last_id INTEGER NOT NULL
+	);`)
+	if err != nil {
+		return
+	}
+
+	return execStmt(db, `INSERT INTO venture_seq (last_id)
+		SELECT COALESCE(MAX(id), 0)
+		FROM venture;`)
+}
+
+// DropSeqTable drops the table from which new Venture IDs are allocated from
+// the supplied database. The SQL is the same for every dialect so 'd' is
+// ignored.
+func DropSeqTable(db database.Executor, d database.Dialect) error {
+	return execStmt(db, `DROP TABLE IF EXISTS venture_seq;`)
+}
+
 // createVentureTable creates the Venture table within the supplied database.
-func createVentureTable() error {
-	return execStmt(`CREATE TABLE venture (
+func createVentureTable(db database.Executor) error {
+	return execStmt(db, `CREATE TABLE venture (
 		id INTEGER NOT NULL,
 		last_modified INTEGER NOT NULL DEFAULT(CAST(ROUND((julianday('now') - 2440587.5)*86400000) As INTEGER)),
 		description TEXT NOT NULL,
@@ -61,8 +98,8 @@ func createVentureTable() error {
 
 // createQlVentureTable creates the query layer Venture table within the
 // supplied database.
-func createQlVentureTable() error {
-	return execStmt(`CREATE TABLE ql_venture (
+func createQlVentureTable(db database.Executor) error {
+	return execStmt(db, `CREATE TABLE ql_venture (
 		id INTEGER NOT NULL PRIMARY KEY,
 		last_modified INTEGER NOT NULL,
 		description TEXT NOT NULL,
@@ -76,8 +113,8 @@ func createQlVentureTable() error {
 // createInsertOnLivingVentureTrigger creates a trigger within the
 // supplied database that updates the ql_venture table when ever a new, and
 // living, Venture is inserted into the venture table.
-func createInsertOnLivingVentureTrigger() error {
-	return execStmt(`CREATE TRIGGER insert_on_living_venture
+func createInsertOnLivingVentureTrigger(db database.Executor) error {
+	return execStmt(db, `CREATE TRIGGER insert_on_living_venture
 		AFTER INSERT ON venture
 		FOR EACH ROW
 		WHEN (NEW.is_dead = false)
@@ -93,8 +130,8 @@ func createInsertOnLivingVentureTrigger() error {
 // createInsertOnDeadVentureTrigger creates a trigger within the supplied
 // database that removes from the ql_venture table the dead Venture inserted
 // into the venture table.
-func createInsertOnDeadVentureTrigger() error {
-	return execStmt(`CREATE TRIGGER insert_on_dead_venture
+func createInsertOnDeadVentureTrigger(db database.Executor) error {
+	return execStmt(db, `CREATE TRIGGER insert_on_dead_venture
 		AFTER INSERT ON venture
 		FOR EACH ROW
 		WHEN (NEW.is_dead = true)
@@ -106,8 +143,8 @@ func createInsertOnDeadVentureTrigger() error {
 
 // createUpdateOnVentureTrigger creates a trigger within the supplied
 // database that raises an error if an update is attempted.
-func createUpdateOnVentureTrigger() error {
-	return execStmt(`CREATE TRIGGER update_on_venture
+func createUpdateOnVentureTrigger(db database.Executor) error {
+	return execStmt(db, `CREATE TRIGGER update_on_venture
 		BEFORE UPDATE ON venture
 		BEGIN
 			SELECT RAISE(FAIL, "Updates not allowed, insert with the same Venture ID!");
@@ -116,8 +153,8 @@ func createUpdateOnVentureTrigger() error {
 
 // createDeleteOnVentureTrigger creates a trigger within the supplied
 // database that raises an error if a delete is attempted.
-func createDeleteOnVentureTrigger() error {
-	return execStmt(`CREATE TRIGGER delete_on_venture
+func createDeleteOnVentureTrigger(db database.Executor) error {
+	return execStmt(db, `CREATE TRIGGER delete_on_venture
 		BEFORE DELETE ON venture
 		BEGIN
 			SELECT RAISE(FAIL, "Deletions not allowed!");
@@ -125,8 +162,8 @@ func createDeleteOnVentureTrigger() error {
 }
 
 // execStmt executes a SQL statment ensuring it is closed afterwards
-func execStmt(sql string) error {
-	stmt, err := database.Get().Prepare(sql)
+func execStmt(db database.Executor, sql string) error {
+	stmt, err := db.Prepare(sql)
 
 	if stmt != nil {
 		defer stmt.Close()
@@ -139,112 +176,3 @@ func execStmt(sql string) error {
 	_, err = stmt.Exec()
 	return err
 }
-
-// QueryFor queries the database for a single Venture.
-func QueryFor(id string) (*Venture, error) {
-	ven := Venture{}
-	err := database.Get().QueryRow(`SELECT
-		id,
-		last_modified,
-		description,
-		order_ids,
-		state,
-		extra
-	FROM ql_venture
-	WHERE id = ?`, id).Scan(&ven.ID,
-		&ven.LastModified,
-		&ven.Description,
-		&ven.Orders,
-		&ven.State,
-		&ven.Extra)
-
-	switch {
-	case err == sql.ErrNoRows:
-		return nil, nil
-	case cookies.LogIfErr(err):
-		return nil, err
-	}
-
-	return &ven, nil
-}
-
-// QueryMany queries the database for all specified Ventures.
-func QueryMany(ids []interface{}) ([]Venture, error) {
-	posParams := strings.Repeat(",?", len(ids))[1:]
-	sql := fmt.Sprintf(`SELECT
-			id,
-			last_modified,
-			description,
-			order_ids,
-			state,
-			extra
-		FROM ql_venture
-		WHERE id IN (%s)`, posParams)
-
-	rows, err := database.Get().Query(sql, ids...)
-
-	if rows != nil {
-		defer rows.Close()
-	}
-
-	if cookies.LogIfErr(err) {
-		return nil, err
-	}
-
-	return mapRows(rows)
-}
-
-// QueryAll queries the database for all Ventures.
-func QueryAll() ([]Venture, error) {
-	rows, err := database.Get().Query(`SELECT
-		id,
-		last_modified,
-		description,
-		order_ids,
-		state,
-		extra
-	FROM ql_venture`)
-
-	if rows != nil {
-		defer rows.Close()
-	}
-
-	if cookies.LogIfErr(err) {
-		return nil, err
-	}
-
-	return mapRows(rows)
-}
-
-// mapRows is a file private function that maps rows from a database query into
-// a slice of Ventures.
-func mapRows(rows *sql.Rows) ([]Venture, error) {
-	vens := []Venture{}
-
-	for rows.Next() {
-		ven, err := mapRow(rows)
-		if err != nil {
-			return nil, err
-		}
-		vens = append(vens, *ven)
-	}
-
-	return vens, nil
-}
-
-// mapRow is a file private function that maps a single row from a database
-// query into a Venture.
-func mapRow(rows *sql.Rows) (*Venture, error) {
-	ven := Venture{}
-	err := rows.Scan(&ven.ID,
-		&ven.LastModified,
-		&ven.Description,
-		&ven.Orders,
-		&ven.State,
-		&ven.Extra)
-
-	if err != nil {
-		return nil, err
-	}
-	return &ven, err
-}
@@ -3,10 +3,14 @@ package ventures
 import (
 	"fmt"
 	"log"
+	"math"
 	"net/http"
+	"strings"
 
 	"github.com/PaulioRandall/go-cookies/cookies"
 	"github.com/PaulioRandall/go-cookies/uhttp"
+	"github.com/PaulioRandall/go-qlueless-api/api/workflow"
+	"github.com/PaulioRandall/go-qlueless-api/shared/wrapped"
 	"github.com/PaulioRandall/go-qlueless-api/shared/writers"
 )
 
@@ -17,18 +21,54 @@ var cors uhttp.CorsHeaders = uhttp.CorsHeaders{
 	Methods: "GET, POST, PUT, DELETE, OPTIONS",
 }
 
+// itemCors contains the CORS headers for /Venture/{id} responses.
+var itemCors uhttp.CorsHeaders = uhttp.CorsHeaders{
+	Origin:  "*",
+	Headers: "*",
+	Methods: "GET, PATCH, DELETE, OPTIONS",
+}
+
+// acceptPatch is the value of the 'Accept-Patch' header listing the media
+// types of the patches /Venture/{id} accepts.
+var acceptPatch = MergePatchType + ", " + JSONPatchType
+
 // Handler handles requests to do with collections of, or individual, Ventures.
-func Handler(res http.ResponseWriter, req *http.Request) {
+type Handler struct {
+	store VentureStore
+	wf    *workflow.Workflow
+}
+
+// NewHandler returns a new Handler that reads and writes Ventures within
+// 'store' moving them through the workflow 'wf'.
+func NewHandler(store VentureStore, wf *workflow.Workflow) *Handler {
+	return &Handler{
+		store: store,
+		wf:    wf,
+	}
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(res http.ResponseWriter, req *http.Request) {
 	uhttp.LogRequest(req)
+
+	id := strings.Trim(strings.TrimPrefix(req.URL.Path, "/ventures"), "/")
+	if id != "" {
+		h.serveItem(id, res, req)
+		return
+	}
+
 	uhttp.UseCors(&res, &cors)
+	res.Header().Set("Access-Control-Expose-Headers", "ETag, Link")
 
 	switch {
 	case req.Method == "GET":
-		get(&res, req)
+		get(h.store, h.wf, &res, req)
 	case req.Method == "POST":
-		post(&res, req)
+		post(h.store, h.wf, &res, req)
 	case req.Method == "PUT":
-		put(&res, req)
+		put(h.store, h.wf, &res, req)
+	case req.Method == "DELETE":
+		del(h.store, &res, req)
 	case req.Method == "OPTIONS":
 		res.WriteHeader(http.StatusOK)
 	default:
@@ -36,47 +76,164 @@ func Handler(res http.ResponseWriter, req *http.Request) {
 	}
 }
 
-// get handles client requests for any amount of living Ventures.
-func get(res *http.ResponseWriter, req *http.Request) {
+// serveItem handles requests to do with the individual Venture with the ID
+// 'id'.
+func (h *Handler) serveItem(id string, res http.ResponseWriter, req *http.Request) {
+	uhttp.UseCors(&res, &itemCors)
+	res.Header().Set("Access-Control-Expose-Headers", "ETag")
+	res.Header().Set("Accept-Patch", acceptPatch)
 
-	ids := req.FormValue("ids")
-	ids = cookies.StripWhitespace(ids)
-	var vens []Venture
+	if !cookies.IsUint(id) {
+		writers.WriteNotFound(&res, req, fmt.Sprintf("'%s' is not a Venture ID", id))
+		return
+	}
 
 	switch {
-	case ids == "":
-		var err error
-		vens, err = QueryAll()
-		if err != nil {
-			writers.WriteServerError(res, req)
-			return
-		}
+	case req.Method == "GET":
+		getOne(h.store, id, &res, req)
+	case req.Method == "PATCH":
+		patchOne(h.store, h.wf, id, &res, req)
+	case req.Method == "DELETE":
+		delOne(h.store, id, &res, req)
+	case req.Method == "OPTIONS":
+		res.WriteHeader(http.StatusOK)
 	default:
-		var ok bool
-		vens, ok = find(ids, res, req)
+		res.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// get handles client requests for any amount of living Ventures.
+func get(s VentureStore, wf *workflow.Workflow, res *http.ResponseWriter, req *http.Request) {
+
+	if req.URL.Query()["history"] != nil {
+		getHistory(s, res, req)
+		return
+	}
+
+	if req.FormValue("as_of") != "" {
+		getAsOf(s, res, req)
+		return
+	}
+
+	r := wrapped.Violations{}
+	q := parseQuery(wf, req, &r)
+	fields := parseFields(req, &r)
+	if len(r) != 0 {
+		writers.WriteInvalid(res, req, r)
+		return
+	}
+
+	strict, ok := parseStrict(false, res, req)
+	if !ok {
+		return
+	}
+
+	var vens []Venture
+	var page wrapped.Page
+	if q.Paged() {
+		vens, page, ok = findPage(s, q, res, req)
+	} else {
+		vens, ok = find(s, q, res, req)
+	}
+
+	if !ok {
+		return
+	}
+
+	missing, ok := findMissingOf(s, q, vens, res, req)
+	if !ok {
+		return
+	}
+
+	if strict && len(missing) > 0 {
+		writeNotFound(&NotFoundError{IDs: missing}, res, req)
+		return
+	}
+
+	m := fmt.Sprintf("Found %d Ventures", len(vens))
+	if len(missing) > 0 {
+		m += fmt.Sprintf(", the following IDs could not be found '%s'",
+			strings.Join(missing, ", "))
+	}
+
+	(*res).Header().Set("ETag", ETag(vens))
+	if q.Paged() {
+		writers.WritePagedReply(res, req, http.StatusOK, project(vens, fields), m, page)
+		return
+	}
+	writers.WriteSuccessReply(res, req, http.StatusOK, project(vens, fields), m)
+}
+
+// getHistory handles client requests for the revision history of Ventures.
+func getHistory(s VentureStore, res *http.ResponseWriter, req *http.Request) {
+	ids, ok := idCsvToSlice(req.FormValue("ids"), res, req)
+	if !ok {
+		return
+	}
+
+	since, ok := parseMillis("since", 0, res, req)
+	if !ok {
+		return
+	}
+
+	until, ok := parseMillis("until", math.MaxInt64, res, req)
+	if !ok {
+		return
+	}
+
+	vens, ok := findHistory(s, ids, since, until, res, req)
+	if !ok {
+		return
+	}
+
+	m := fmt.Sprintf("Found %d Venture revisions", len(vens))
+	writers.WriteSuccessReply(res, req, http.StatusOK, vens, m)
+}
+
+// getAsOf handles client requests for living Ventures as they were at a
+// specific point in time.
+func getAsOf(s VentureStore, res *http.ResponseWriter, req *http.Request) {
+	asOf, ok := parseMillis("as_of", 0, res, req)
+	if !ok {
+		return
+	}
+
+	ids := []string{}
+	if cookies.StripWhitespace(req.FormValue("ids")) != "" {
+		ids, ok = idCsvToSlice(req.FormValue("ids"), res, req)
 		if !ok {
 			return
 		}
 	}
 
-	m := fmt.Sprintf("Found %d Ventures", len(vens))
+	vens, ok := findAsOf(s, ids, asOf, res, req)
+	if !ok {
+		return
+	}
+
+	m := fmt.Sprintf("Found %d Ventures as of %d", len(vens), asOf)
 	writers.WriteSuccessReply(res, req, http.StatusOK, vens, m)
 }
 
 // post handles client requests for creating new Ventures.
-func post(res *http.ResponseWriter, req *http.Request) {
+func post(s VentureStore, wf *workflow.Workflow, res *http.ResponseWriter, req *http.Request) {
+	if isArrayBody(req) {
+		postMany(s, wf, res, req)
+		return
+	}
+
 	new, ok := decodeNew(res, req)
 	if !ok {
 		return
 	}
 
-	new.Clean()
-	ok = validateNew(&new, res, req)
+	new.Clean(wf)
+	ok = validateNew(wf, &new, res, req)
 	if !ok {
 		return
 	}
 
-	ven, ok := insertNew(&new, res, req)
+	ven, ok := insertNew(s, &new, res, req)
 	if !ok {
 		return
 	}
@@ -86,26 +243,162 @@ func post(res *http.ResponseWriter, req *http.Request) {
 	writers.WriteSuccessReply(res, req, http.StatusCreated, ven, m)
 }
 
+// postMany handles client requests for creating many new Ventures at once.
+func postMany(s VentureStore, wf *workflow.Workflow, res *http.ResponseWriter, req *http.Request) {
+	news, ok := decodeNewSlice(res, req)
+	if !ok {
+		return
+	}
+
+	for i := range news {
+		news[i].Clean(wf)
+	}
+
+	ok = validateNewSlice(wf, news, res, req)
+	if !ok {
+		return
+	}
+
+	vens, ok := insertNewSlice(s, news, res, req)
+	if !ok {
+		return
+	}
+
+	m := fmt.Sprintf("New Ventures with the following IDs created '%s'", idsToCSV(vens))
+	log.Println(m)
+	writers.WriteSuccessReply(res, req, http.StatusCreated, vens, m)
+}
+
 // put handles client requests for updating Ventures.
-func put(res *http.ResponseWriter, req *http.Request) {
+func put(s VentureStore, wf *workflow.Workflow, res *http.ResponseWriter, req *http.Request) {
 	mv, ok := decodeMod(res, req)
 	if !ok {
 		return
 	}
 
-	mv.Clean()
-	ok = validateMod(mv, res, req)
+	mv.IfMatch = req.Header.Get("If-Match")
+	mv.Strict, ok = parseStrict(true, res, req)
+	if !ok {
+		return
+	}
+
+	mv.Clean(wf)
+	ok = validateMod(wf, mv, res, req)
 	if !ok {
 		return
 	}
 
-	vens, ok := pushMod(mv, res, req)
+	vens, ok := pushMod(s, mv, "values.", res, req)
 	if !ok {
 		return
 	}
 
 	ids := idsToCSV(vens)
 	m := fmt.Sprintf("Updated Ventures with the following IDs '%s'", ids)
+	missing := findMissing(mv.SplitIDs(), vens)
+	if len(missing) > 0 {
+		m += fmt.Sprintf(", the following IDs could not be found '%s'",
+			strings.Join(missing, ", "))
+	}
+
+	log.Println(m)
+	(*res).Header().Set("ETag", ETag(vens))
+	writers.WriteSuccessReply(res, req, http.StatusOK, vens, m)
+}
+
+// del handles client requests for deleting Ventures.
+func del(s VentureStore, res *http.ResponseWriter, req *http.Request) {
+	ids, ok := idCsvToSlice(req.FormValue("ids"), res, req)
+	if !ok {
+		return
+	}
+
+	vens, ok := pushKill(s, ids, res, req)
+	if !ok {
+		return
+	}
+
+	m := fmt.Sprintf("Deleted Ventures with the following IDs '%s'", idsToCSV(vens))
+	missing := findMissing(ids, vens)
+	if len(missing) > 0 {
+		m += fmt.Sprintf(", the following IDs could not be found '%s'",
+			strings.Join(missing, ", "))
+	}
+
 	log.Println(m)
 	writers.WriteSuccessReply(res, req, http.StatusOK, vens, m)
 }
+
+// getOne handles client requests for a single living Venture.
+func getOne(s VentureStore, id string, res *http.ResponseWriter, req *http.Request) {
+	ven, ok := findOne(s, id, res, req)
+	if !ok {
+		return
+	}
+
+	m := fmt.Sprintf("Found Venture '%s'", id)
+	(*res).Header().Set("ETag", ETag([]Venture{*ven}))
+	writers.WriteSuccessReply(res, req, http.StatusOK, ven, m)
+}
+
+// patchOne handles client requests for patching a single living Venture. The
+// patch is applied to the latest revision of the Venture and the changes it
+// makes stored as a new revision.
+func patchOne(s VentureStore, wf *workflow.Workflow, id string, res *http.ResponseWriter, req *http.Request) {
+	mediaType, ok := parsePatchType(res, req)
+	if !ok {
+		return
+	}
+
+	ven, ok := findOne(s, id, res, req)
+	if !ok {
+		return
+	}
+
+	mv, r, ok := decodePatch(ven, mediaType, res, req)
+	if !ok {
+		return
+	}
+
+	mv.Clean(wf)
+	ok = validatePatch(wf, mv, r, res, req)
+	if !ok {
+		return
+	}
+
+	if mv.Props == "" {
+		m := fmt.Sprintf("Venture '%s' is unchanged", id)
+		(*res).Header().Set("ETag", ETag([]Venture{*ven}))
+		writers.WriteSuccessReply(res, req, http.StatusOK, ven, m)
+		return
+	}
+
+	mv.IfMatch = req.Header.Get("If-Match")
+
+	vens, ok := pushMod(s, mv, "", res, req)
+	if !ok {
+		return
+	}
+
+	m := fmt.Sprintf("Patched Venture '%s'", id)
+	log.Println(m)
+	(*res).Header().Set("ETag", ETag(vens))
+	writers.WriteSuccessReply(res, req, http.StatusOK, vens[0], m)
+}
+
+// delOne handles client requests for deleting a single Venture.
+func delOne(s VentureStore, id string, res *http.ResponseWriter, req *http.Request) {
+	vens, ok := pushKill(s, []string{id}, res, req)
+	if !ok {
+		return
+	}
+
+	if len(vens) == 0 {
+		writeNotFound(&NotFoundError{IDs: []string{id}}, res, req)
+		return
+	}
+
+	m := fmt.Sprintf("Deleted Venture '%s'", id)
+	log.Println(m)
+	writers.WriteSuccessReply(res, req, http.StatusOK, vens[0], m)
+}
@@ -1,25 +1,146 @@
 package ventures
 
 import (
+	"bytes"
 	"fmt"
+	"io/ioutil"
+	"mime"
 	"net/http"
+	"strconv"
 	"strings"
+	"unicode"
 
 	"github.com/PaulioRandall/go-cookies/cookies"
+	"github.com/PaulioRandall/go-qlueless-api/api/workflow"
+	"github.com/PaulioRandall/go-qlueless-api/shared/patch"
+	"github.com/PaulioRandall/go-qlueless-api/shared/wrapped"
 	"github.com/PaulioRandall/go-qlueless-api/shared/writers"
 )
 
 // find finds the Ventures with the specified IDs.
-func find(ids string, res *http.ResponseWriter, req *http.Request) ([]Venture, bool) {
-	idSlice := strings.Split(ids, ",")
-	s := make([]interface{}, len(idSlice))
+func find(s VentureStore, q *Query, res *http.ResponseWriter, req *http.Request) ([]Venture, bool) {
+	vens, err := s.Find(q)
+	if err != nil {
+		writers.WriteServerError(res, req)
+		return nil, false
+	}
+
+	return vens, true
+}
 
-	for i, id := range idSlice {
-		s[i] = id
+// findPage finds the page of living Ventures identified by the paged Query
+// 'q' returning the links to the adjacent pages. The total number of Ventures
+// across every page is only counted if the client requested it be wrapped.
+func findPage(s VentureStore, q *Query, res *http.ResponseWriter, req *http.Request) ([]Venture, wrapped.Page, bool) {
+	page := wrapped.Page{}
+	if q.Limit == 0 {
+		q.Limit = DefaultLimit
 	}
 
-	vens, err := QueryMany(s)
+	probe := *q
+	probe.Limit++
 
+	vens, ok := find(s, &probe, res, req)
+	if !ok {
+		return nil, page, false
+	}
+
+	backwards := q.Cursor != nil && q.Cursor.Before
+	more := len(vens) > q.Limit
+
+	switch {
+	case more && backwards:
+		vens = vens[1:]
+	case more:
+		vens = vens[:q.Limit]
+	}
+
+	hasNext, hasPrev := more, q.Cursor != nil
+	if backwards {
+		hasNext, hasPrev = true, more
+	}
+
+	// An empty page can only be found beyond a Cursor so, if the page is empty,
+	// the adjacent pages are found from the Cursor itself.
+	if hasNext {
+		var next Cursor
+		if len(vens) > 0 {
+			next = After(&vens[len(vens)-1])
+		} else {
+			next = q.Cursor.Reverse()
+		}
+		page.Next = pageURL(req, next, q.Limit)
+	}
+
+	if hasPrev {
+		var prev Cursor
+		if len(vens) > 0 {
+			prev = Before(&vens[0])
+		} else {
+			prev = q.Cursor.Reverse()
+		}
+		page.Prev = pageURL(req, prev, q.Limit)
+	}
+
+	if writers.IsWrapped(req) {
+		total, err := s.Count(q)
+		if err != nil {
+			writers.WriteServerError(res, req)
+			return nil, page, false
+		}
+		page.Total = &total
+	}
+
+	return vens, page, true
+}
+
+// pageURL returns the URL of the request 'req' with its cursor and limit
+// replaced by 'c' and 'limit'.
+func pageURL(req *http.Request, c Cursor, limit int) string {
+	u := *req.URL
+	v := u.Query()
+	v.Set("cursor", c.Encode())
+	v.Set("limit", strconv.Itoa(limit))
+	u.RawQuery = v.Encode()
+	return u.RequestURI()
+}
+
+// findMissingOf finds the IDs requested by the Query 'q' that don't belong to
+// any living Venture. 'vens' are the Ventures found by 'q' which suffice unless
+// 'q' filters out Ventures by more than their IDs or is paged.
+func findMissingOf(s VentureStore, q *Query, vens []Venture, res *http.ResponseWriter, req *http.Request) ([]string, bool) {
+	if len(q.IDs) == 0 {
+		return []string{}, true
+	}
+
+	if q.HasFilters() || q.Paged() {
+		var err error
+		vens, err = s.List(q.IDs)
+		if err != nil {
+			writers.WriteServerError(res, req)
+			return nil, false
+		}
+	}
+
+	return findMissing(q.IDs, vens), true
+}
+
+// findHistory finds every revision of the Ventures with the specified IDs
+// made between 'since' and 'until'.
+func findHistory(s VentureStore, ids []string, since int64, until int64, res *http.ResponseWriter, req *http.Request) ([]Venture, bool) {
+	vens, err := s.History(ids, since, until)
+	if err != nil {
+		writers.WriteServerError(res, req)
+		return nil, false
+	}
+
+	return vens, true
+}
+
+// findAsOf finds the living Ventures, as they were at the time 'asOf', with
+// the specified IDs or all of them if 'ids' is empty.
+func findAsOf(s VentureStore, ids []string, asOf int64, res *http.ResponseWriter, req *http.Request) ([]Venture, bool) {
+	vens, err := s.AsOf(ids, asOf)
 	if err != nil {
 		writers.WriteServerError(res, req)
 		return nil, false
@@ -28,6 +149,155 @@ func find(ids string, res *http.ResponseWriter, req *http.Request) ([]Venture, b
 	return vens, true
 }
 
+// parseMillis parses the query parameter 'name' as a Unix time in
+// milliseconds returning 'def' if the parameter is missing or empty.
+func parseMillis(name string, def int64, res *http.ResponseWriter, req *http.Request) (int64, bool) {
+	r := wrapped.Violations{}
+	ms := MillisParam(name, def, req, &r)
+	if len(r) != 0 {
+		writers.WriteInvalid(res, req, r)
+		return 0, false
+	}
+	return ms, true
+}
+
+// MillisParam parses the query parameter 'name' as a Unix time in milliseconds
+// returning 'def' if it is missing. A Violation is added to 'r' if it can't be
+// parsed.
+func MillisParam(name string, def int64, req *http.Request, r *wrapped.Violations) int64 {
+	v := cookies.StripWhitespace(req.FormValue(name))
+	if v == "" {
+		return def
+	}
+
+	ms, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || ms < 0 {
+		r.Add(name, "invalid", fmt.Sprintf("Could not parse query"+
+			" parameter '%s=%s' into a Unix time in milliseconds", name, v))
+		return def
+	}
+
+	return ms
+}
+
+// parseQuery parses the query parameters that filter and order the living
+// Ventures requested. A Violation is added to 'r' for each parameter that
+// can't be parsed.
+func parseQuery(wf *workflow.Workflow, req *http.Request, r *wrapped.Violations) *Query {
+	q := &Query{
+		ModifiedSince:  MillisParam("modified_since", 0, req, r),
+		ModifiedBefore: MillisParam("modified_before", 0, req, r),
+		Search:         strings.TrimSpace(req.FormValue("q")),
+	}
+
+	if ids := cookies.StripWhitespace(req.FormValue("ids")); ids != "" {
+		if !cookies.IsUintCSV(ids) {
+			r.Add("ids", "invalid", fmt.Sprintf("Could not parse query"+
+				" parameter 'ids=%s' into a list of Venture IDs", ids))
+		}
+		q.IDs = strings.Split(ids, ",")
+	}
+
+	if states := req.FormValue("state"); strings.TrimSpace(states) != "" {
+		for _, st := range strings.Split(states, ",") {
+			st = wf.Canonical(strings.TrimSpace(st))
+			if !wf.Has(st) {
+				r.Add("state", "not_in_workflow", fmt.Sprintf("Query parameter"+
+					" 'state' may only contain states within the workflow, '%s' is"+
+					" not one of them.", st))
+				continue
+			}
+			q.States = append(q.States, st)
+		}
+	}
+
+	if o := cookies.StripWhitespace(req.FormValue("has_order")); o != "" {
+		if !cookies.IsUint(o) {
+			r.Add("has_order", "invalid", fmt.Sprintf("Could not parse query"+
+				" parameter 'has_order=%s' into an Order ID", o))
+		}
+		q.HasOrder = o
+	}
+
+	keys, err := ParseSort(cookies.StripWhitespace(req.FormValue("sort")))
+	if err != nil {
+		r.Add("sort", "invalid", err.Error())
+	}
+	q.Sort = keys
+
+	if l := cookies.StripWhitespace(req.FormValue("limit")); l != "" {
+		n, err := strconv.Atoi(l)
+		if err != nil || n < 1 {
+			r.Add("limit", "invalid", fmt.Sprintf("Could not parse query"+
+				" parameter 'limit=%s' into a positive integer", l))
+		}
+		q.Limit = n
+	}
+
+	if c := cookies.StripWhitespace(req.FormValue("cursor")); c != "" {
+		cur, err := DecodeCursor(c)
+		if err != nil {
+			r.Add("cursor", "invalid", "Query parameter 'cursor' must be one"+
+				" returned within the links to another page.")
+		}
+		q.Cursor = &cur
+	}
+
+	if q.Paged() && len(q.Sort) > 0 {
+		r.Add("sort", "paged", "Query parameter 'sort' can't be used with"+
+			" 'limit' or 'cursor', pages are ordered by 'last_modified' then 'id'.")
+	}
+
+	return q
+}
+
+// parseFields parses the 'fields' query parameter, a CSV of the Venture
+// properties to return, returning an empty slice if every property should be
+// returned. A Violation is added to 'r' for each unknown property.
+func parseFields(req *http.Request, r *wrapped.Violations) []string {
+	v := cookies.StripWhitespace(req.FormValue("fields"))
+	if v == "" {
+		return []string{}
+	}
+
+	fields := strings.Split(v, ",")
+	for _, f := range fields {
+		if !contains(projectable, f) {
+			r.Add("fields", "invalid", fmt.Sprintf("Query parameter 'fields' may"+
+				" only contain '%s', '%s' is not one of them.",
+				strings.Join(projectable, "', '"), f))
+		}
+	}
+
+	return fields
+}
+
+// parseStrict parses the 'strict' query parameter returning 'def' if it is
+// missing. The parameter is true if present without a value. Strict requests
+// fail if any of the Ventures they identify can't be found.
+func parseStrict(def bool, res *http.ResponseWriter, req *http.Request) (bool, bool) {
+	v, ok := req.URL.Query()["strict"]
+	if !ok {
+		return def, true
+	}
+
+	s := cookies.StripWhitespace(v[0])
+	if s == "" {
+		return true, true
+	}
+
+	strict, err := strconv.ParseBool(s)
+	if err != nil {
+		r := wrapped.Violations{}
+		r.Add("strict", "invalid", fmt.Sprintf("Could not parse query"+
+			" parameter 'strict=%s' into a boolean", s))
+		writers.WriteInvalid(res, req, r)
+		return false, false
+	}
+
+	return strict, true
+}
+
 // decodeNew decodes a NewVenture from a Request.Body.
 func decodeNew(res *http.ResponseWriter, req *http.Request) (NewVenture, bool) {
 	ven, err := DecodeNewVenture(req.Body)
@@ -38,23 +308,93 @@ func decodeNew(res *http.ResponseWriter, req *http.Request) (NewVenture, bool) {
 	return ven, true
 }
 
+// isArrayBody returns true if the Request.Body holds a JSON array rather than a
+// single JSON object. The body is read in full then replaced so it may still be
+// decoded.
+func isArrayBody(req *http.Request) bool {
+	b, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	req.Body = ioutil.NopCloser(bytes.NewReader(b))
+
+	if err != nil {
+		return false
+	}
+
+	b = bytes.TrimLeftFunc(b, unicode.IsSpace)
+	return len(b) > 0 && b[0] == '['
+}
+
+// decodeNewSlice decodes a slice of NewVentures from a Request.Body.
+func decodeNewSlice(res *http.ResponseWriter, req *http.Request) ([]NewVenture, bool) {
+	vens, err := DecodeNewVentureSlice(req.Body)
+	if err != nil {
+		writers.WriteBadRequest(res, req, "Unable to decode request body into an array of Ventures")
+		return nil, false
+	}
+	return vens, true
+}
+
+// validateNewSlice validates each NewVenture within 'news'. The field and
+// message of each Violation is prefixed with the index of the NewVenture it
+// applies to.
+func validateNewSlice(wf *workflow.Workflow, news []NewVenture, res *http.ResponseWriter, req *http.Request) bool {
+	if len(news) == 0 {
+		writers.WriteBadRequest(res, req, "At least one Venture must be supplied.")
+		return false
+	}
+
+	r := wrapped.Violations{}
+	for i := range news {
+		for _, v := range news[i].Validate(wf) {
+			r.Add(fmt.Sprintf("[%d].%s", i, v.Field), v.Code, fmt.Sprintf("[%d] %s", i, v.Message))
+		}
+	}
+
+	if len(r) != 0 {
+		writers.WriteInvalid(res, req, r)
+		return false
+	}
+	return true
+}
+
 // validateNew validates a NewVenture that has yet to be assigned an ID.
-func validateNew(ven *NewVenture, res *http.ResponseWriter, req *http.Request) bool {
-	errMsgs := ven.Validate()
-	if len(errMsgs) != 0 {
-		writers.WriteBadRequest(res, req, strings.Join(errMsgs, " "))
+func validateNew(wf *workflow.Workflow, ven *NewVenture, res *http.ResponseWriter, req *http.Request) bool {
+	r := ven.Validate(wf)
+	if len(r) != 0 {
+		writers.WriteInvalid(res, req, r)
 		return false
 	}
 	return true
 }
 
-// insertNew inserts a new Venture into the database.
-func insertNew(new *NewVenture, res *http.ResponseWriter, req *http.Request) (*Venture, bool) {
-	ven, ok := new.Insert()
-	if !ok {
+// insertNew inserts a new Venture into the store.
+func insertNew(s VentureStore, new *NewVenture, res *http.ResponseWriter, req *http.Request) (*Venture, bool) {
+	ven, err := s.Create(new)
+	if e, ok := err.(*InvalidError); ok {
+		writeInvalid(e, "", res, req)
+		return nil, false
+	}
+
+	if err != nil {
 		writers.WriteServerError(res, req)
+		return nil, false
 	}
-	return ven, ok
+	return ven, true
+}
+
+// insertNewSlice inserts every new Venture within 'news' into the store.
+func insertNewSlice(s VentureStore, news []NewVenture, res *http.ResponseWriter, req *http.Request) ([]Venture, bool) {
+	vens, err := s.CreateAll(news)
+	if e, ok := err.(*InvalidError); ok {
+		writeInvalid(e, "", res, req)
+		return nil, false
+	}
+
+	if err != nil {
+		writers.WriteServerError(res, req)
+		return nil, false
+	}
+	return vens, true
 }
 
 // decodeMod decodes modifications to Ventures from a Request.Body.
@@ -69,10 +409,93 @@ func decodeMod(res *http.ResponseWriter, req *http.Request) (*ModVenture, bool)
 }
 
 // validateMod validates a Venture update.
-func validateMod(mv *ModVenture, res *http.ResponseWriter, req *http.Request) bool {
-	errMsgs := mv.Validate()
-	if len(errMsgs) != 0 {
-		writers.WriteBadRequest(res, req, strings.Join(errMsgs, " "))
+func validateMod(wf *workflow.Workflow, mv *ModVenture, res *http.ResponseWriter, req *http.Request) bool {
+	r := mv.Validate(wf)
+	if len(r) != 0 {
+		writers.WriteInvalid(res, req, r)
+		return false
+	}
+	return true
+}
+
+// findOne finds the living Venture with the ID 'id'.
+func findOne(s VentureStore, id string, res *http.ResponseWriter, req *http.Request) (*Venture, bool) {
+	ven, err := s.Get(id)
+	if err != nil {
+		writers.WriteServerError(res, req)
+		return nil, false
+	}
+
+	if ven == nil {
+		writeNotFound(&NotFoundError{IDs: []string{id}}, res, req)
+		return nil, false
+	}
+
+	return ven, true
+}
+
+// parsePatchType returns the media type of the patch within the Request.Body
+// if it is one of those accepted.
+func parsePatchType(res *http.ResponseWriter, req *http.Request) (string, bool) {
+	mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+
+	switch mediaType {
+	case MergePatchType, JSONPatchType:
+		return mediaType, true
+	}
+
+	writers.WriteProblem(res, req, wrapped.Problem{
+		Status: http.StatusUnsupportedMediaType,
+		Detail: fmt.Sprintf("Ventures may only be patched using '%s' or '%s'",
+			MergePatchType, JSONPatchType),
+	})
+	return "", false
+}
+
+// decodePatch applies the patch within the Request.Body to the Venture 'ven'
+// returning the modification that stores the changes it made along with the
+// Violations of any unknown or immutable properties changed.
+func decodePatch(ven *Venture, mediaType string, res *http.ResponseWriter, req *http.Request) (*ModVenture, wrapped.Violations, bool) {
+	b, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		writers.WriteBadRequest(res, req, "Unable to read the request body")
+		return nil, nil, false
+	}
+
+	patched, err := ApplyPatch(ven, mediaType, b)
+	e, isPatchErr := err.(*patch.Error)
+
+	switch {
+	case err == nil:
+	case err == ErrNotVenture, isPatchErr && e.Malformed:
+		writers.WriteBadRequest(res, req, err.Error())
+		return nil, nil, false
+	case isPatchErr:
+		writers.WriteProblem(res, req, wrapped.Problem{
+			Status: http.StatusConflict,
+			Detail: err.Error(),
+		})
+		return nil, nil, false
+	default:
+		writers.WriteServerError(res, req)
+		return nil, nil, false
+	}
+
+	mv, r := NewPatchMod(ven, patched)
+	return mv, r, true
+}
+
+// validatePatch validates the modification made by a patch appending the
+// Violations found to 'r', those found while decoding the patch. Violations
+// are named by property rather than the field of the modification.
+func validatePatch(wf *workflow.Workflow, mv *ModVenture, r wrapped.Violations, res *http.ResponseWriter, req *http.Request) bool {
+	mv.validateProps(wf, &r)
+
+	if len(r) != 0 {
+		for i := range r {
+			r[i].Field = strings.TrimPrefix(r[i].Field, "values.")
+		}
+		writers.WriteInvalid(res, req, r)
 		return false
 	}
 	return true
@@ -81,15 +504,18 @@ func validateMod(mv *ModVenture, res *http.ResponseWriter, req *http.Request) bo
 // idCsvToSlice validates then parses a CSV string of IDs into a slice.
 func idCsvToSlice(idCsv string, res *http.ResponseWriter, req *http.Request) ([]string, bool) {
 	idCsv = cookies.StripWhitespace(idCsv)
+	r := wrapped.Violations{}
 
-	if idCsv == "" {
-		writers.WriteBadRequest(res, req, "Query parameter 'ids' is missing or empty")
-		return nil, false
+	switch {
+	case idCsv == "":
+		r.Add("ids", "required", "Query parameter 'ids' is missing or empty")
+	case !cookies.IsUintCSV(idCsv):
+		r.Add("ids", "invalid", fmt.Sprintf("Could not parse query parameter"+
+			" 'ids=%s' into a list of Venture IDs", idCsv))
 	}
 
-	if !cookies.IsUintCSV(idCsv) {
-		writers.WriteBadRequest(res, req, fmt.Sprintf("Could not parse query parameter"+
-			" 'ids=%s' into a list of Venture IDs", idCsv))
+	if len(r) != 0 {
+		writers.WriteInvalid(res, req, r)
 		return nil, false
 	}
 
@@ -112,12 +538,125 @@ func idsToCSV(vens []Venture) string {
 }
 
 // pushMod performs the specified modification operation and pushes the result
-// to the database.
-func pushMod(mv *ModVenture, res *http.ResponseWriter, req *http.Request) ([]Venture, bool) {
-	vens, ok := mv.Update()
-	if !ok {
+// to the store. 'prefix' is prepended to the field of any Violations found by
+// the store, e.g. 'values.' when the new values are nested within the request.
+func pushMod(s VentureStore, mv *ModVenture, prefix string, res *http.ResponseWriter, req *http.Request) ([]Venture, bool) {
+	vens, err := s.Modify(mv)
+	switch e := err.(type) {
+	case *ConflictError:
+		writeConflict(e, res, req)
+		return nil, false
+	case *NotFoundError:
+		writeNotFound(e, res, req)
+		return nil, false
+	case *InvalidError:
+		writeInvalid(e, prefix, res, req)
+		return nil, false
+	}
+
+	if err != nil {
+		writers.WriteServerError(res, req)
+		return nil, false
+	}
+	return vens, true
+}
+
+// writeNotFound writes the response for a request that required Ventures which
+// could not be found.
+func writeNotFound(nf *NotFoundError, res *http.ResponseWriter, req *http.Request) {
+	r := wrapped.Violations{}
+	for _, id := range nf.IDs {
+		r.Add("ids", "not_found", fmt.Sprintf("Venture '%s' could not be found.", id))
+	}
+
+	writers.WriteProblem(res, req, wrapped.Problem{
+		Status: http.StatusNotFound,
+		Detail: nf.Error(),
+		Errors: r,
+	})
+}
+
+// writeInvalid writes the response for a request the store found invalid.
+// 'prefix' is prepended to the field of each Violation.
+func writeInvalid(ie *InvalidError, prefix string, res *http.ResponseWriter, req *http.Request) {
+	r := make(wrapped.Violations, len(ie.Violations))
+	for i, v := range ie.Violations {
+		r[i] = v
+		r[i].Field = prefix + v.Field
+	}
+	writers.WriteInvalid(res, req, r)
+}
+
+// writeConflict writes the problem response for a modification that expected
+// revisions of Ventures other than the latest. The latest revisions are
+// returned within the 'current' member, along with their ETag, so the client
+// may reapply its changes to them.
+func writeConflict(ce *ConflictError, res *http.ResponseWriter, req *http.Request) {
+	status := http.StatusConflict
+	if ce.Precondition {
+		status = http.StatusPreconditionFailed
+	}
+
+	(*res).Header().Set("ETag", ETag(ce.Current))
+	writers.WriteProblem(res, req, wrapped.Problem{
+		Status:  status,
+		Detail:  ce.Error(),
+		Current: ce.Current,
+	})
+}
+
+// pushKill marks the Ventures with the specified IDs as dead by pushing a new
+// revision of each to the store.
+func pushKill(s VentureStore, ids []string, res *http.ResponseWriter, req *http.Request) ([]Venture, bool) {
+	vens, err := s.Kill(ids)
+	if err != nil {
 		writers.WriteServerError(res, req)
 		return nil, false
 	}
 	return vens, true
 }
+
+// project returns 'vens' with only the properties named within 'fields' or
+// 'vens' as they are if 'fields' is empty.
+func project(vens []Venture, fields []string) interface{} {
+	if len(fields) == 0 {
+		return vens
+	}
+
+	r := make([]map[string]interface{}, len(vens))
+	for i, ven := range vens {
+		all := map[string]interface{}{
+			"id":            ven.ID,
+			"last_modified": ven.LastModified,
+			"description":   ven.Description,
+			"orders":        ven.Orders,
+			"state":         ven.State,
+			"dead":          ven.Dead,
+			"extra":         ven.Extra,
+		}
+
+		r[i] = make(map[string]interface{}, len(fields))
+		for _, f := range fields {
+			r[i][f] = all[f]
+		}
+	}
+
+	return r
+}
+
+// findMissing returns the IDs within 'ids' that do not belong to any of the
+// Ventures within 'vens'.
+func findMissing(ids []string, vens []Venture) []string {
+	found := make(map[string]bool, len(vens))
+	for _, ven := range vens {
+		found[ven.ID] = true
+	}
+
+	missing := []string{}
+	for _, id := range ids {
+		if !found[id] {
+			missing = append(missing, id)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,357 @@
+package ventures
+
+import (
+	"database/sql"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/PaulioRandall/go-cookies/cookies"
+	"github.com/PaulioRandall/go-qlueless-api/api/workflow"
+)
+
+// MemStore is a VentureStore held in memory. It is safe for concurrent use
+// but nothing is persisted so it's best suited to testing.
+type MemStore struct {
+	mutex  sync.RWMutex
+	wf     *workflow.Workflow
+	ids    []string
+	revs   map[string][]Venture
+	orders map[string]bool
+}
+
+// NewMemStore returns a new empty MemStore that orders states as they are
+// within the workflow 'wf'.
+func NewMemStore(wf *workflow.Workflow) *MemStore {
+	return &MemStore{
+		wf:     wf,
+		ids:    []string{},
+		revs:   map[string][]Venture{},
+		orders: map[string]bool{},
+	}
+}
+
+// SetOrders sets the IDs of the living Orders that Ventures within the store
+// may reference.
+func (s *MemStore) SetOrders(ids ...string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.orders = map[string]bool{}
+	for _, id := range ids {
+		s.orders[id] = true
+	}
+}
+
+// Get implements VentureStore.
+func (s *MemStore) Get(id string) (*Venture, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	ven := s.latest(id)
+	if ven == nil || ven.Dead {
+		return nil, nil
+	}
+
+	v := *ven
+	return &v, nil
+}
+
+// List implements VentureStore.
+func (s *MemStore) List(ids []string) ([]Venture, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.living(ids), nil
+}
+
+// Find implements VentureStore.
+func (s *MemStore) Find(q *Query) ([]Venture, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	vens := s.matching(q)
+	if !q.Paged() {
+		SortVentures(s.wf, vens, q.Sort)
+		return vens, nil
+	}
+
+	SortVentures(s.wf, vens, []SortKey{{Prop: "last_modified"}})
+
+	if q.Cursor != nil {
+		page := []Venture{}
+		for _, ven := range vens {
+			if q.Cursor.Includes(&ven) {
+				page = append(page, ven)
+			}
+		}
+		vens = page
+	}
+
+	if q.Limit > 0 && len(vens) > q.Limit {
+		if q.Cursor != nil && q.Cursor.Before {
+			return vens[len(vens)-q.Limit:], nil
+		}
+		return vens[:q.Limit], nil
+	}
+
+	return vens, nil
+}
+
+// Count implements VentureStore.
+func (s *MemStore) Count(q *Query) (int, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return len(s.matching(q)), nil
+}
+
+// Create implements VentureStore.
+func (s *MemStore) Create(nv *NewVenture) (*Venture, error) {
+	vens, err := s.create([]NewVenture{*nv}, false)
+	if err != nil {
+		return nil, err
+	}
+	return &vens[0], nil
+}
+
+// CreateAll implements VentureStore.
+func (s *MemStore) CreateAll(nvs []NewVenture) ([]Venture, error) {
+	return s.create(nvs, true)
+}
+
+// create is a file private function that performs the work of Create() and
+// CreateAll(). 'indexed' is passed to checkNewOrders().
+func (s *MemStore) create(nvs []NewVenture, indexed bool) ([]Venture, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	err := checkNewOrders(nvs, indexed, s.missingOrders)
+	if err != nil {
+		return nil, err
+	}
+
+	vens := make([]Venture, len(nvs))
+	for i, nv := range nvs {
+		vens[i] = Venture{
+			ID:           strconv.Itoa(len(s.ids) + 1),
+			LastModified: cookies.ToUnixMilli(time.Now()),
+			Description:  nv.Description,
+			Orders:       nv.Orders,
+			State:        nv.State,
+			Extra:        nv.Extra,
+		}
+
+		s.ids = append(s.ids, vens[i].ID)
+		s.revs[vens[i].ID] = []Venture{vens[i]}
+	}
+
+	return vens, nil
+}
+
+// Modify implements VentureStore.
+func (s *MemStore) Modify(mv *ModVenture) ([]Venture, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.modify(mv)
+}
+
+// modify is a file private function that applies the modification 'mv'
+// returning the new revision of each Venture modified. The caller must hold the
+// lock.
+func (s *MemStore) modify(mv *ModVenture) ([]Venture, error) {
+	var vens []Venture
+
+	switch {
+	case !mv.Restores():
+		vens = s.living(mv.SplitIDs())
+	case mv.Sets("orders"):
+		vens = s.latestOf(mv.SplitIDs())
+	default:
+		vens = s.latestOf(mv.SplitIDs())
+		err := pruneRestored(vens, s.missingOrders)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err := mv.check(vens)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkTransitions(s.wf, mv, vens)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkModOrders(mv, s.missingOrders)
+	if err != nil {
+		return nil, err
+	}
+
+	now := cookies.ToUnixMilli(time.Now())
+	for i := range vens {
+		mv.ApplyMod(&vens[i])
+		stamp(&vens[i], now)
+		s.revs[vens[i].ID] = append(s.revs[vens[i].ID], vens[i])
+	}
+
+	return vens, nil
+}
+
+// PruneOrders implements VentureStore. 'tx' is ignored so the pruning is not
+// undone if it's rolled back. The Orders are also no longer considered living.
+func (s *MemStore) PruneOrders(tx *sql.Tx, orderIDs []string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, id := range orderIDs {
+		delete(s.orders, id)
+	}
+
+	ids := referencing(s.living(nil), orderIDs)
+	if len(ids) == 0 {
+		return nil
+	}
+
+	_, err := s.modify(pruneMod(ids, orderIDs))
+	return err
+}
+
+// Kill implements VentureStore.
+func (s *MemStore) Kill(ids []string) ([]Venture, error) {
+	return s.Modify(killMod(ids))
+}
+
+// History implements VentureStore.
+func (s *MemStore) History(ids []string, since int64, until int64) ([]Venture, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	vens := []Venture{}
+	for _, id := range s.filter(ids) {
+		for _, ven := range s.revs[id] {
+			if ven.LastModified >= since && ven.LastModified <= until {
+				vens = append(vens, ven)
+			}
+		}
+	}
+
+	return vens, nil
+}
+
+// AsOf implements VentureStore.
+func (s *MemStore) AsOf(ids []string, asOf int64) ([]Venture, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	vens := []Venture{}
+	for _, id := range s.filter(ids) {
+		var latest *Venture
+
+		for i, ven := range s.revs[id] {
+			if ven.LastModified <= asOf {
+				latest = &s.revs[id][i]
+			}
+		}
+
+		if latest != nil && !latest.Dead {
+			vens = append(vens, *latest)
+		}
+	}
+
+	return vens, nil
+}
+
+// MissingOrders implements VentureStore.
+func (s *MemStore) MissingOrders(ids []string) ([]string, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.missingOrders(ids)
+}
+
+// missingOrders is a file private function that returns the Order IDs, within
+// 'ids', that do not belong to any living Order. The caller must hold the
+// lock.
+func (s *MemStore) missingOrders(ids []string) ([]string, error) {
+	missing := []string{}
+	for _, id := range ids {
+		if !s.orders[id] {
+			missing = append(missing, id)
+		}
+	}
+	return missing, nil
+}
+
+// filter is a file private function that returns the IDs, within 'ids', of
+// Ventures within the store in the order they were created or the IDs of every
+// Venture if 'ids' is empty. The caller must hold the lock.
+func (s *MemStore) filter(ids []string) []string {
+	if len(ids) == 0 {
+		return s.ids
+	}
+
+	want := map[string]bool{}
+	for _, id := range ids {
+		want[id] = true
+	}
+
+	found := []string{}
+	for _, id := range s.ids {
+		if want[id] {
+			found = append(found, id)
+		}
+	}
+
+	return found
+}
+
+// latest is a file private function that returns the latest revision, dead or
+// alive, of the Venture with the ID 'id' or nil if there is no such Venture.
+// The caller must hold the lock.
+func (s *MemStore) latest(id string) *Venture {
+	revs := s.revs[id]
+	if len(revs) == 0 {
+		return nil
+	}
+	return &revs[len(revs)-1]
+}
+
+// latestOf is a file private function that returns a copy of the latest
+// revision, dead or alive, of each of the specified Ventures. The caller must
+// hold the lock.
+func (s *MemStore) latestOf(ids []string) []Venture {
+	vens := []Venture{}
+	for _, id := range s.filter(ids) {
+		vens = append(vens, *s.latest(id))
+	}
+	return vens
+}
+
+// matching is a file private function that returns a copy of the living
+// Ventures that match the Query 'q' ignoring its order, Cursor, and Limit. The
+// caller must hold the lock.
+func (s *MemStore) matching(q *Query) []Venture {
+	vens := []Venture{}
+	for _, ven := range s.living(q.IDs) {
+		if q.Matches(&ven) {
+			vens = append(vens, ven)
+		}
+	}
+	return vens
+}
+
+// living is a file private function that returns a copy of the specified
+// living Ventures or every living Venture if 'ids' is empty. The caller must
+// hold the lock.
+func (s *MemStore) living(ids []string) []Venture {
+	vens := []Venture{}
+	for _, ven := range s.latestOf(ids) {
+		if !ven.Dead {
+			vens = append(vens, ven)
+		}
+	}
+	return vens
+}
@@ -1,23 +1,33 @@
 package ventures
 
 import (
-	"database/sql"
 	"encoding/json"
 	"fmt"
 	"io"
 	"strings"
-	"time"
 
 	"github.com/PaulioRandall/go-cookies/cookies"
-	"github.com/PaulioRandall/go-cookies/strlist"
-	"github.com/PaulioRandall/go-qlueless-api/api/database"
+	"github.com/PaulioRandall/go-qlueless-api/api/workflow"
+	"github.com/PaulioRandall/go-qlueless-api/shared/wrapped"
 )
 
 // ModVenture represents an update to a Venture.
+//
+// LastModified optionally maps the IDs of Ventures to the last modified time
+// of the revision the client expects each to be at while IfMatch optionally
+// holds the value of the 'If-Match' request header. If either expectation is
+// not met then nothing is modified. If Strict is true then nothing is modified
+// unless every Venture identified is found. If Prune is not nil then setting
+// 'orders' removes the Order IDs within it from each Venture rather than
+// replacing their Orders with those of Values.
 type ModVenture struct {
-	IDs    string  `json:"ids"`
-	Props  string  `json:"set"`
-	Values Venture `json:"values"`
+	IDs          string           `json:"ids"`
+	Props        string           `json:"set"`
+	Values       Venture          `json:"values"`
+	LastModified map[string]int64 `json:"last_modified,omitempty"`
+	IfMatch      string           `json:"-"`
+	Strict       bool             `json:"-"`
+	Prune        []string         `json:"-"`
 }
 
 // DecodeModVenture decodes a ModVenture from data obtained via a Reader.
@@ -44,143 +54,158 @@ func (mv *ModVenture) SplitProps() []string {
 	return strings.Split(mv.Props, ",")
 }
 
-// Clean cleans up the ModVenture by removing whitespace where applicable.
-func (mv *ModVenture) Clean() {
+// Sets returns true if the property 'prop' is one of the properties to update.
+func (mv *ModVenture) Sets(prop string) bool {
+	for _, p := range mv.SplitProps() {
+		if p == prop {
+			return true
+		}
+	}
+	return false
+}
+
+// Restores returns true if the modification brings dead Ventures back to life.
+func (mv *ModVenture) Restores() bool {
+	return mv.Sets("dead") && !mv.Values.Dead
+}
+
+// Clean cleans up the ModVenture by removing whitespace where applicable and
+// replacing the state with its canonical form within the workflow 'wf'.
+func (mv *ModVenture) Clean(wf *workflow.Workflow) {
 	mv.IDs = cookies.StripWhitespace(mv.IDs)
 	mv.Props = cookies.StripWhitespace(mv.Props)
-	mv.Values.Clean()
+	mv.Values.Clean(wf)
 }
 
 // validateProps checks the properties declared for change are valid and the
-// property value for each is valid. Returned is the input slice of human
-// readable error messages with the violations found appended to it. These
-// messages are suitable for returning to clients.
-func (mv *ModVenture) validateProps(r *strlist.StrList) {
+// property value for each is valid, states against the workflow 'wf'. The
+// Violations found are appended to 'r'.
+func (mv *ModVenture) validateProps(wf *workflow.Workflow, r *wrapped.Violations) {
 	for _, prop := range mv.SplitProps() {
 		switch prop {
 		case "dead", "extra":
 		case "description":
 			if mv.Values.Description == "" {
-				r.Add("Ventures must have a description.")
+				r.Add("values.description", "required", "Ventures must have a description.")
 			}
 		case "state":
-			if mv.Values.State == "" {
-				r.Add("Ventures must have a state.")
-			}
+			validateState(wf, "values.state", mv.Values.State, r)
 		case "orders":
-			if !cookies.IsUintCSV(mv.Values.Orders) {
-				r.Add("The list of Order IDs within a Venture must be an integer CSV.")
+			if mv.Values.Orders != "" && !cookies.IsUintCSV(mv.Values.Orders) {
+				r.Add("values.orders", "invalid", "The list of Order IDs within a Venture must be an integer CSV.")
 			}
 		default:
-			r.Add(fmt.Sprintf("Can't update unknown or immutable property '%s'.", prop))
+			r.Add("set", "immutable", fmt.Sprintf("Can't update unknown or immutable property '%s'.", prop))
 		}
 	}
 }
 
-// Validate checks each field contains valid content returning a non-empty
-// slice of human readable error messages detailing the violations found or an
-// empty slice if all is well. These messages are suitable for returning to
-// clients.
-func (mv *ModVenture) Validate() []string {
-	r := strlist.StrList{}
+// Validate checks each field contains valid content, and any state is within
+// the workflow 'wf', returning the Violations found or an empty slice if all is
+// well. The message of each Violation is suitable for returning to clients.
+func (mv *ModVenture) Validate(wf *workflow.Workflow) wrapped.Violations {
+	r := wrapped.Violations{}
 
 	switch {
 	case mv.IDs == "":
-		r.Add("'ids' must be supplied so the Ventures to update can be determined.")
+		r.Add("ids", "required", "'ids' must be supplied so the Ventures to update can be determined.")
 	case !cookies.IsUintCSV(mv.IDs):
-		r.Add("'ids' must be a CSV of positive integers.")
+		r.Add("ids", "invalid", "'ids' must be a CSV of positive integers.")
 	}
 
 	if mv.Props == "" {
-		r.Add("Some properties must be 'set' for any updating to take place.")
+		r.Add("set", "required", "Some properties must be 'set' for any updating to take place.")
 	}
 
-	mv.validateProps(&r)
-	return r.Slice()
-}
+	mv.validateProps(wf, &r)
 
-// ApplyMod applies the modifications to the supplied Venture only touching
-// those properties the user has specified
-func (mv *ModVenture) ApplyMod(ven *Venture) {
-	mod := mv.Values
-	for _, p := range mv.SplitProps() {
-		ven.LastModified = cookies.ToUnixMilli(time.Now())
-		switch p {
-		case "description":
-			ven.Description = mod.Description
-		case "orders":
-			ven.Orders = mod.Orders
-		case "state":
-			ven.State = mod.State
-		case "dead":
-			ven.Dead = mod.Dead
-		case "extra":
-			ven.Extra = mod.Extra
+	for id := range mv.LastModified {
+		if !mv.Targets(id) {
+			r.Add("last_modified", "not_targeted", fmt.Sprintf("'last_modified' may only"+
+				" contain the IDs within 'ids', '%s' is not one of them.", id))
 		}
 	}
-}
-
-// Update pushes the modification of changes to the database.
-func (mv *ModVenture) Update() ([]Venture, bool) {
 
-	ids := mv.SplitIDs()
-	args := make([]interface{}, len(ids))
-	for i := range ids {
-		args[i] = ids[i]
-	}
+	return r
+}
 
-	vens, err := QueryMany(args)
-	if cookies.LogIfErr(err) {
-		return nil, false
+// Targets returns true if the Venture with the ID 'id' is one of the Ventures
+// to update.
+func (mv *ModVenture) Targets(id string) bool {
+	for _, v := range mv.SplitIDs() {
+		if v == id {
+			return true
+		}
 	}
+	return false
+}
 
-	ok := mv.insertEach(vens)
-	if !ok {
-		return nil, false
+// check returns an error if the Ventures found to modify, those within 'vens',
+// do not meet the expectations of the client; a *NotFoundError if the
+// modification is strict and any are missing else a *ConflictError if any are
+// not at the expected revision.
+func (mv *ModVenture) check(vens []Venture) error {
+	if mv.Strict {
+		missing := findMissing(mv.SplitIDs(), vens)
+		if len(missing) > 0 {
+			return &NotFoundError{
+				IDs: missing,
+			}
+		}
 	}
 
-	return vens, true
+	return mv.checkCurrent(vens)
 }
 
-// insertEach is a file private function that performs the actual SQL operation
-// of pushing modifications to the database.
-func (mv *ModVenture) insertEach(vens []Venture) bool {
-
-	stmt, err := database.Get().Prepare(`INSERT INTO venture
-			(id, description, order_ids, state, is_dead, extra)
-		VALUES
-			(?, ?, ?, ?, ?, ?);`)
+// checkCurrent returns a *ConflictError if the client expects revisions of the
+// Ventures being modified other than the latest, those within 'vens'.
+func (mv *ModVenture) checkCurrent(vens []Venture) error {
+	if mv.IfMatch != "" && !matchesETag(mv.IfMatch, ETag(vens)) {
+		return &ConflictError{
+			Precondition: true,
+			Current:      vens,
+		}
+	}
 
-	if stmt != nil {
-		defer stmt.Close()
+	stale := []string{}
+	for _, ven := range vens {
+		lm, ok := mv.LastModified[ven.ID]
+		if ok && lm != ven.LastModified {
+			stale = append(stale, ven.ID)
+		}
 	}
 
-	if cookies.LogIfErr(err) {
-		return false
+	if len(stale) > 0 {
+		return &ConflictError{
+			Stale:   stale,
+			Current: vens,
+		}
 	}
 
-	return mv.execStmtForEach(stmt, vens)
+	return nil
 }
 
-// execStmtForEach executes the insert statment provided for each Venture
-// provided.
-func (mv *ModVenture) execStmtForEach(stmt *sql.Stmt, vens []Venture) bool {
-	for i := range vens {
-
-		ven := &vens[i]
-		mv.ApplyMod(ven)
-
-		_, err := stmt.Exec(ven.ID,
-			ven.Description,
-			ven.Orders,
-			ven.State,
-			ven.Dead,
-			ven.Extra)
-
-		if cookies.LogIfErr(err) {
-			return false
+// ApplyMod applies the modifications to the supplied Venture only touching
+// those properties the user has specified. The last modified time is left for
+// the store to set.
+func (mv *ModVenture) ApplyMod(ven *Venture) {
+	mod := mv.Values
+	for _, p := range mv.SplitProps() {
+		switch p {
+		case "description":
+			ven.Description = mod.Description
+		case "orders":
+			if mv.Prune != nil {
+				ven.SetOrders(without(ven.SplitOrders(), mv.Prune))
+			} else {
+				ven.Orders = mod.Orders
+			}
+		case "state":
+			ven.State = mod.State
+		case "dead":
+			ven.Dead = mod.Dead
+		case "extra":
+			ven.Extra = mod.Extra
 		}
 	}
-
-	return true
 }
@@ -0,0 +1,132 @@
+package ventures
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/PaulioRandall/go-qlueless-api/shared/patch"
+	"github.com/PaulioRandall/go-qlueless-api/shared/wrapped"
+)
+
+const (
+	// MergePatchType is the media type of JSON Merge Patches (RFC 7396).
+	MergePatchType = "application/merge-patch+json"
+
+	// JSONPatchType is the media type of JSON Patches (RFC 6902).
+	JSONPatchType = "application/json-patch+json"
+)
+
+// patchDoc is the document patches are applied to. Unlike a Venture, every
+// property is present so JSON Patches may replace those that are empty.
+type patchDoc struct {
+	ID           string `json:"id"`
+	LastModified int64  `json:"last_modified"`
+	Description  string `json:"description"`
+	Orders       string `json:"orders"`
+	State        string `json:"state"`
+	Dead         bool   `json:"dead"`
+	Extra        string `json:"extra"`
+}
+
+// ErrNotVenture is returned when a patch replaces the Venture with something
+// other than a JSON object.
+var ErrNotVenture = errors.New("The patched Venture must remain a JSON object")
+
+// ApplyPatch applies the patch 'p', of the media type 'mediaType', to the
+// Venture 'ven' returning the members of the patched Venture; 'ven' is not
+// changed. A *patch.Error is returned if the patch is malformed or can't be
+// applied.
+func ApplyPatch(ven *Venture, mediaType string, p []byte) (map[string]json.RawMessage, error) {
+	doc, err := json.Marshal(patchDoc(*ven))
+	if err != nil {
+		return nil, err
+	}
+
+	switch mediaType {
+	case MergePatchType:
+		doc, err = patch.Merge(doc, p)
+	case JSONPatchType:
+		doc, err = patch.Apply(doc, p)
+	default:
+		err = fmt.Errorf("Unsupported patch media type '%s'", mediaType)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	patched := map[string]json.RawMessage{}
+	if json.Unmarshal(doc, &patched) != nil || patched == nil {
+		return nil, ErrNotVenture
+	}
+
+	return patched, nil
+}
+
+// NewPatchMod returns the ModVenture that updates the Venture 'ven' to the
+// patched Venture 'patched', see ApplyPatch(), setting only the properties
+// that changed; removed properties become empty. The modification expects
+// 'ven' to still be the latest revision. Violations are returned, named by
+// property, for unknown or immutable properties and values of the wrong type.
+func NewPatchMod(ven *Venture, patched map[string]json.RawMessage) (*ModVenture, wrapped.Violations) {
+	r := wrapped.Violations{}
+	before, after := patchDoc(*ven), patchDoc{}
+	members := after.members()
+	bad := map[string]bool{}
+
+	keys := []string{}
+	for k := range patched {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		m, ok := members[k]
+		switch {
+		case !ok:
+			r.Add(k, "immutable", fmt.Sprintf("Can't update unknown or immutable property '%s'.", k))
+		case json.Unmarshal(patched[k], m) != nil:
+			r.Add(k, "invalid", fmt.Sprintf("'%s' is not of the correct type.", k))
+			bad[k] = true
+		}
+	}
+
+	props := []string{}
+	for _, k := range projectable {
+		x, _ := json.Marshal(before.members()[k])
+		y, _ := json.Marshal(members[k])
+
+		switch {
+		case bad[k], string(x) == string(y):
+		case k == "id", k == "last_modified":
+			r.Add(k, "immutable", fmt.Sprintf("Can't update unknown or immutable property '%s'.", k))
+		default:
+			props = append(props, k)
+		}
+	}
+
+	return &ModVenture{
+		IDs:          ven.ID,
+		Props:        strings.Join(props, ","),
+		Values:       Venture(after),
+		LastModified: map[string]int64{ven.ID: ven.LastModified},
+		Strict:       true,
+	}, r
+}
+
+// members is a file private function that returns a pointer to each property
+// of the patchDoc keyed by its JSON name.
+func (d *patchDoc) members() map[string]interface{} {
+	return map[string]interface{}{
+		"id":            &d.ID,
+		"last_modified": &d.LastModified,
+		"description":   &d.Description,
+		"orders":        &d.Orders,
+		"state":         &d.State,
+		"dead":          &d.Dead,
+		"extra":         &d.Extra,
+	}
+}
@@ -0,0 +1,116 @@
+package ventures
+
+import (
+	"github.com/PaulioRandall/go-qlueless-api/api/database"
+)
+
+// createPostgresTables creates all the Venture tables, functions and triggers
+// within the supplied PostgreSQL database. They behave exactly as their SQLite
+// counterparts do; the venture table is append only and the ql_venture table
+// holds the latest revision of each living Venture.
+func createPostgresTables(db database.Executor) error {
+	stmts := []string{
+		`CREATE TABLE venture (
+			id INTEGER NOT NULL,
+			last_modified BIGINT NOT NULL DEFAULT (FLOOR(EXTRACT(EPOCH FROM clock_timestamp()) * 1000)::BIGINT),
+			description TEXT NOT NULL,
+			order_ids TEXT NOT NULL,
+			state TEXT NOT NULL,
+			is_dead BOOL NOT NULL DEFAULT FALSE,
+			extra TEXT NOT NULL DEFAULT '',
+			PRIMARY KEY(id, last_modified)
+		);`,
+		`CREATE TABLE ql_venture (
+			id INTEGER NOT NULL PRIMARY KEY,
+			last_modified BIGINT NOT NULL,
+			description TEXT NOT NULL,
+			order_ids TEXT NOT NULL,
+			state TEXT NOT NULL,
+			is_dead BOOL NOT NULL,
+			extra TEXT NOT NULL
+		);`,
+		`CREATE FUNCTION upsert_ql_venture() RETURNS TRIGGER AS $$
+			BEGIN
+				INSERT INTO ql_venture (
+					id, last_modified, description, order_ids, state, is_dead, extra
+				) VALUES (
+					NEW.id, NEW.last_modified, NEW.description, NEW.order_ids, NEW.state, NEW.is_dead, NEW.extra
+				)
+				ON CONFLICT (id) DO UPDATE SET
+					last_modified = EXCLUDED.last_modified,
+					description = EXCLUDED.description,
+					order_ids = EXCLUDED.order_ids,
+					state = EXCLUDED.state,
+					is_dead = EXCLUDED.is_dead,
+					extra = EXCLUDED.extra;
+				RETURN NULL;
+			END;
+		$$ LANGUAGE plpgsql;`,
+		`CREATE TRIGGER insert_on_living_venture
+			AFTER INSERT ON venture
+			FOR EACH ROW
+			WHEN (NEW.is_dead = false)
+			EXECUTE PROCEDURE upsert_ql_venture();`,
+		`CREATE FUNCTION delete_ql_venture() RETURNS TRIGGER AS $$
+			BEGIN
+				DELETE FROM ql_venture
+				WHERE id = NEW.id;
+				RETURN NULL;
+			END;
+		$$ LANGUAGE plpgsql;`,
+		`CREATE TRIGGER insert_on_dead_venture
+			AFTER INSERT ON venture
+			FOR EACH ROW
+			WHEN (NEW.is_dead = true)
+			EXECUTE PROCEDURE delete_ql_venture();`,
+		`CREATE FUNCTION forbid_venture_update() RETURNS TRIGGER AS $$
+			BEGIN
+				RAISE EXCEPTION 'Updates not allowed, insert with the same Venture ID!';
+			END;
+		$$ LANGUAGE plpgsql;`,
+		`CREATE TRIGGER update_on_venture
+			BEFORE UPDATE ON venture
+			FOR EACH ROW
+			EXECUTE PROCEDURE forbid_venture_update();`,
+		`CREATE FUNCTION forbid_venture_delete() RETURNS TRIGGER AS $$
+			BEGIN
+				RAISE EXCEPTION 'Deletions not allowed!';
+			END;
+		$$ LANGUAGE plpgsql;`,
+		`CREATE TRIGGER delete_on_venture
+			BEFORE DELETE ON venture
+			FOR EACH ROW
+			EXECUTE PROCEDURE forbid_venture_delete();`,
+	}
+
+	for _, s := range stmts {
+		_, err := db.Exec(s)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// dropPostgresTables drops all the Venture tables, functions and triggers from
+// the supplied PostgreSQL database.
+func dropPostgresTables(db database.Executor) error {
+	stmts := []string{
+		`DROP TABLE IF EXISTS ql_venture;`,
+		`DROP TABLE IF EXISTS venture;`,
+		`DROP FUNCTION IF EXISTS upsert_ql_venture();`,
+		`DROP FUNCTION IF EXISTS delete_ql_venture();`,
+		`DROP FUNCTION IF EXISTS forbid_venture_update();`,
+		`DROP FUNCTION IF EXISTS forbid_venture_delete();`,
+	}
+
+	for _, s := range stmts {
+		_, err := db.Exec(s)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
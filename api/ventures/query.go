@@ -0,0 +1,151 @@
+package ventures
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/PaulioRandall/go-qlueless-api/api/workflow"
+)
+
+// Query represents the criteria used to find living Ventures. The zero value
+// matches every living Venture ordered by ID.
+//
+// ModifiedSince is inclusive while ModifiedBefore is exclusive, both are Unix
+// times in milliseconds where zero means unbounded. Search matches Ventures
+// whose description or extra contain it regardless of case.
+//
+// If Limit is positive, or Cursor is not nil, only a page of the Ventures is
+// found. Pages are ordered by last modified time then ID, Sort is ignored, and
+// hold at most Limit Ventures, starting from the Cursor if there is one.
+type Query struct {
+	IDs            []string
+	States         []string
+	ModifiedSince  int64
+	ModifiedBefore int64
+	HasOrder       string
+	Search         string
+	Sort           []SortKey
+	Limit          int
+	Cursor         *Cursor
+}
+
+// SortKey represents a single property, and direction, Ventures are ordered
+// by.
+type SortKey struct {
+	Prop string
+	Desc bool
+}
+
+// sortable holds the names of the properties Ventures may be ordered by.
+var sortable = []string{"id", "last_modified", "description", "state"}
+
+// projectable holds the names of the properties Ventures may be projected
+// onto.
+var projectable = []string{"id", "last_modified", "description", "orders", "state", "dead", "extra"}
+
+// ParseSort parses a CSV of property names into SortKeys. Each name may be
+// prefixed with '-' to sort descending, e.g. 'last_modified,-id'.
+func ParseSort(csv string) ([]SortKey, error) {
+	keys := []SortKey{}
+	if csv == "" {
+		return keys, nil
+	}
+
+	seen := map[string]bool{}
+	for _, p := range strings.Split(csv, ",") {
+		k := SortKey{Prop: p}
+		if strings.HasPrefix(p, "-") {
+			k = SortKey{Prop: p[1:], Desc: true}
+		}
+
+		switch {
+		case !isSortable(k.Prop):
+			return nil, fmt.Errorf("Ventures can't be sorted by '%s', use one of '%s'",
+				k.Prop, strings.Join(sortable, "', '"))
+		case seen[k.Prop]:
+			return nil, fmt.Errorf("Ventures can't be sorted by '%s' more than once", k.Prop)
+		}
+
+		seen[k.Prop] = true
+		keys = append(keys, k)
+	}
+
+	return keys, nil
+}
+
+// isSortable is a package private function that returns true if Ventures may be
+// ordered by the property 'prop'.
+func isSortable(prop string) bool {
+	for _, p := range sortable {
+		if p == prop {
+			return true
+		}
+	}
+	return false
+}
+
+// Paged returns true if only a page of the Ventures matching the Query should
+// be found.
+func (q *Query) Paged() bool {
+	return q.Limit > 0 || q.Cursor != nil
+}
+
+// HasFilters returns true if the Query narrows the Ventures found by anything
+// other than their IDs.
+func (q *Query) HasFilters() bool {
+	return len(q.States) > 0 ||
+		q.ModifiedSince > 0 ||
+		q.ModifiedBefore > 0 ||
+		q.HasOrder != "" ||
+		q.Search != ""
+}
+
+// Matches returns true if the living Venture 'ven' meets every criteria of the
+// Query. The Cursor and Limit are ignored.
+func (q *Query) Matches(ven *Venture) bool {
+	switch {
+	case len(q.IDs) > 0 && !contains(q.IDs, ven.ID):
+		return false
+	case len(q.States) > 0 && !contains(q.States, ven.State):
+		return false
+	case q.ModifiedSince > 0 && ven.LastModified < q.ModifiedSince:
+		return false
+	case q.ModifiedBefore > 0 && ven.LastModified >= q.ModifiedBefore:
+		return false
+	case q.HasOrder != "" && !contains(ven.SplitOrders(), q.HasOrder):
+		return false
+	case q.Search != "":
+		s := strings.ToLower(q.Search)
+		return strings.Contains(strings.ToLower(ven.Description), s) ||
+			strings.Contains(strings.ToLower(ven.Extra), s)
+	}
+	return true
+}
+
+// SortVentures orders 'vens' by the SortKeys 'keys' with states ordered as
+// they are within the workflow 'wf'. Ventures equal by every key are ordered
+// by ID.
+func SortVentures(wf *workflow.Workflow, vens []Venture, keys []SortKey) {
+	sort.SliceStable(vens, func(i, j int) bool {
+		for _, k := range keys {
+			c := comparatorOf(wf, k.Prop)(&vens[i], &vens[j])
+			if c == 0 {
+				continue
+			}
+			return (c < 0) != k.Desc
+		}
+		return CompareID(&vens[i], &vens[j]) < 0
+	})
+}
+
+// contains is a package private function that returns true if 's' is within
+// 'vals'.
+func contains(vals []string, s string) bool {
+	for _, v := range vals {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,781 @@
+package ventures
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PaulioRandall/go-cookies/cookies"
+	"github.com/PaulioRandall/go-qlueless-api/api/database"
+	"github.com/PaulioRandall/go-qlueless-api/api/workflow"
+)
+
+// SQLStore is a VentureStore backed by the Venture tables of a SQLite or
+// PostgreSQL database, see CreateTables().
+type SQLStore struct {
+	db *sql.DB
+	d  database.Dialect
+	wf *workflow.Workflow
+}
+
+// NewSQLStore returns a new SQLStore that reads and writes Ventures
+// within 'db', of the SQL dialect 'd', ordering states as they are within the
+// workflow 'wf'.
+func NewSQLStore(db *sql.DB, d database.Dialect, wf *workflow.Workflow) *SQLStore {
+	return &SQLStore{
+		db: db,
+		d:  d,
+		wf: wf,
+	}
+}
+
+// Get implements VentureStore.
+func (s *SQLStore) Get(id string) (*Venture, error) {
+	ven := Venture{}
+	err := s.db.QueryRow(`SELECT
+		id,
+		last_modified,
+		description,
+		order_ids,
+		state,
+		extra
+	FROM ql_venture
+	WHERE id = $1`, id).Scan(&ven.ID,
+		&ven.LastModified,
+		&ven.Description,
+		&ven.Orders,
+		&ven.State,
+		&ven.Extra)
+
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, nil
+	case cookies.LogIfErr(err):
+		return nil, err
+	}
+
+	return &ven, nil
+}
+
+// List implements VentureStore.
+func (s *SQLStore) List(ids []string) ([]Venture, error) {
+	return s.list(s.db, ids)
+}
+
+// Find implements VentureStore.
+func (s *SQLStore) Find(q *Query) ([]Venture, error) {
+	return s.findMatching(s.db, q)
+}
+
+// Count implements VentureStore.
+func (s *SQLStore) Count(q *Query) (int, error) {
+	all := *q
+	all.Cursor = nil
+	where, args := whereClause(&all)
+
+	var n int
+	err := s.db.QueryRow(`SELECT COUNT(*) FROM ql_venture `+where, args...).Scan(&n)
+	if cookies.LogIfErr(err) {
+		return 0, err
+	}
+
+	return n, nil
+}
+
+// Create implements VentureStore.
+func (s *SQLStore) Create(nv *NewVenture) (*Venture, error) {
+	vens, err := s.create([]NewVenture{*nv}, false)
+	if err != nil || len(vens) == 0 {
+		return nil, err
+	}
+	return &vens[0], nil
+}
+
+// CreateAll implements VentureStore. Each ID is allocated and each Venture
+// inserted within a single transaction so concurrent creates never share an
+// ID.
+func (s *SQLStore) CreateAll(nvs []NewVenture) ([]Venture, error) {
+	return s.create(nvs, true)
+}
+
+// create is a file private function that performs the work of Create() and
+// CreateAll(). The Orders referenced are checked after taking the write lock so
+// they can't die before the Ventures are inserted. 'indexed' is passed to
+// checkNewOrders().
+func (s *SQLStore) create(nvs []NewVenture, indexed bool) ([]Venture, error) {
+	ids := make([]string, len(nvs))
+
+	err := database.InTx(s.db, func(tx *sql.Tx) error {
+		err := lockWrites(tx)
+		if err != nil {
+			return err
+		}
+
+		err = checkNewOrders(nvs, indexed, func(orderIDs []string) ([]string, error) {
+			return missingOrders(tx, orderIDs)
+		})
+		if err != nil {
+			return err
+		}
+
+		stmt, err := tx.Prepare(`INSERT INTO venture (
+			id, last_modified, description, order_ids, state, extra
+		) VALUES (
+			$1, $2, $3, $4, $5, $6
+		);`)
+
+		if stmt != nil {
+			defer stmt.Close()
+		}
+
+		if err != nil {
+			return err
+		}
+
+		now := cookies.ToUnixMilli(time.Now())
+		for i, nv := range nvs {
+			ids[i], err = nextID(tx)
+			if err != nil {
+				return err
+			}
+
+			_, err = stmt.Exec(ids[i], now, nv.Description, nv.Orders, nv.State, nv.Extra)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if _, ok := err.(*InvalidError); ok {
+		return nil, err
+	}
+
+	if cookies.LogIfErr(err) {
+		return nil, err
+	}
+
+	vens, err := s.list(s.db, ids)
+	if cookies.LogIfErr(err) {
+		return nil, err
+	}
+
+	return inOrderOf(ids, vens), nil
+}
+
+// Modify implements VentureStore. The Ventures are read, modified, and their
+// new revisions inserted within a single transaction so either every Venture
+// is modified or none are. Concurrent writers wait for the transaction to end
+// rather than interleave with it.
+func (s *SQLStore) Modify(mv *ModVenture) ([]Venture, error) {
+	var vens []Venture
+
+	err := database.InTx(s.db, func(tx *sql.Tx) (err error) {
+		vens, err = s.modify(tx, mv)
+		return
+	})
+
+	switch err.(type) {
+	case *ConflictError, *NotFoundError, *InvalidError:
+		return nil, err
+	}
+
+	if cookies.LogIfErr(err) {
+		return nil, err
+	}
+
+	return vens, nil
+}
+
+// Kill implements VentureStore.
+func (s *SQLStore) Kill(ids []string) ([]Venture, error) {
+	return s.Modify(killMod(ids))
+}
+
+// History implements VentureStore.
+func (s *SQLStore) History(ids []string, since int64, until int64) ([]Venture, error) {
+	idFilter := ""
+	if len(ids) > 0 {
+		idFilter = fmt.Sprintf("AND id IN (%s)", database.Params(3, len(ids)))
+	}
+
+	sql := fmt.Sprintf(`SELECT
+			id,
+			last_modified,
+			description,
+			order_ids,
+			state,
+			is_dead,
+			extra
+		FROM venture
+		WHERE last_modified >= $1
+		AND last_modified <= $2
+		%s
+		ORDER BY id ASC, last_modified ASC`, idFilter)
+
+	args := append([]interface{}{since, until}, toArgs(ids)...)
+	rows, err := s.db.Query(sql, args...)
+
+	if rows != nil {
+		defer rows.Close()
+	}
+
+	if cookies.LogIfErr(err) {
+		return nil, err
+	}
+
+	return mapRevisionRows(rows)
+}
+
+// AsOf implements VentureStore.
+func (s *SQLStore) AsOf(ids []string, asOf int64) ([]Venture, error) {
+	idFilter := ""
+	if len(ids) > 0 {
+		idFilter = fmt.Sprintf("AND v.id IN (%s)", database.Params(2, len(ids)))
+	}
+
+	sql := fmt.Sprintf(`SELECT
+			v.id,
+			v.last_modified,
+			v.description,
+			v.order_ids,
+			v.state,
+			v.extra
+		FROM venture v
+		INNER JOIN (
+			SELECT id, MAX(last_modified) AS last_modified
+			FROM venture
+			WHERE last_modified <= $1
+			GROUP BY id
+		) latest
+		ON v.id = latest.id
+		AND v.last_modified = latest.last_modified
+		WHERE v.is_dead = false
+		%s
+		ORDER BY v.id ASC`, idFilter)
+
+	args := append([]interface{}{asOf}, toArgs(ids)...)
+	rows, err := s.db.Query(sql, args...)
+
+	if rows != nil {
+		defer rows.Close()
+	}
+
+	if cookies.LogIfErr(err) {
+		return nil, err
+	}
+
+	return mapRows(rows)
+}
+
+// MissingOrders implements VentureStore.
+func (s *SQLStore) MissingOrders(ids []string) ([]string, error) {
+	return missingOrders(s.db, ids)
+}
+
+// missingOrders is a file private function that queries 'q' for the Order IDs,
+// within 'ids', that do not belong to any living Order.
+func missingOrders(q database.Executor, ids []string) ([]string, error) {
+	if len(ids) == 0 {
+		return []string{}, nil
+	}
+
+	sql := fmt.Sprintf(`SELECT id
+		FROM ql_order
+		WHERE id IN (%s)`, database.Params(1, len(ids)))
+
+	rows, err := q.Query(sql, toArgs(ids)...)
+
+	if rows != nil {
+		defer rows.Close()
+	}
+
+	if cookies.LogIfErr(err) {
+		return nil, err
+	}
+
+	found := map[string]bool{}
+	for rows.Next() {
+		var id string
+		err = rows.Scan(&id)
+		if err != nil {
+			return nil, err
+		}
+		found[id] = true
+	}
+
+	missing := []string{}
+	for _, id := range ids {
+		if !found[id] {
+			missing = append(missing, id)
+		}
+	}
+
+	return missing, nil
+}
+
+// PruneOrders implements VentureStore. A single modification covers every
+// Venture changed.
+func (s *SQLStore) PruneOrders(tx *sql.Tx, orderIDs []string) error {
+	err := lockWrites(tx)
+	if err != nil {
+		return err
+	}
+
+	vens, err := s.list(tx, nil)
+	if err != nil {
+		return err
+	}
+
+	ids := referencing(vens, orderIDs)
+	if len(ids) == 0 {
+		return nil
+	}
+
+	_, err = s.modify(tx, pruneMod(ids, orderIDs))
+	return err
+}
+
+// modify is a file private function that applies the modification 'mv' within
+// the transaction 'tx' returning the new revision of each Venture modified. The
+// Ventures are read and checked after taking the write lock so no other writer
+// can invalidate the checks before the new revisions are inserted.
+func (s *SQLStore) modify(tx *sql.Tx, mv *ModVenture) ([]Venture, error) {
+	err := lockWrites(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	vens, err := s.findToModify(tx, mv)
+	if err != nil {
+		return nil, err
+	}
+
+	err = mv.check(vens)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkTransitions(s.wf, mv, vens)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkModOrders(mv, func(orderIDs []string) ([]string, error) {
+		return missingOrders(tx, orderIDs)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = insertEach(tx, mv, vens, cookies.ToUnixMilli(time.Now()))
+	if err != nil {
+		return nil, err
+	}
+
+	return vens, nil
+}
+
+// lockWrites is a file private function that takes the write lock, within the
+// transaction 'tx', that every transaction writing Ventures must hold. Only one
+// transaction may hold it at a time so reads made after taking it can't be
+// invalidated by another writer before the transaction ends.
+func lockWrites(tx *sql.Tx) error {
+	_, err := tx.Exec(`UPDATE venture_seq SET last_id = last_id;`)
+	return err
+}
+
+// nextID is a file private function that allocates the next free Venture ID
+// within the transaction 'tx'. Incrementing the sequence takes the same lock
+// as lockWrites() so concurrent transactions wait their turn rather than read
+// the same ID.
+func nextID(tx *sql.Tx) (string, error) {
+	_, err := tx.Exec(`UPDATE venture_seq SET last_id = last_id + 1;`)
+	if err != nil {
+		return "", err
+	}
+
+	var id int64
+	err = tx.QueryRow(`SELECT last_id FROM venture_seq;`).Scan(&id)
+	if err != nil {
+		return "", err
+	}
+
+	return strconv.FormatInt(id, 10), nil
+}
+
+// list is a file private function that queries 'q' for the living Ventures
+// with the IDs within 'ids' or every living Venture if 'ids' is empty.
+func (s *SQLStore) list(q database.Executor, ids []string) ([]Venture, error) {
+	return s.findMatching(q, &Query{IDs: ids})
+}
+
+// findMatching is a file private function that queries 'q' for the living
+// Ventures matching the Query 'qry'. Every criteria is applied by the database.
+func (s *SQLStore) findMatching(q database.Executor, qry *Query) ([]Venture, error) {
+	where, args := whereClause(qry)
+	backwards := qry.Cursor != nil && qry.Cursor.Before
+
+	orderBy := orderByClause(s.wf, s.d, qry.Sort)
+	switch {
+	case backwards:
+		orderBy = "last_modified DESC, id DESC"
+	case qry.Paged():
+		orderBy = "last_modified ASC, id ASC"
+	}
+
+	limit := ""
+	if qry.Limit > 0 {
+		limit = fmt.Sprintf("LIMIT %d", qry.Limit)
+	}
+
+	sql := fmt.Sprintf(`SELECT
+			id,
+			last_modified,
+			description,
+			order_ids,
+			state,
+			extra
+		FROM ql_venture
+		%s
+		ORDER BY %s
+		%s`, where, orderBy, limit)
+
+	rows, err := q.Query(sql, args...)
+
+	if rows != nil {
+		defer rows.Close()
+	}
+
+	if cookies.LogIfErr(err) {
+		return nil, err
+	}
+
+	vens, err := mapRows(rows)
+	if err != nil || !backwards {
+		return vens, err
+	}
+
+	for i, j := 0, len(vens)-1; i < j; i, j = i+1, j-1 {
+		vens[i], vens[j] = vens[j], vens[i]
+	}
+	return vens, nil
+}
+
+// findToModify is a file private function that queries 'q' for the Ventures
+// to modify. Only living Ventures are returned unless the
+// modification restores Ventures in which case the latest revision of each,
+// dead or alive, is returned. The Orders of restored Ventures that have since
+// died are pruned unless the modification sets the Orders itself.
+func (s *SQLStore) findToModify(q database.Executor, mv *ModVenture) ([]Venture, error) {
+	if !mv.Restores() {
+		return s.list(q, mv.SplitIDs())
+	}
+
+	vens, err := findLatest(q, mv.SplitIDs())
+	if err != nil {
+		return nil, err
+	}
+
+	if mv.Sets("orders") {
+		return vens, nil
+	}
+
+	err = pruneRestored(vens, func(ids []string) ([]string, error) {
+		return missingOrders(q, ids)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return vens, nil
+}
+
+// findLatest is a file private function that queries 'q' for the latest
+// revision, dead or alive, of each of the specified Ventures.
+func findLatest(q database.Executor, ids []string) ([]Venture, error) {
+	sql := fmt.Sprintf(`SELECT
+			v.id,
+			v.last_modified,
+			v.description,
+			v.order_ids,
+			v.state,
+			v.is_dead,
+			v.extra
+		FROM venture v
+		INNER JOIN (
+			SELECT id, MAX(last_modified) AS last_modified
+			FROM venture
+			WHERE id IN (%s)
+			GROUP BY id
+		) latest
+		ON v.id = latest.id
+		AND v.last_modified = latest.last_modified`, database.Params(1, len(ids)))
+
+	rows, err := q.Query(sql, toArgs(ids)...)
+
+	if rows != nil {
+		defer rows.Close()
+	}
+
+	if cookies.LogIfErr(err) {
+		return nil, err
+	}
+
+	return mapRevisionRows(rows)
+}
+
+// insertEach is a file private function that applies the modification 'mv' to
+// each Venture within 'vens' then inserts the resultant revisions using 'q'.
+// Each revision is stamped with the Unix time 'now', see stamp(), which must be
+// taken after the write lock so concurrent writers can't reuse a time.
+func insertEach(q database.Executor, mv *ModVenture, vens []Venture, now int64) error {
+	stmt, err := q.Prepare(`INSERT INTO venture
+			(id, last_modified, description, order_ids, state, is_dead, extra)
+		VALUES
+			($1, $2, $3, $4, $5, $6, $7);`)
+
+	if stmt != nil {
+		defer stmt.Close()
+	}
+
+	if err != nil {
+		return err
+	}
+
+	for i := range vens {
+		ven := &vens[i]
+		mv.ApplyMod(ven)
+		stamp(ven, now)
+
+		_, err := stmt.Exec(ven.ID,
+			ven.LastModified,
+			ven.Description,
+			ven.Orders,
+			ven.State,
+			ven.Dead,
+			ven.Extra)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// whereClause is a file private function that returns the WHERE clause, and
+// its arguments, that filters the ql_venture table by the criteria of 'qry'
+// including its Cursor. An empty clause is returned if there are no criteria.
+func whereClause(qry *Query) (string, []interface{}) {
+	conds := []string{}
+	args := []interface{}{}
+
+	param := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if len(qry.IDs) > 0 {
+		conds = append(conds, fmt.Sprintf("id IN (%s)", database.Params(len(args)+1, len(qry.IDs))))
+		args = append(args, toArgs(qry.IDs)...)
+	}
+
+	if len(qry.States) > 0 {
+		conds = append(conds, fmt.Sprintf("state IN (%s)", database.Params(len(args)+1, len(qry.States))))
+		args = append(args, toArgs(qry.States)...)
+	}
+
+	if qry.ModifiedSince > 0 {
+		conds = append(conds, "last_modified >= "+param(qry.ModifiedSince))
+	}
+
+	if qry.ModifiedBefore > 0 {
+		conds = append(conds, "last_modified < "+param(qry.ModifiedBefore))
+	}
+
+	if qry.HasOrder != "" {
+		conds = append(conds, "(',' || order_ids || ',') LIKE "+param("%,"+qry.HasOrder+",%"))
+	}
+
+	if qry.Search != "" {
+		p := param("%" + escapeLike(strings.ToLower(qry.Search)) + "%")
+		conds = append(conds, fmt.Sprintf(`(LOWER(description) LIKE %s ESCAPE '\'
+			OR LOWER(extra) LIKE %s ESCAPE '\')`, p, p))
+	}
+
+	if c := qry.Cursor; c != nil {
+		op := ">"
+		if c.Before {
+			op = "<"
+		}
+
+		lm, id := param(c.LastModified), param(c.ID)
+		conds = append(conds, fmt.Sprintf("(last_modified %s %s OR (last_modified = %s AND id %s %s))",
+			op, lm, lm, op, id))
+	}
+
+	if len(conds) == 0 {
+		return "", args
+	}
+
+	return "WHERE " + strings.Join(conds, " AND "), args
+}
+
+// orderByClause is a file private function that returns the ORDER BY clause,
+// without the keywords, that orders the ql_venture table by 'keys' as their
+// Comparators would, states as they are within the workflow 'wf'. Text is
+// compared byte-wise in the SQL dialect 'd'. Keys of unsortable properties are
+// ignored and Ventures are always ordered by ID last so the order is
+// deterministic.
+func orderByClause(wf *workflow.Workflow, d database.Dialect, keys []SortKey) string {
+	terms := []string{}
+	byID := false
+
+	for _, k := range keys {
+		if !isSortable(k.Prop) {
+			continue
+		}
+
+		dir := "ASC"
+		if k.Desc {
+			dir = "DESC"
+		}
+
+		switch k.Prop {
+		case "state":
+			terms = append(terms, stateRankExpr(wf)+" "+dir)
+			terms = append(terms, k.Prop+" "+byteWise(d)+" "+dir)
+		case "description":
+			terms = append(terms, k.Prop+" "+byteWise(d)+" "+dir)
+		default:
+			terms = append(terms, k.Prop+" "+dir)
+		}
+
+		byID = byID || k.Prop == "id"
+	}
+
+	if !byID {
+		terms = append(terms, "id ASC")
+	}
+
+	return strings.Join(terms, ", ")
+}
+
+// byteWise is a file private function that returns the COLLATE clause that
+// compares text byte-wise, as the Comparators do, in the SQL dialect
+// 'd'. The default collation of a PostgreSQL database depends on its locale.
+func byteWise(d database.Dialect) string {
+	if d == database.Postgres {
+		return `COLLATE "C"`
+	}
+	return "COLLATE BINARY"
+}
+
+// stateRankExpr is a file private function that returns a SQL expression
+// evaluating to the position of a Ventures state within the workflow 'wf', see
+// CompareState().
+func stateRankExpr(wf *workflow.Workflow) string {
+	b := strings.Builder{}
+	b.WriteString("CASE state")
+	for i, st := range wf.States {
+		fmt.Fprintf(&b, " WHEN '%s' THEN %d", strings.Replace(st, "'", "''", -1), i)
+	}
+	fmt.Fprintf(&b, " ELSE %d END", len(wf.States))
+
+	return b.String()
+}
+
+// escapeLike is a file private function that escapes the LIKE wildcards within
+// 's' so they match literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// inOrderOf is a file private function that returns the Ventures within 'vens'
+// ordered as their IDs are within 'ids'.
+func inOrderOf(ids []string, vens []Venture) []Venture {
+	byID := make(map[string]Venture, len(vens))
+	for _, ven := range vens {
+		byID[ven.ID] = ven
+	}
+
+	ordered := []Venture{}
+	for _, id := range ids {
+		if ven, ok := byID[id]; ok {
+			ordered = append(ordered, ven)
+		}
+	}
+
+	return ordered
+}
+
+// toArgs is a file private function that converts a slice of IDs into a slice
+// of query arguments.
+func toArgs(ids []string) []interface{} {
+	args := make([]interface{}, len(ids))
+	for i := range ids {
+		args[i] = ids[i]
+	}
+	return args
+}
+
+// mapRows is a file private function that maps rows from a database query into
+// a slice of Ventures.
+func mapRows(rows *sql.Rows) ([]Venture, error) {
+	vens := []Venture{}
+
+	for rows.Next() {
+		ven, err := mapRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		vens = append(vens, *ven)
+	}
+
+	return vens, nil
+}
+
+// mapRevisionRows is a file private function that maps rows from a database
+// query of Venture revisions into a slice of Ventures.
+func mapRevisionRows(rows *sql.Rows) ([]Venture, error) {
+	vens := []Venture{}
+
+	for rows.Next() {
+		ven := Venture{}
+		err := rows.Scan(&ven.ID,
+			&ven.LastModified,
+			&ven.Description,
+			&ven.Orders,
+			&ven.State,
+			&ven.Dead,
+			&ven.Extra)
+
+		if err != nil {
+			return nil, err
+		}
+		vens = append(vens, ven)
+	}
+
+	return vens, nil
+}
+
+// mapRow is a file private function that maps a single row from a database
+// query into a Venture.
+func mapRow(rows *sql.Rows) (*Venture, error) {
+	ven := Venture{}
+	err := rows.Scan(&ven.ID,
+		&ven.LastModified,
+		&ven.Description,
+		&ven.Orders,
+		&ven.State,
+		&ven.Extra)
+
+	if err != nil {
+		return nil, err
+	}
+	return &ven, err
+}
@@ -0,0 +1,281 @@
+package ventures
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/PaulioRandall/go-qlueless-api/api/workflow"
+	"github.com/PaulioRandall/go-qlueless-api/shared/wrapped"
+)
+
+// VentureStore represents a store of Ventures and every revision made to them.
+// Ventures are never removed from a store, killing a Venture adds a dead
+// revision of it so its history is never lost.
+type VentureStore interface {
+
+	// Get returns the living Venture with the ID 'id' or nil if there is no
+	// such Venture.
+	Get(id string) (*Venture, error)
+
+	// List returns the living Ventures with the IDs within 'ids' or every
+	// living Venture if 'ids' is empty.
+	List(ids []string) ([]Venture, error)
+
+	// Find returns the living Ventures that match the Query 'q' in the order it
+	// specifies.
+	Find(q *Query) ([]Venture, error)
+
+	// Count returns the number of living Ventures that match the Query 'q'
+	// ignoring its Cursor and Limit.
+	Count(q *Query) (int, error)
+
+	// Create adds the NewVenture 'nv' to the store, assigning it the next free
+	// ID, returning the resultant Venture. An *InvalidError is returned, and
+	// nothing created, if it references Orders that aren't living.
+	Create(nv *NewVenture) (*Venture, error)
+
+	// CreateAll adds every NewVenture within 'nvs' to the store, assigning each
+	// the next free ID, returning the resultant Ventures in the same order.
+	// Either every Venture is created or none are. An *InvalidError is
+	// returned, and nothing created, if any reference Orders that aren't
+	// living; the field and message of each Violation are prefixed with the
+	// index of the NewVenture it applies to.
+	CreateAll(nvs []NewVenture) ([]Venture, error)
+
+	// Modify applies the modification 'mv' to the Ventures it identifies
+	// returning the new revision of each. Only living Ventures are modified
+	// unless 'mv' restores dead Ventures. A *ConflictError is returned, and
+	// nothing modified, if 'mv' expects revisions other than the latest. A
+	// *NotFoundError is returned, and nothing modified, if 'mv' is strict and
+	// any of the Ventures it identifies could not be found. An *InvalidError is
+	// returned, and nothing modified, if 'mv' sets Orders that aren't living or
+	// moves a Venture, dead or alive, to a state the workflow does not allow it
+	// to move to from its current state.
+	Modify(mv *ModVenture) ([]Venture, error)
+
+	// Kill marks the living Ventures with the IDs within 'ids' as dead
+	// returning the new revision of each.
+	Kill(ids []string) ([]Venture, error)
+
+	// History returns every revision, dead or alive, of the Ventures with the
+	// IDs within 'ids', or every Venture if 'ids' is empty, made between
+	// 'since' and 'until' inclusive. The revisions are ordered by Venture ID
+	// then by the time they were made.
+	History(ids []string, since int64, until int64) ([]Venture, error)
+
+	// AsOf returns the living Ventures, with the IDs within 'ids' or every
+	// Venture if 'ids' is empty, as they were at the Unix time 'asOf' in
+	// milliseconds.
+	AsOf(ids []string, asOf int64) ([]Venture, error)
+
+	// MissingOrders returns the Order IDs, within 'ids', that do not belong to
+	// any living Order.
+	MissingOrders(ids []string) ([]string, error)
+
+	// PruneOrders removes the Order IDs within 'orderIDs' from every living
+	// Venture that references them. Stores backed by the database holding the
+	// Orders do so within the transaction 'tx' so it may be shared with the
+	// killing of the Orders.
+	PruneOrders(tx *sql.Tx, orderIDs []string) error
+}
+
+// NotFoundError is returned when Ventures that must exist could not be found.
+type NotFoundError struct {
+	IDs []string
+}
+
+// Error implements error.
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("The following Venture IDs could not be found '%s'",
+		strings.Join(e.IDs, ", "))
+}
+
+// InvalidError is returned when a creation or modification is invalid given the
+// current contents of the store, e.g. it references Orders that aren't living.
+// The Violations are checked while holding the write lock so they can't be
+// invalidated by another writer before the change is made. Fields are named
+// as the properties of a Venture.
+type InvalidError struct {
+	Violations wrapped.Violations
+}
+
+// Error implements error.
+func (e *InvalidError) Error() string {
+	return strings.Join(e.Violations.Messages(), " ")
+}
+
+// checkNewOrders is a file private function that returns an *InvalidError if
+// any NewVenture within 'nvs' references Orders that aren't living. If
+// 'indexed' is true the field and message of each Violation are prefixed with
+// the index of the NewVenture it applies to. 'missingOrders' returns the Order
+// IDs, within those supplied, that do not belong to any living Order.
+func checkNewOrders(nvs []NewVenture, indexed bool, missingOrders func([]string) ([]string, error)) error {
+	r := wrapped.Violations{}
+
+	for i, nv := range nvs {
+		if nv.Orders == "" {
+			continue
+		}
+
+		missing, err := missingOrders(strings.Split(nv.Orders, ","))
+		if err != nil {
+			return err
+		}
+
+		if len(missing) == 0 {
+			continue
+		}
+
+		field, prefix := "orders", ""
+		if indexed {
+			field, prefix = fmt.Sprintf("[%d].orders", i), fmt.Sprintf("[%d] ", i)
+		}
+
+		r.Add(field, "not_found", prefix+missingOrdersMsg(missing))
+	}
+
+	if len(r) > 0 {
+		return &InvalidError{Violations: r}
+	}
+	return nil
+}
+
+// checkModOrders is a file private function that returns an *InvalidError if
+// the modification 'mv' sets Orders that aren't living. 'missingOrders' returns
+// the Order IDs, within those supplied, that do not belong to any living Order.
+func checkModOrders(mv *ModVenture, missingOrders func([]string) ([]string, error)) error {
+	if !mv.Sets("orders") || mv.Values.Orders == "" {
+		return nil
+	}
+
+	missing, err := missingOrders(strings.Split(mv.Values.Orders, ","))
+	if err != nil || len(missing) == 0 {
+		return err
+	}
+
+	r := wrapped.Violations{}
+	r.Add("orders", "not_found", missingOrdersMsg(missing))
+	return &InvalidError{Violations: r}
+}
+
+// checkTransitions is a file private function that returns an *InvalidError if
+// the modification 'mv' moves any Venture within 'vens', dead or alive, to a
+// state the workflow 'wf' does not allow it to move to from its current state.
+func checkTransitions(wf *workflow.Workflow, mv *ModVenture, vens []Venture) error {
+	if !mv.Sets("state") {
+		return nil
+	}
+
+	bad := []string{}
+	for _, ven := range vens {
+		if !wf.Allows(ven.State, mv.Values.State) {
+			bad = append(bad, fmt.Sprintf("%s: %s -> %s", ven.ID, ven.State, mv.Values.State))
+		}
+	}
+
+	if len(bad) == 0 {
+		return nil
+	}
+
+	r := wrapped.Violations{}
+	r.Add("state", "transition", fmt.Sprintf("Ventures may only move"+
+		" between states allowed by the workflow, the following transitions"+
+		" are not allowed '%s'", strings.Join(bad, ", ")))
+	return &InvalidError{Violations: r}
+}
+
+// missingOrdersMsg is a file private function that returns the message of a
+// Violation for referencing the Orders, with IDs within 'missing', that aren't
+// living.
+func missingOrdersMsg(missing []string) string {
+	return fmt.Sprintf("Ventures may only reference existing Orders, the"+
+		" following Order IDs could not be found '%s'.", strings.Join(missing, ", "))
+}
+
+// stamp is a package private function that sets the last modified time of the
+// Venture 'ven', about to become a new revision, to the Unix time 'now' in
+// milliseconds. If the revision it replaces was made at or after 'now' then a
+// millisecond after it is used instead so no two revisions of a Venture share
+// a last modified time.
+func stamp(ven *Venture, now int64) {
+	if now <= ven.LastModified {
+		now = ven.LastModified + 1
+	}
+	ven.LastModified = now
+}
+
+// pruneMod is a file private function that returns the modification that
+// removes the Order IDs within 'orderIDs' from the Ventures with the IDs within
+// 'ids'.
+func pruneMod(ids []string, orderIDs []string) *ModVenture {
+	return &ModVenture{
+		IDs:   strings.Join(ids, ","),
+		Props: "orders",
+		Prune: orderIDs,
+	}
+}
+
+// referencing is a file private function that returns the IDs of the Ventures,
+// within 'vens', that reference any of the Order IDs within 'orderIDs'.
+func referencing(vens []Venture, orderIDs []string) []string {
+	ids := []string{}
+	for _, ven := range vens {
+		orders := ven.SplitOrders()
+		if len(without(orders, orderIDs)) != len(orders) {
+			ids = append(ids, ven.ID)
+		}
+	}
+	return ids
+}
+
+// killMod is a file private function that returns the modification that kills
+// the Ventures with the IDs within 'ids'.
+func killMod(ids []string) *ModVenture {
+	return &ModVenture{
+		IDs:   strings.Join(ids, ","),
+		Props: "dead",
+		Values: Venture{
+			Dead: true,
+		},
+	}
+}
+
+// pruneRestored is a file private function that removes the Orders that have
+// since died from each dead Venture within 'vens' that is about to be restored.
+// 'missingOrders' returns the Order IDs, within those supplied, that do not
+// belong to any living Order.
+func pruneRestored(vens []Venture, missingOrders func([]string) ([]string, error)) error {
+	for i := range vens {
+		if !vens[i].Dead || vens[i].Orders == "" {
+			continue
+		}
+
+		missing, err := missingOrders(vens[i].SplitOrders())
+		if err != nil {
+			return err
+		}
+
+		vens[i].SetOrders(without(vens[i].SplitOrders(), missing))
+	}
+
+	return nil
+}
+
+// without is a file private function that returns 'ids' without any of the IDs
+// within 'exclude'.
+func without(ids []string, exclude []string) []string {
+	ex := map[string]bool{}
+	for _, id := range exclude {
+		ex[id] = true
+	}
+
+	kept := []string{}
+	for _, id := range ids {
+		if !ex[id] {
+			kept = append(kept, id)
+		}
+	}
+
+	return kept
+}
@@ -2,12 +2,13 @@ package ventures
 
 import (
 	"encoding/json"
+	"fmt"
 	"io"
 	"strings"
 
 	"github.com/PaulioRandall/go-cookies/cookies"
-	"github.com/PaulioRandall/go-cookies/strlist"
-	"github.com/PaulioRandall/go-qlueless-api/api/database"
+	"github.com/PaulioRandall/go-qlueless-api/api/workflow"
+	"github.com/PaulioRandall/go-qlueless-api/shared/wrapped"
 )
 
 // Venture represents a Venture, aka, project.
@@ -42,46 +43,56 @@ func DecodeVentureSlice(r io.Reader) ([]Venture, error) {
 }
 
 // Clean removes redundent whitespace from property values within a Venture
-// except where whitespace is allowable.
-func (ven *Venture) Clean() {
+// except where whitespace is allowable. The state is replaced by its canonical
+// form within the workflow 'wf'.
+func (ven *Venture) Clean(wf *workflow.Workflow) {
 	ven.Description = strings.TrimSpace(ven.Description)
 	ven.ID = strings.TrimSpace(ven.ID)
 	ven.Orders = cookies.StripWhitespace(ven.Orders)
-	ven.State = strings.TrimSpace(ven.State)
+	ven.State = wf.Canonical(strings.TrimSpace(ven.State))
 }
 
-// Validate checks each field contains valid content returning a non-empty
-// slice of human readable error messages detailing the violations found or an
-// empty slice if all is well. These messages are suitable for returning to
-// clients.
-func (ven *Venture) Validate(isNew bool) []string {
-	r := strlist.StrList{}
+// Validate checks each field contains valid content, and the state is within
+// the workflow 'wf', returning the Violations found or an empty slice if all is
+// well. The message of each Violation is suitable for returning to clients.
+func (ven *Venture) Validate(wf *workflow.Workflow, isNew bool) wrapped.Violations {
+	r := wrapped.Violations{}
 
 	if ven.Description == "" {
-		r.Add("Ventures must have a description.")
+		r.Add("description", "required", "Ventures must have a description.")
 	}
 
 	if !isNew {
 		if !cookies.IsUint(ven.ID) {
-			r.Add("Ventures must have a positive integer ID.")
+			r.Add("id", "invalid", "Ventures must have a positive integer ID.")
 		}
 
 		if ven.LastModified < 1 {
-			r.Add("Ventures must have a last modified Unix date in milliseconds")
+			r.Add("last_modified", "invalid", "Ventures must have a last modified Unix date in milliseconds")
 		}
 	}
 
 	if ven.Orders != "" {
 		if !cookies.IsUintCSV(ven.Orders) {
-			r.Add("Child Orders within a Venture must all be positive integers.")
+			r.Add("orders", "invalid", "Child Orders within a Venture must all be positive integers.")
 		}
 	}
 
-	if ven.State == "" {
-		r.Add("Ventures must have a state.")
-	}
+	validateState(wf, "state", ven.State, &r)
+	return r
+}
 
-	return r.Slice()
+// validateState is a package private function that checks 'state', the value
+// of the field 'field', is one of the states within the workflow 'wf' adding a
+// Violation to 'r' if not.
+func validateState(wf *workflow.Workflow, field string, state string, r *wrapped.Violations) {
+	switch {
+	case state == "":
+		r.Add(field, "required", "Ventures must have a state.")
+	case !wf.Has(state):
+		r.Add(field, "not_in_workflow", fmt.Sprintf("Ventures must have a state"+
+			" within the workflow, i.e. one of '%s'.", strings.Join(wf.States, "', '")))
+	}
 }
 
 // SplitOrders returns the IDs of the Orders as a slice.
@@ -97,32 +108,6 @@ func (ven *Venture) SetOrders(ids []string) {
 	ven.Orders = strings.Join(ids, ",")
 }
 
-// Update updates the Venture within the database.
-func (ven *Venture) Update() error {
-	stmt, err := database.Get().Prepare(`INSERT INTO venture (
-		id, description, order_ids, state, is_dead, extra
-	) VALUES (
-		?, ?, ?, ?, ?, ?
-	);`)
-
-	if stmt != nil {
-		defer stmt.Close()
-	}
-
-	if err != nil {
-		return err
-	}
-
-	_, err = stmt.Exec(ven.ID,
-		ven.Description,
-		ven.Orders,
-		ven.State,
-		ven.Dead,
-		ven.Extra)
-
-	return err
-}
-
 // ByVenID is a slice of Ventures
 type ByVenID []Venture
 
@@ -138,5 +123,5 @@ func (bv ByVenID) Swap(i, j int) {
 
 // Less implements from sort.Interface
 func (bv ByVenID) Less(i, j int) bool {
-	return bv[i].ID < bv[j].ID
+	return CompareID(&bv[i], &bv[j]) < 0
 }
@@ -0,0 +1,46 @@
+package workflow
+
+import (
+	"net/http"
+
+	"github.com/PaulioRandall/go-cookies/uhttp"
+	"github.com/PaulioRandall/go-qlueless-api/shared/writers"
+)
+
+var cors uhttp.CorsHeaders = uhttp.CorsHeaders{
+	Origin:  "*",
+	Headers: "*",
+	Methods: "GET, OPTIONS",
+}
+
+// Handler handles requests for the Venture workflow.
+type Handler struct {
+	wf *Workflow
+}
+
+// NewHandler returns a new Handler that serves the workflow 'wf'.
+func NewHandler(wf *Workflow) *Handler {
+	return &Handler{
+		wf: wf,
+	}
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(res http.ResponseWriter, req *http.Request) {
+	uhttp.LogRequest(req)
+	uhttp.UseCors(&res, &cors)
+
+	switch req.Method {
+	case "GET":
+		get(h.wf, &res, req)
+	case "OPTIONS":
+		res.WriteHeader(http.StatusOK)
+	default:
+		res.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// get handles client requests for the Venture workflow 'wf'.
+func get(wf *Workflow, res *http.ResponseWriter, req *http.Request) {
+	writers.WriteSuccessReply(res, req, http.StatusOK, wf, "Found the Venture workflow")
+}
@@ -0,0 +1,204 @@
+// Package workflow provides the workflow that Ventures move through along with
+// handlers for fetching it. Functionality in this package is primarily tested
+// using API tests within the /tests directory of this project.
+//
+// A workflow lists the valid states of a Venture and the transitions allowed
+// between them. Without one, every spelling of a state, e.g. 'in progress',
+// 'In-Progress', and 'wip', becomes a separate column on a clients board.
+//
+// The workflow is loaded from the configured workflow file, './workflow.json'
+// by default, when that file exists else the default workflow within this
+// package is used. A workflow file that can't be read or is invalid prevents
+// the server from starting.
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/PaulioRandall/go-cookies/strlist"
+)
+
+// Workflow represents the states a Venture may be in and the transitions
+// allowed between them. The first state is the one Ventures wait in before
+// work starts and the 'Done' states are those in which work has finished.
+type Workflow struct {
+	States      []string            `json:"states"`
+	Transitions map[string][]string `json:"transitions"`
+	Done        []string            `json:"done"`
+}
+
+// Default is the workflow used when no workflow file is present.
+var Default = Workflow{
+	States: []string{
+		"Not started",
+		"In progress",
+		"Finished",
+		"Closed",
+	},
+	Transitions: map[string][]string{
+		"Not started": []string{"In progress", "Closed"},
+		"In progress": []string{"Not started", "Finished", "Closed"},
+		"Finished":    []string{"In progress", "Closed"},
+		"Closed":      []string{"Not started"},
+	},
+	Done: []string{
+		"Finished",
+		"Closed",
+	},
+}
+
+// Load loads the workflow from the file at 'path' falling back to the default
+// workflow only if the file does not exist. An error is returned if the file
+// can't be read or decoded or the workflow within it is invalid.
+func Load(path string) (*Workflow, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		wf := Default
+		return &wf, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	wf, err := DecodeWorkflow(f)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to decode workflow '%s': %s", path, err.Error())
+	}
+
+	errMsgs := wf.Validate()
+	if len(errMsgs) != 0 {
+		return nil, fmt.Errorf("Invalid workflow '%s': %s", path,
+			strings.Join(errMsgs, " "))
+	}
+
+	return &wf, nil
+}
+
+// DecodeWorkflow decodes a Workflow from data obtained via a Reader.
+func DecodeWorkflow(r io.Reader) (Workflow, error) {
+	var wf Workflow
+	d := json.NewDecoder(r)
+	err := d.Decode(&wf)
+	return wf, err
+}
+
+// Validate checks the Workflow has some states and that every transition is
+// between known states returning a non-empty slice of human readable error
+// messages detailing the violations found or an empty slice if all is well.
+func (wf *Workflow) Validate() []string {
+	r := strlist.StrList{}
+
+	if len(wf.States) == 0 {
+		r.Add("Workflows must have at least one state.")
+	}
+
+	for from, tos := range wf.Transitions {
+		if !wf.Has(from) {
+			r.Add(fmt.Sprintf("Transitions must be from a known state, not '%s'.", from))
+		}
+
+		for _, to := range tos {
+			if !wf.Has(to) {
+				r.Add(fmt.Sprintf("Transitions must be to a known state, not '%s'.", to))
+			}
+		}
+	}
+
+	for _, s := range wf.Done {
+		if !wf.Has(s) {
+			r.Add(fmt.Sprintf("Done states must be known states, not '%s'.", s))
+		}
+	}
+
+	return r.Slice()
+}
+
+// Initial returns the state Ventures wait in before work on them starts.
+func (wf *Workflow) Initial() string {
+	if len(wf.States) == 0 {
+		return ""
+	}
+	return wf.States[0]
+}
+
+// IsDone returns true if 'state' is one of the states in which work has
+// finished.
+func (wf *Workflow) IsDone(state string) bool {
+	for _, s := range wf.Done {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
+
+// Has returns true if 'state' is exactly one of the Workflows states.
+func (wf *Workflow) Has(state string) bool {
+	for _, s := range wf.States {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
+
+// Index returns the position of 'state' within the Workflows states or -1 if
+// it is not one of them. States are ordered as they are listed.
+func (wf *Workflow) Index(state string) int {
+	for i, s := range wf.States {
+		if s == state {
+			return i
+		}
+	}
+	return -1
+}
+
+// Canonical returns the Workflow state matching 'state' ignoring case and
+// treating hyphens, underscores, and runs of whitespace as single spaces. If
+// no state matches then 'state' is returned unchanged.
+func (wf *Workflow) Canonical(state string) string {
+	key := normalise(state)
+	for _, s := range wf.States {
+		if normalise(s) == key {
+			return s
+		}
+	}
+	return state
+}
+
+// Allows returns true if a Venture may move from the state 'from' to the state
+// 'to'. Both are compared in their canonical form, see Canonical(), so
+// Ventures stored before the workflow was adopted are still recognised.
+// Remaining in the same state is always allowed while a Venture in a state
+// outside the workflow may move to any workflow state.
+func (wf *Workflow) Allows(from string, to string) bool {
+	from, to = wf.Canonical(from), wf.Canonical(to)
+	if from == to {
+		return true
+	}
+
+	if !wf.Has(from) {
+		return wf.Has(to)
+	}
+
+	for _, s := range wf.Transitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// normalise is a file private function that reduces a state to a form that
+// allows states to be compared regardless of case and separators.
+func normalise(state string) string {
+	state = strings.ToLower(state)
+	state = strings.NewReplacer("-", " ", "_", " ").Replace(state)
+	return strings.Join(strings.Fields(state), " ")
+}
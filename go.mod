@@ -4,6 +4,7 @@ go 1.12
 
 require (
 	github.com/PaulioRandall/go-cookies v0.0.0-20190519215902-1b74a73485f3
+	github.com/lib/pq v1.1.1
 	github.com/mattn/go-sqlite3 v1.10.0
 	github.com/mitchellh/mapstructure v1.1.2
 	github.com/stretchr/testify v1.3.0
@@ -14,6 +14,9 @@ import (
 
 	comfiler "github.com/PaulioRandall/go-cookies/comfiler"
 	cookies "github.com/PaulioRandall/go-cookies/cookies"
+	config "github.com/PaulioRandall/go-qlueless-api/api/config"
+	database "github.com/PaulioRandall/go-qlueless-api/api/database"
+	migrate "github.com/PaulioRandall/go-qlueless-api/api/migrate"
 )
 
 // main is the entry point for this script. It wraps the standard Go format,
@@ -52,6 +55,9 @@ func main() {
 		goTestApi(root)
 		goInstall(root)
 
+	case "migrate":
+		goMigrate(root)
+
 	default:
 		badSyntax()
 	}
@@ -118,10 +124,12 @@ func makeBinDir(root string) {
 }
 
 // getArgument returns the argument passed that represents the operation to
-// perform.
+// perform. Only the 'migrate' operation accepts further arguments.
 func getArgument() string {
 	args := os.Args[1:]
-	if len(args) != 1 {
+	switch {
+	case len(args) > 1 && args[0] == "migrate":
+	case len(args) != 1:
 		badSyntax()
 	}
 	return args[0]
@@ -133,7 +141,11 @@ func badSyntax() {
 	syntax := `syntax options:
 1) ./godo.go build  		Builds and tests
 2) ./godo.go run    		Builds, tests, and runs
-3) ./godo.go install		Builds, tests, and installs`
+3) ./godo.go install		Builds, tests, and installs
+4) ./godo.go migrate up|down|status [flags]
+   				Migrates the database up, rolls back the latest
+   				migration, or prints the status of each migration;
+   				flags are those of the application`
 
 	fmt.Println(syntax + "\n")
 	os.Exit(1)
@@ -212,7 +224,7 @@ func goTest(root string) {
 func goTestApi(root string) {
 	fmt.Println("...web API testing, this may take a few moments...")
 	tests := filepath.Join(root, "test", "...")
-	goExe(root, "test", "-count=1", "-p=1", "-failfast", tests)
+	goExe(root, "test", "-count=1", "-failfast", tests)
 }
 
 // goInstall installs the compiled application.
@@ -237,6 +249,86 @@ func goRun(root string) {
 	runCmd(cmd)
 }
 
+// goMigrate applies all pending migrations, rolls back the latest migration,
+// or prints the status of each migration for the database used by the
+// application. The database is configured exactly as the application would be
+// when run from the 'root/bin' directory; any arguments after the migrate
+// operation are passed as command line flags.
+func goMigrate(root string) {
+	if len(os.Args) < 3 {
+		badSyntax()
+	}
+
+	fmt.Println("...migrating database...")
+
+	err := os.Chdir(filepath.Join(root, "bin"))
+	if err != nil {
+		panic(err)
+	}
+
+	cfg, err := config.Load(os.Args[3:])
+	if err != nil {
+		panic(err)
+	}
+
+	db, err := database.Open(cfg.DatabaseDSN)
+	if err != nil {
+		panic(err)
+	}
+	defer db.Close()
+
+	d := database.DialectOf(cfg.DatabaseDSN)
+
+	switch os.Args[2] {
+	case "up":
+		done, err := migrate.Up(db, d)
+		for _, m := range done {
+			printOk(fmt.Sprintf("%d_%s", m.Version, m.Name), "applied")
+		}
+		if err != nil {
+			panic(err)
+		}
+		if len(done) == 0 {
+			printOk(cfg.DatabaseDSN, "up to date")
+		}
+
+	case "down":
+		m, err := migrate.Down(db, d)
+		if err != nil {
+			panic(err)
+		}
+		if m == nil {
+			printOk(cfg.DatabaseDSN, "nothing to roll back")
+			return
+		}
+		printOk(fmt.Sprintf("%d_%s", m.Version, m.Name), "rolled back")
+
+	case "status":
+		recs, err := migrate.Status(db)
+		if err != nil {
+			panic(err)
+		}
+		for _, r := range recs {
+			printMigration(r)
+		}
+
+	default:
+		badSyntax()
+	}
+}
+
+// printMigration prints the status of a migration in the style of 'go test'.
+func printMigration(r migrate.Record) {
+	name := fmt.Sprintf("%d_%s", r.Version, r.Name)
+	if r.Pending() {
+		fmt.Printf("pending\t%s\n", name)
+		return
+	}
+
+	applied := time.Unix(0, r.Applied*int64(time.Millisecond)).UTC()
+	printOk(name, "applied "+applied.Format(time.RFC3339))
+}
+
 // runCmd runs a command and panics on error.
 func runCmd(cmd *exec.Cmd) {
 	cmd.Stdout = os.Stdout
@@ -0,0 +1,43 @@
+package patch
+
+// Merge applies the JSON Merge Patch 'patch' (RFC 7396) to the JSON document
+// 'doc' returning the patched document. Members of the patch set to null are
+// removed from the document while objects are merged recursively; any other
+// value replaces the one within the document.
+func Merge(doc []byte, patch []byte) ([]byte, error) {
+	d, err := decode(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := decode(patch)
+	if err != nil {
+		return nil, malformed("The merge patch is not valid JSON: %s", err.Error())
+	}
+
+	return encode(mergeValue(d, p))
+}
+
+// mergeValue is a file private function that merges 'patch' into 'target'
+// returning the result.
+func mergeValue(target interface{}, patch interface{}) interface{} {
+	p, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	t, ok := target.(map[string]interface{})
+	if !ok {
+		t = map[string]interface{}{}
+	}
+
+	for k, v := range p {
+		if v == nil {
+			delete(t, k)
+			continue
+		}
+		t[k] = mergeValue(t[k], v)
+	}
+
+	return t
+}
@@ -0,0 +1,76 @@
+package patch
+
+import (
+	"testing"
+
+	assert "github.com/stretchr/testify/assert"
+	require "github.com/stretchr/testify/require"
+)
+
+// ****************************************************************************
+// Merge()
+// ****************************************************************************
+
+func TestMerge(t *testing.T) {
+	doc := `{
+		"name": "Rincewind",
+		"hat": {"text": "Wizzard", "colour": "red"},
+		"tags": ["pointy", "stars"]
+	}`
+
+	for _, c := range []struct {
+		name  string
+		patch string
+		exp   result
+	}{
+		{"empty patch",
+			`{}`,
+			result{doc: doc}},
+		{"replace member",
+			`{"name": "Twoflower"}`,
+			result{doc: `{"name": "Twoflower", "hat": {"text": "Wizzard", "colour": "red"}, "tags": ["pointy", "stars"]}`}},
+		{"add member",
+			`{"staff": "Oak"}`,
+			result{doc: `{"name": "Rincewind", "hat": {"text": "Wizzard", "colour": "red"}, "tags": ["pointy", "stars"], "staff": "Oak"}`}},
+		{"null removes member",
+			`{"name": null}`,
+			result{doc: `{"hat": {"text": "Wizzard", "colour": "red"}, "tags": ["pointy", "stars"]}`}},
+		{"null removes missing member",
+			`{"staff": null}`,
+			result{doc: doc}},
+		{"nested object merged",
+			`{"hat": {"colour": "blue", "brim": "wide"}}`,
+			result{doc: `{"name": "Rincewind", "hat": {"text": "Wizzard", "colour": "blue", "brim": "wide"}, "tags": ["pointy", "stars"]}`}},
+		{"null removes nested member",
+			`{"hat": {"colour": null}}`,
+			result{doc: `{"name": "Rincewind", "hat": {"text": "Wizzard"}, "tags": ["pointy", "stars"]}`}},
+		{"nested object replaces scalar",
+			`{"name": {"first": "Rincewind", "title": null}}`,
+			result{doc: `{"name": {"first": "Rincewind"}, "hat": {"text": "Wizzard", "colour": "red"}, "tags": ["pointy", "stars"]}`}},
+		{"array replaced not merged",
+			`{"tags": ["moons"]}`,
+			result{doc: `{"name": "Rincewind", "hat": {"text": "Wizzard", "colour": "red"}, "tags": ["moons"]}`}},
+		{"scalar replaces nested object",
+			`{"hat": "none"}`,
+			result{doc: `{"name": "Rincewind", "hat": "none", "tags": ["pointy", "stars"]}`}},
+		{"non-object replaces document",
+			`["Luggage"]`,
+			result{doc: `["Luggage"]`}},
+		{"null replaces document",
+			`null`,
+			result{doc: `null`}},
+		{"invalid JSON",
+			`{"name": `,
+			result{err: true, malformed: true}},
+	} {
+		out, err := Merge([]byte(doc), []byte(c.patch))
+		assertResult(t, c.name, c.exp, out, err)
+	}
+}
+
+func TestMerge_InvalidDocument(t *testing.T) {
+	_, err := Merge([]byte(`{"name": `), []byte(`{}`))
+	require.NotNil(t, err)
+	_, isPatchErr := err.(*Error)
+	assert.False(t, isPatchErr)
+}
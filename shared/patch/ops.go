@@ -0,0 +1,323 @@
+package patch
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// op represents a single operation of a JSON Patch.
+type op struct {
+	Op    string
+	Path  []string
+	From  []string
+	Value interface{}
+}
+
+// Apply applies the JSON Patch 'patch' (RFC 6902) to the JSON document 'doc'
+// returning the patched document. Operations are applied in order and, if any
+// fail, an *Error is returned and the document is left as it was.
+func Apply(doc []byte, patch []byte) ([]byte, error) {
+	d, err := decode(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	ops, err := decodeOps(patch)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, o := range ops {
+		d, err = applyOp(d, o)
+		if err != nil {
+			return nil, conflict("Operation %d, '%s', failed: %s", i, o.Op, err.Error())
+		}
+	}
+
+	return encode(d)
+}
+
+// decodeOps is a file private function that decodes then validates the
+// operations of the JSON Patch 'patch'.
+func decodeOps(patch []byte) ([]op, error) {
+	raw := []map[string]json.RawMessage{}
+	err := json.Unmarshal(patch, &raw)
+	if err != nil {
+		return nil, malformed("The JSON Patch must be an array of operations: %s", err.Error())
+	}
+
+	ops := make([]op, len(raw))
+	for i, r := range raw {
+		ops[i], err = decodeOp(r)
+		if err != nil {
+			return nil, malformed("Operation %d is invalid: %s", i, err.Error())
+		}
+	}
+
+	return ops, nil
+}
+
+// decodeOp is a file private function that decodes then validates a single
+// JSON Patch operation.
+func decodeOp(r map[string]json.RawMessage) (op, error) {
+	o := op{}
+
+	str := func(name string) (string, error) {
+		var s string
+		v, ok := r[name]
+		if !ok {
+			return "", malformed("'%s' is missing", name)
+		}
+		if json.Unmarshal(v, &s) != nil {
+			return "", malformed("'%s' must be a string", name)
+		}
+		return s, nil
+	}
+
+	var err error
+	if o.Op, err = str("op"); err != nil {
+		return o, err
+	}
+
+	path, err := str("path")
+	if err != nil {
+		return o, err
+	}
+
+	if o.Path, err = parsePointer(path); err != nil {
+		return o, err
+	}
+
+	switch o.Op {
+	case "add", "replace", "test":
+		v, ok := r["value"]
+		if !ok {
+			return o, malformed("'value' is missing")
+		}
+		o.Value, err = decode(v)
+
+	case "move", "copy":
+		var from string
+		if from, err = str("from"); err != nil {
+			return o, err
+		}
+		o.From, err = parsePointer(from)
+
+	case "remove":
+	default:
+		return o, malformed("'%s' is not an operation, use one of 'add',"+
+			" 'remove', 'replace', 'move', 'copy', or 'test'", o.Op)
+	}
+
+	return o, err
+}
+
+// applyOp is a file private function that applies the operation 'o' to the
+// document 'doc' returning the result.
+func applyOp(doc interface{}, o op) (interface{}, error) {
+	switch o.Op {
+	case "add":
+		return add(doc, o.Path, o.Value)
+
+	case "remove":
+		d, _, err := remove(doc, o.Path)
+		return d, err
+
+	case "replace":
+		d, _, err := remove(doc, o.Path)
+		if err != nil {
+			return nil, err
+		}
+		return add(d, o.Path, o.Value)
+
+	case "move":
+		if isProperPrefix(o.From, o.Path) {
+			return nil, conflict("A value can't be moved into one of its children")
+		}
+
+		d, v, err := remove(doc, o.From)
+		if err != nil {
+			return nil, err
+		}
+		return add(d, o.Path, v)
+
+	case "copy":
+		v, err := get(doc, o.From)
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, o.Path, clone(v))
+
+	case "test":
+		v, err := get(doc, o.Path)
+		if err != nil {
+			return nil, err
+		}
+		if !equal(v, o.Value) {
+			return nil, conflict("The value at '/%s' does not match", strings.Join(o.Path, "/"))
+		}
+		return doc, nil
+	}
+
+	return nil, malformed("'%s' is not an operation", o.Op)
+}
+
+// get is a file private function that returns the value within 'doc' at the
+// location referenced by 'path'.
+func get(doc interface{}, path []string) (interface{}, error) {
+	for _, t := range path {
+		switch c := doc.(type) {
+		case map[string]interface{}:
+			v, ok := c[t]
+			if !ok {
+				return nil, conflict("Member '%s' does not exist", t)
+			}
+			doc = v
+
+		case []interface{}:
+			i, err := index(t, len(c)-1)
+			if err != nil {
+				return nil, err
+			}
+			doc = c[i]
+
+		default:
+			return nil, conflict("'%s' can't be referenced within a value that"+
+				" is neither an object nor array", t)
+		}
+	}
+
+	return doc, nil
+}
+
+// add is a file private function that adds 'val' to 'doc' at the location
+// referenced by 'path' returning the result. Object members are replaced if
+// they exist while array elements are inserted before the index referenced
+// or appended if it is '-'.
+func add(doc interface{}, path []string, val interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return val, nil
+	}
+
+	parent, err := get(doc, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	t := path[len(path)-1]
+	switch c := parent.(type) {
+	case map[string]interface{}:
+		c[t] = val
+		return doc, nil
+
+	case []interface{}:
+		i := len(c)
+		if t != "-" {
+			if i, err = index(t, len(c)); err != nil {
+				return nil, err
+			}
+		}
+
+		s := make([]interface{}, 0, len(c)+1)
+		s = append(s, c[:i]...)
+		s = append(s, val)
+		s = append(s, c[i:]...)
+		return set(doc, path[:len(path)-1], s)
+	}
+
+	return nil, conflict("'%s' can't be added to a value that is neither an"+
+		" object nor array", t)
+}
+
+// remove is a file private function that removes the value within 'doc' at
+// the location referenced by 'path' returning the result and the value
+// removed.
+func remove(doc interface{}, path []string) (interface{}, interface{}, error) {
+	if len(path) == 0 {
+		return nil, doc, nil
+	}
+
+	parent, err := get(doc, path[:len(path)-1])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	v, err := get(parent, path[len(path)-1:])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	t := path[len(path)-1]
+	switch c := parent.(type) {
+	case map[string]interface{}:
+		delete(c, t)
+		return doc, v, nil
+
+	case []interface{}:
+		i, _ := index(t, len(c)-1)
+		s := make([]interface{}, 0, len(c)-1)
+		s = append(s, c[:i]...)
+		s = append(s, c[i+1:]...)
+		doc, err = set(doc, path[:len(path)-1], s)
+		return doc, v, err
+	}
+
+	return nil, nil, conflict("'%s' can't be removed from a value that is"+
+		" neither an object nor array", t)
+}
+
+// set is a file private function that replaces the value within 'doc' at the
+// existing location referenced by 'path' with 'val' returning the result. It
+// is used to replace arrays since, unlike objects, they can't grow in place.
+func set(doc interface{}, path []string, val interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return val, nil
+	}
+
+	parent, err := get(doc, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	t := path[len(path)-1]
+	switch c := parent.(type) {
+	case map[string]interface{}:
+		c[t] = val
+	case []interface{}:
+		i, _ := index(t, len(c)-1)
+		c[i] = val
+	}
+
+	return doc, nil
+}
+
+// index is a file private function that parses the array index 't' returning
+// an error if it is not an integer between zero and 'max' inclusive.
+func index(t string, max int) (int, error) {
+	i, err := strconv.Atoi(t)
+	if err != nil || i < 0 || (len(t) > 1 && t[0] == '0') {
+		return 0, conflict("'%s' is not an array index", t)
+	}
+
+	if i > max {
+		return 0, conflict("Array index '%d' is out of bounds", i)
+	}
+
+	return i, nil
+}
+
+// isProperPrefix is a file private function that returns true if the path
+// 'prefix' references a parent of the location referenced by 'path'.
+func isProperPrefix(prefix []string, path []string) bool {
+	if len(prefix) >= len(path) {
+		return false
+	}
+
+	for i, t := range prefix {
+		if path[i] != t {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,220 @@
+package patch
+
+import (
+	"testing"
+
+	assert "github.com/stretchr/testify/assert"
+	require "github.com/stretchr/testify/require"
+)
+
+// result represents the expected outcome of applying a patch; if 'err' is
+// set 'doc' is ignored.
+type result struct {
+	doc       string
+	err       bool
+	malformed bool
+}
+
+// assertResult asserts that the output of applying a patch, 'out' and 'err',
+// matches the expected result 'exp'. 'name' identifies the case under test.
+func assertResult(t *testing.T, name string, exp result, out []byte, err error) {
+	if !exp.err {
+		require.Nil(t, err, "Case: %s", name)
+		assert.JSONEq(t, exp.doc, string(out), "Case: %s", name)
+		return
+	}
+
+	require.NotNil(t, err, "Case: %s", name)
+	e, ok := err.(*Error)
+	require.True(t, ok, "Case: %s", name)
+	assert.Equal(t, exp.malformed, e.Malformed, "Case: %s", name)
+	assert.NotEmpty(t, e.Error(), "Case: %s", name)
+	assert.Nil(t, out, "Case: %s", name)
+}
+
+// ****************************************************************************
+// Apply()
+// ****************************************************************************
+
+func TestApply(t *testing.T) {
+	doc := `{
+		"name": "Rincewind",
+		"hat": {"text": "Wizzard", "tags": ["pointy", "stars"]},
+		"a/b": 1,
+		"m~n": 2
+	}`
+
+	for _, c := range []struct {
+		name  string
+		patch string
+		exp   result
+	}{
+		// add
+		{"add member",
+			`[{"op": "add", "path": "/staff", "value": "Oak"}]`,
+			result{doc: `{"name": "Rincewind", "hat": {"text": "Wizzard", "tags": ["pointy", "stars"]}, "a/b": 1, "m~n": 2, "staff": "Oak"}`}},
+		{"add replaces existing member",
+			`[{"op": "add", "path": "/name", "value": "Twoflower"}]`,
+			result{doc: `{"name": "Twoflower", "hat": {"text": "Wizzard", "tags": ["pointy", "stars"]}, "a/b": 1, "m~n": 2}`}},
+		{"add inserts before index",
+			`[{"op": "add", "path": "/hat/tags/0", "value": "red"}]`,
+			result{doc: `{"name": "Rincewind", "hat": {"text": "Wizzard", "tags": ["red", "pointy", "stars"]}, "a/b": 1, "m~n": 2}`}},
+		{"add at index equal to length appends",
+			`[{"op": "add", "path": "/hat/tags/2", "value": "red"}]`,
+			result{doc: `{"name": "Rincewind", "hat": {"text": "Wizzard", "tags": ["pointy", "stars", "red"]}, "a/b": 1, "m~n": 2}`}},
+		{"add with '-' appends",
+			`[{"op": "add", "path": "/hat/tags/-", "value": "red"}]`,
+			result{doc: `{"name": "Rincewind", "hat": {"text": "Wizzard", "tags": ["pointy", "stars", "red"]}, "a/b": 1, "m~n": 2}`}},
+		{"add at index beyond length",
+			`[{"op": "add", "path": "/hat/tags/3", "value": "red"}]`,
+			result{err: true}},
+		{"add with missing parent",
+			`[{"op": "add", "path": "/cloak/colour", "value": "red"}]`,
+			result{err: true}},
+		{"add replaces root",
+			`[{"op": "add", "path": "", "value": {"name": "Luggage"}}]`,
+			result{doc: `{"name": "Luggage"}`}},
+		{"add with escaped tokens",
+			`[{"op": "add", "path": "/a~1b", "value": 3}, {"op": "add", "path": "/m~0n", "value": 4}]`,
+			result{doc: `{"name": "Rincewind", "hat": {"text": "Wizzard", "tags": ["pointy", "stars"]}, "a/b": 3, "m~n": 4}`}},
+
+		// remove
+		{"remove member",
+			`[{"op": "remove", "path": "/hat/text"}]`,
+			result{doc: `{"name": "Rincewind", "hat": {"tags": ["pointy", "stars"]}, "a/b": 1, "m~n": 2}`}},
+		{"remove element",
+			`[{"op": "remove", "path": "/hat/tags/0"}]`,
+			result{doc: `{"name": "Rincewind", "hat": {"text": "Wizzard", "tags": ["stars"]}, "a/b": 1, "m~n": 2}`}},
+		{"remove missing member",
+			`[{"op": "remove", "path": "/staff"}]`,
+			result{err: true}},
+		{"remove with '-'",
+			`[{"op": "remove", "path": "/hat/tags/-"}]`,
+			result{err: true}},
+		{"remove at index equal to length",
+			`[{"op": "remove", "path": "/hat/tags/2"}]`,
+			result{err: true}},
+		{"remove root",
+			`[{"op": "remove", "path": ""}]`,
+			result{doc: `null`}},
+
+		// replace
+		{"replace member",
+			`[{"op": "replace", "path": "/hat/text", "value": "Wizard"}]`,
+			result{doc: `{"name": "Rincewind", "hat": {"text": "Wizard", "tags": ["pointy", "stars"]}, "a/b": 1, "m~n": 2}`}},
+		{"replace element",
+			`[{"op": "replace", "path": "/hat/tags/1", "value": "moons"}]`,
+			result{doc: `{"name": "Rincewind", "hat": {"text": "Wizzard", "tags": ["pointy", "moons"]}, "a/b": 1, "m~n": 2}`}},
+		{"replace missing member",
+			`[{"op": "replace", "path": "/staff", "value": "Oak"}]`,
+			result{err: true}},
+		{"replace root",
+			`[{"op": "replace", "path": "", "value": [1, 2]}]`,
+			result{doc: `[1, 2]`}},
+
+		// move
+		{"move member",
+			`[{"op": "move", "from": "/hat/text", "path": "/text"}]`,
+			result{doc: `{"name": "Rincewind", "hat": {"tags": ["pointy", "stars"]}, "text": "Wizzard", "a/b": 1, "m~n": 2}`}},
+		{"move element",
+			`[{"op": "move", "from": "/hat/tags/0", "path": "/hat/tags/-"}]`,
+			result{doc: `{"name": "Rincewind", "hat": {"text": "Wizzard", "tags": ["stars", "pointy"]}, "a/b": 1, "m~n": 2}`}},
+		{"move to itself",
+			`[{"op": "move", "from": "/hat", "path": "/hat"}]`,
+			result{doc: doc}},
+		{"move into own child",
+			`[{"op": "move", "from": "/hat", "path": "/hat/text"}]`,
+			result{err: true}},
+		{"move from missing member",
+			`[{"op": "move", "from": "/staff", "path": "/name"}]`,
+			result{err: true}},
+
+		// copy
+		{"copy member",
+			`[{"op": "copy", "from": "/hat/tags", "path": "/tags"}]`,
+			result{doc: `{"name": "Rincewind", "hat": {"text": "Wizzard", "tags": ["pointy", "stars"]}, "tags": ["pointy", "stars"], "a/b": 1, "m~n": 2}`}},
+		{"copy is independent of original",
+			`[{"op": "copy", "from": "/hat", "path": "/spare"}, {"op": "remove", "path": "/spare/tags/0"}]`,
+			result{doc: `{"name": "Rincewind", "hat": {"text": "Wizzard", "tags": ["pointy", "stars"]}, "spare": {"text": "Wizzard", "tags": ["stars"]}, "a/b": 1, "m~n": 2}`}},
+		{"copy from missing member",
+			`[{"op": "copy", "from": "/staff", "path": "/name"}]`,
+			result{err: true}},
+
+		// test
+		{"test passes",
+			`[{"op": "test", "path": "/name", "value": "Rincewind"}]`,
+			result{doc: doc}},
+		{"test nested value ignoring member order",
+			`[{"op": "test", "path": "/hat", "value": {"tags": ["pointy", "stars"], "text": "Wizzard"}}]`,
+			result{doc: doc}},
+		{"test number ignoring how it is written",
+			`[{"op": "test", "path": "/a~1b", "value": 1.0}]`,
+			result{doc: doc}},
+		{"test nested value with different element order",
+			`[{"op": "test", "path": "/hat/tags", "value": ["stars", "pointy"]}]`,
+			result{err: true}},
+		{"test fails",
+			`[{"op": "test", "path": "/name", "value": "Twoflower"}]`,
+			result{err: true}},
+		{"test fails after earlier operations applied",
+			`[{"op": "replace", "path": "/name", "value": "Twoflower"}, {"op": "test", "path": "/name", "value": "Rincewind"}]`,
+			result{err: true}},
+		{"test missing member",
+			`[{"op": "test", "path": "/staff", "value": "Oak"}]`,
+			result{err: true}},
+
+		// array indices
+		{"index with leading zero",
+			`[{"op": "replace", "path": "/hat/tags/01", "value": "moons"}]`,
+			result{err: true}},
+		{"negative index",
+			`[{"op": "replace", "path": "/hat/tags/-1", "value": "moons"}]`,
+			result{err: true}},
+		{"non-numeric index",
+			`[{"op": "test", "path": "/hat/tags/one", "value": "stars"}]`,
+			result{err: true}},
+		{"reference within a scalar",
+			`[{"op": "test", "path": "/name/0", "value": "R"}]`,
+			result{err: true}},
+
+		// malformed
+		{"not an array",
+			`{"op": "remove", "path": "/name"}`,
+			result{err: true, malformed: true}},
+		{"unknown operation",
+			`[{"op": "delete", "path": "/name"}]`,
+			result{err: true, malformed: true}},
+		{"missing op",
+			`[{"path": "/name"}]`,
+			result{err: true, malformed: true}},
+		{"missing path",
+			`[{"op": "remove"}]`,
+			result{err: true, malformed: true}},
+		{"missing value",
+			`[{"op": "add", "path": "/name"}]`,
+			result{err: true, malformed: true}},
+		{"missing from",
+			`[{"op": "copy", "path": "/name"}]`,
+			result{err: true, malformed: true}},
+		{"path not a JSON Pointer",
+			`[{"op": "remove", "path": "name"}]`,
+			result{err: true, malformed: true}},
+		{"path not a string",
+			`[{"op": "remove", "path": 1}]`,
+			result{err: true, malformed: true}},
+	} {
+		out, err := Apply([]byte(doc), []byte(c.patch))
+		assertResult(t, c.name, c.exp, out, err)
+	}
+}
+
+func TestApply_NoOperations(t *testing.T) {
+	out, err := Apply([]byte(`{"name": "Rincewind"}`), []byte(`[]`))
+	require.Nil(t, err)
+	assert.JSONEq(t, `{"name": "Rincewind"}`, string(out))
+}
+
+func TestApply_InvalidDocument(t *testing.T) {
+	_, err := Apply([]byte(`{"name": `), []byte(`[]`))
+	require.NotNil(t, err)
+}
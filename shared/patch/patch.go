@@ -0,0 +1,102 @@
+// Package patch provides functions for applying JSON Merge Patches (RFC 7396)
+// and JSON Patches (RFC 6902) to JSON documents.
+package patch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Error is returned when a patch can not be applied to a document.
+type Error struct {
+
+	// Malformed is true if the patch itself is invalid, e.g. an unknown
+	// operation or a path that isn't a JSON Pointer, rather than not
+	// applicable to the document, e.g. a path that doesn't exist or a failed
+	// 'test' operation.
+	Malformed bool
+
+	msg string
+}
+
+// Error implements error.
+func (e *Error) Error() string {
+	return e.msg
+}
+
+// malformed is a file private function that returns an Error for an invalid
+// patch.
+func malformed(format string, args ...interface{}) *Error {
+	return &Error{
+		Malformed: true,
+		msg:       fmt.Sprintf(format, args...),
+	}
+}
+
+// conflict is a file private function that returns an Error for a patch that
+// can't be applied to the document.
+func conflict(format string, args ...interface{}) *Error {
+	return &Error{
+		msg: fmt.Sprintf(format, args...),
+	}
+}
+
+// decode is a file private function that decodes the JSON 'b' keeping numbers
+// as they were written so they're encoded again without loss.
+func decode(b []byte) (interface{}, error) {
+	var v interface{}
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	err := d.Decode(&v)
+	return v, err
+}
+
+// encode is a file private function that encodes the JSON value 'v'.
+func encode(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// equal is a file private function that returns true if the JSON values 'a'
+// and 'b' are equal ignoring how their numbers are written and the order of
+// object members.
+func equal(a interface{}, b interface{}) bool {
+	norm := func(v interface{}) interface{} {
+		b, _ := json.Marshal(v)
+		var n interface{}
+		json.Unmarshal(b, &n)
+		return n
+	}
+	return reflect.DeepEqual(norm(a), norm(b))
+}
+
+// clone is a file private function that returns a deep copy of the JSON value
+// 'v'.
+func clone(v interface{}) interface{} {
+	b, _ := json.Marshal(v)
+	c, _ := decode(b)
+	return c
+}
+
+// parsePointer is a file private function that parses the JSON Pointer 'p'
+// (RFC 6901) into its unescaped reference tokens. The empty pointer refers to
+// the whole document and so has no tokens.
+func parsePointer(p string) ([]string, error) {
+	if p == "" {
+		return []string{}, nil
+	}
+
+	if !strings.HasPrefix(p, "/") {
+		return nil, malformed("'%s' is not a JSON Pointer, it must be empty or begin with '/'", p)
+	}
+
+	tokens := strings.Split(p[1:], "/")
+	r := strings.NewReplacer("~1", "/", "~0", "~")
+	for i, t := range tokens {
+		tokens[i] = r.Replace(t)
+	}
+
+	return tokens, nil
+}
@@ -6,12 +6,19 @@ import (
 	assert "github.com/stretchr/testify/assert"
 )
 
-// AssertGenericError checks that an error response body has the required fields
-// populated
-func AssertGenericError(t *testing.T, r WrappedReply) {
-	assert.NotEmpty(t, r.Message)
-	assert.NotEmpty(t, r.Self)
-	assert.Empty(t, r.Data)
+// AssertGenericProblem checks that a problem response body has the required
+// fields populated
+func AssertGenericProblem(t *testing.T, p Problem) {
+	assert.NotEmpty(t, p.Type)
+	assert.NotEmpty(t, p.Title)
+	assert.NotEmpty(t, p.Status)
+	assert.NotEmpty(t, p.Detail)
+	assert.NotEmpty(t, p.Instance)
+	for _, v := range p.Errors {
+		assert.NotEmpty(t, v.Field)
+		assert.NotEmpty(t, v.Code)
+		assert.NotEmpty(t, v.Message)
+	}
 }
 
 // AssertGenericReply checks that an response body has the required fields
@@ -0,0 +1,57 @@
+package wrapped
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// Problem represents an RFC 7807 problem details response returned when a
+// request could not be fulfilled. Current is an extension member holding the
+// latest state of the resources when a request conflicts with them.
+type Problem struct {
+	Type     string      `json:"type"`
+	Title    string      `json:"title"`
+	Status   int         `json:"status"`
+	Detail   string      `json:"detail,omitempty"`
+	Instance string      `json:"instance"`
+	Errors   []Violation `json:"errors,omitempty"`
+	Current  interface{} `json:"current,omitempty"`
+}
+
+// Violation represents a single field of a request that failed validation.
+// Code is a short machine readable name for the rule violated while Message
+// is suitable for presenting to end users.
+type Violation struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Violations represents the list of Violations found while validating a
+// request.
+type Violations []Violation
+
+// Add appends a new Violation of the rule 'code' by the field 'field'.
+func (vs *Violations) Add(field string, code string, msg string) {
+	*vs = append(*vs, Violation{
+		Field:   field,
+		Code:    code,
+		Message: msg,
+	})
+}
+
+// Messages returns the human readable message of each Violation.
+func (vs Violations) Messages() []string {
+	r := make([]string, len(vs))
+	for i, v := range vs {
+		r[i] = v.Message
+	}
+	return r
+}
+
+// DecodeProblem decodes JSON from a Reader into a Problem
+func DecodeProblem(r io.Reader) (Problem, error) {
+	var p Problem
+	err := json.NewDecoder(r).Decode(&p)
+	return p, err
+}
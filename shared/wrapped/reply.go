@@ -7,13 +7,26 @@ import (
 
 // A WrappedReply represents the response that should be returned when the
 // client has requested data be wrapped and meta information included or when
-// error information is being returned
+// error information is being returned. Next, Prev, and Total are only set when
+// the data is a single page of a larger set.
 type WrappedReply struct {
 	Message string      `json:"message"`
 	Self    string      `json:"self"`
+	Next    string      `json:"next,omitempty"`
+	Prev    string      `json:"prev,omitempty"`
+	Total   *int        `json:"total,omitempty"`
 	Data    interface{} `json:"data,omitempty"`
 }
 
+// Page represents the position of a single page of data within a larger set.
+// Next and Prev are the URLs of the adjacent pages, empty if there are none,
+// while Total is the size of the whole set or nil if it is unknown.
+type Page struct {
+	Next  string
+	Prev  string
+	Total *int
+}
+
 // DecodeFromReader decodes JSON from a Reader into a WrappedReply
 func DecodeFromReader(r io.Reader) (WrappedReply, error) {
 	var wr WrappedReply
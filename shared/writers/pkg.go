@@ -2,8 +2,10 @@ package writers
 
 import (
 	"encoding/json"
+	"fmt"
 	"log"
 	"net/http"
+	"strings"
 
 	uhttp "github.com/PaulioRandall/go-cookies/uhttp"
 	wrapped "github.com/PaulioRandall/go-qlueless-api/shared/wrapped"
@@ -24,7 +26,7 @@ func CheckNotEmpty(res *http.ResponseWriter, req *http.Request, name string, m s
 // the client has specified, the data is wrapped and meta information added else
 // the input data is returned.
 func PrepResponseData(req *http.Request, data interface{}, msg string) interface{} {
-	if req.URL.Query()["wrap"] != nil {
+	if IsWrapped(req) {
 		return wrapped.WrappedReply{
 			Message: msg,
 			Self:    uhttp.RelURL(req),
@@ -35,16 +37,72 @@ func PrepResponseData(req *http.Request, data interface{}, msg string) interface
 	}
 }
 
-// WriteServerError writes the response for a generic 500 error to the client.
-func WriteServerError(res *http.ResponseWriter, req *http.Request) {
-	r := wrapped.WrappedReply{
-		Message: "Bummer! Something went wrong on the server.",
-		Self:    (*req).URL.String(),
+// WriteProblem writes an RFC 7807 'application/problem+json' response to the
+// client. If not specified, the type defaults to 'about:blank', the title to
+// the standard text of the status, and the instance to the request URL.
+func WriteProblem(res *http.ResponseWriter, req *http.Request, p wrapped.Problem) {
+	if p.Type == "" {
+		p.Type = "about:blank"
+	}
+
+	if p.Title == "" {
+		p.Title = http.StatusText(p.Status)
+	}
+
+	if p.Instance == "" {
+		p.Instance = uhttp.RelURL(req)
+	}
+
+	(*res).Header().Set("Content-Type", "application/problem+json; charset=utf-8")
+	(*res).WriteHeader(p.Status)
+	json.NewEncoder(*res).Encode(p)
+}
+
+// IsWrapped returns true if the client has requested the response data be
+// wrapped and meta information added.
+func IsWrapped(req *http.Request) bool {
+	return req.URL.Query()["wrap"] != nil
+}
+
+// WritePagedReply writes a success response holding a single page of data.
+// Links to the adjacent pages are written within an RFC 8288 'Link' header
+// and, if the client has specified, the data is wrapped along with the links
+// and total size of the set.
+func WritePagedReply(res *http.ResponseWriter, req *http.Request, code int, data interface{}, msg string, p wrapped.Page) {
+	links := []string{}
+	if p.Next != "" {
+		links = append(links, fmt.Sprintf(`<%s>; rel="next"`, p.Next))
+	}
+	if p.Prev != "" {
+		links = append(links, fmt.Sprintf(`<%s>; rel="prev"`, p.Prev))
+	}
+	if len(links) > 0 {
+		(*res).Header().Set("Link", strings.Join(links, ", "))
+	}
+
+	var reply interface{} = data
+	if IsWrapped(req) {
+		reply = wrapped.WrappedReply{
+			Message: msg,
+			Self:    uhttp.RelURL(req),
+			Next:    p.Next,
+			Prev:    p.Prev,
+			Total:   p.Total,
+			Data:    data,
+		}
 	}
 
 	uhttp.UseUTF8Json(res, "")
-	(*res).WriteHeader(500)
-	json.NewEncoder(*res).Encode(r)
+	(*res).WriteHeader(code)
+	json.NewEncoder(*res).Encode(reply)
+}
+
+// WriteServerError writes the response for a generic 500 error to the client.
+func WriteServerError(res *http.ResponseWriter, req *http.Request) {
+	WriteProblem(res, req, wrapped.Problem{
+		Status: http.StatusInternalServerError,
+		Detail: "Bummer! Something went wrong on the server.",
+	})
 }
 
 // WriteBadRequest writes the response for a 400 error to the client.
@@ -53,14 +111,39 @@ func WriteBadRequest(res *http.ResponseWriter, req *http.Request, m string) {
 		return
 	}
 
-	r := wrapped.WrappedReply{
-		Message: m,
-		Self:    uhttp.RelURL(req),
+	WriteProblem(res, req, wrapped.Problem{
+		Status: http.StatusBadRequest,
+		Detail: m,
+	})
+}
+
+// WriteInvalid writes the response for a 400 error, caused by the request
+// failing validation, to the client. Each Violation is listed within the
+// response and their messages joined to form its detail.
+func WriteInvalid(res *http.ResponseWriter, req *http.Request, vs wrapped.Violations) {
+	if len(vs) == 0 {
+		log.Println("[BUG] Error missing 'violations'")
+		WriteServerError(res, req)
+		return
 	}
 
-	uhttp.UseUTF8Json(res, "")
-	(*res).WriteHeader(http.StatusBadRequest)
-	json.NewEncoder(*res).Encode(r)
+	WriteProblem(res, req, wrapped.Problem{
+		Status: http.StatusBadRequest,
+		Detail: strings.Join(vs.Messages(), " "),
+		Errors: vs,
+	})
+}
+
+// WriteNotFound writes the response for a 404 error to the client.
+func WriteNotFound(res *http.ResponseWriter, req *http.Request, m string) {
+	if !CheckNotEmpty(res, req, "response message", m) {
+		return
+	}
+
+	WriteProblem(res, req, wrapped.Problem{
+		Status: http.StatusNotFound,
+		Detail: m,
+	})
 }
 
 // WriteWrappedReply writes the response for a WrappedReply to the client.
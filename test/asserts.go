@@ -2,7 +2,6 @@ package test
 
 import (
 	"bytes"
-	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -78,14 +77,13 @@ func AssertNotEmptyBody(t *testing.T, body io.Reader) []byte {
 	return b
 }
 
-// AssertErrorBody assert that a response 'body' is a generic response error.
-// Returns the parsed response error.
-func AssertErrorBody(t *testing.T, body io.Reader) wrapped.WrappedReply {
-	var reply wrapped.WrappedReply
-	err := json.NewDecoder(body).Decode(&reply)
+// AssertErrorBody assert that a response 'body' is a generic problem response.
+// Returns the parsed problem.
+func AssertErrorBody(t *testing.T, body io.Reader) wrapped.Problem {
+	p, err := wrapped.DecodeProblem(body)
 	require.Nil(t, err)
-	wrapped.AssertGenericError(t, reply)
-	return reply
+	wrapped.AssertGenericProblem(t, p)
+	return p
 }
 
 // VerifyBadMethods asserts that for a specific 'url', the 'corsMethods' are as
@@ -0,0 +1,95 @@
+package DELETE
+
+import (
+	"testing"
+
+	"github.com/PaulioRandall/go-qlueless-api/api/batches"
+	"github.com/PaulioRandall/go-qlueless-api/test"
+	btest "github.com/PaulioRandall/go-qlueless-api/test/batches"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	test.SetWorkingDir("../../../bin")
+}
+
+// ****************************************************************************
+// (DELETE) /batches?ids={ids}
+// ****************************************************************************
+
+func TestDELETE_Batches_1(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Batches already exist on the server
+		When existing Batches are deleted
+		Ensure the response code is 200
+		And the body is a JSON array containing the deleted Batches
+		And those Batches are marked as dead
+		And those Batches are no longer among the living
+	`)
+
+	btest.SetupTest()
+	defer btest.TearDown()
+
+	req := test.APICall{
+		URL:    test.Host + "/batches?ids=1,2",
+		Method: "DELETE",
+	}
+	res := req.Fire()
+	defer res.Body.Close()
+
+	require.Equal(t, 200, res.StatusCode)
+	test.AssertDefaultHeaders(t, res, "application/json", "GET, POST, PUT, DELETE, OPTIONS")
+
+	body := test.PrintBody(t, res)
+
+	result := batches.RequireSliceOfBatches(t, body)
+	require.Len(t, result, 2)
+	for _, b := range result {
+		assert.True(t, b.Dead, "Batch.Dead")
+	}
+
+	assert.Empty(t, btest.DBQueryMany("1,2"))
+	assert.Len(t, btest.DBQueryAll(), 1)
+}
+
+func TestDELETE_Batches_2(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Batches already exist on the server
+		When a delete is requested without any IDs or with IDs that aren't
+		integers
+		Ensure the response code is 400
+		And the error lists a violation of 'ids'
+		And no Batches have been deleted
+	`)
+
+	btest.SetupTest()
+	defer btest.TearDown()
+
+	before := btest.DBQueryAll()
+
+	for _, c := range []struct {
+		query string
+		code  string
+	}{
+		{"", "required"},
+		{"?ids=abc", "invalid"},
+	} {
+		req := test.APICall{
+			URL:    test.Host + "/batches" + c.query,
+			Method: "DELETE",
+		}
+		res := req.Fire()
+		defer res.Body.Close()
+
+		require.Equal(t, 400, res.StatusCode, "Query: %s", c.query)
+		p := test.AssertErrorBody(t, test.PrintBody(t, res))
+		require.Len(t, p.Errors, 1, "Query: %s", c.query)
+		assert.Equal(t, "ids", p.Errors[0].Field, "Query: %s", c.query)
+		assert.Equal(t, c.code, p.Errors[0].Code, "Query: %s", c.query)
+	}
+
+	assert.Equal(t, before, btest.DBQueryAll())
+}
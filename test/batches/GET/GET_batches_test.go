@@ -0,0 +1,170 @@
+package GET
+
+import (
+	"testing"
+
+	"github.com/PaulioRandall/go-cookies/toastify"
+	"github.com/PaulioRandall/go-qlueless-api/api/batches"
+	"github.com/PaulioRandall/go-qlueless-api/test"
+	btest "github.com/PaulioRandall/go-qlueless-api/test/batches"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	test.SetWorkingDir("../../../bin")
+}
+
+// ****************************************************************************
+// (GET) /batches
+// ****************************************************************************
+
+func TestGET_Batches_1(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Batches already exist on the server
+		When all Batches are requested
+		Ensure the response code is 200
+		And header includes:
+			Content-Type:                   'application/json; charset=utf-8'
+			Access-Control-Allow-Origin:    '*'
+			Access-Control-Allow-Headers:   '*'
+			Access-Control-Allow-Methods:   'GET, POST, PUT, DELETE, OPTIONS'
+		And the body is a JSON array containing all living Batches
+	`)
+
+	btest.SetupTest()
+	defer btest.TearDown()
+
+	req := test.APICall{
+		URL:    test.Host + "/batches",
+		Method: "GET",
+	}
+	res := req.Fire()
+	defer res.Body.Close()
+
+	require.Equal(t, 200, res.StatusCode)
+	toastify.AssertHeaderEqual(t, "Content-Type", res.Header, "application/json; charset=utf-8")
+	toastify.AssertHeaderEqual(t, "Access-Control-Allow-Origin", res.Header, "*")
+	toastify.AssertHeaderEqual(t, "Access-Control-Allow-Headers", res.Header, "*")
+	toastify.AssertHeaderEqual(t, "Access-Control-Allow-Methods", res.Header, "GET, POST, PUT, DELETE, OPTIONS")
+
+	body := test.PrintBody(t, res)
+
+	result := batches.RequireSliceOfBatches(t, body)
+	batches.AssertBatchesEqual(t, btest.DBQueryAll(), result, true)
+}
+
+// ****************************************************************************
+// (GET) /batches?order_id={order_id}
+// ****************************************************************************
+
+func TestGET_Batches_2(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Batches already exist on the server
+		When the Batches belonging to a specific Order are requested
+		Ensure the response code is 200
+		And the body is a JSON array containing only the Batches of that Order
+	`)
+
+	btest.SetupTest()
+	defer btest.TearDown()
+
+	req := test.APICall{
+		URL:    test.Host + "/batches?order_id=1",
+		Method: "GET",
+	}
+	res := req.Fire()
+	defer res.Body.Close()
+
+	require.Equal(t, 200, res.StatusCode)
+
+	body := test.PrintBody(t, res)
+
+	result := batches.RequireSliceOfBatches(t, body)
+	require.Len(t, result, 2)
+	batches.AssertBatchesEqual(t, btest.DBQueryMany("1,2"), result, true)
+}
+
+func TestGET_Batches_3(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Batches already exist on the server
+		When specific Batches belonging to a specific Order are requested
+		Ensure the response code is 200
+		And the body is a JSON array containing only the requested Batches of
+		that Order
+	`)
+
+	btest.SetupTest()
+	defer btest.TearDown()
+
+	req := test.APICall{
+		URL:    test.Host + "/batches?order_id=1&ids=2,3",
+		Method: "GET",
+	}
+	res := req.Fire()
+	defer res.Body.Close()
+
+	require.Equal(t, 200, res.StatusCode)
+
+	body := test.PrintBody(t, res)
+
+	result := batches.RequireSliceOfBatches(t, body)
+	require.Len(t, result, 1)
+	batches.AssertBatchesEqual(t, btest.DBQueryMany("2"), result, true)
+}
+
+func TestGET_Batches_4(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Batches already exist on the server
+		When Batches are requested with an invalid Order ID
+		Ensure the response code is 400
+		And the body is a JSON object representing an error response
+		And the error lists a violation of 'order_id'
+	`)
+
+	btest.SetupTest()
+	defer btest.TearDown()
+
+	req := test.APICall{
+		URL:    test.Host + "/batches?order_id=abc",
+		Method: "GET",
+	}
+	res := req.Fire()
+	defer res.Body.Close()
+
+	require.Equal(t, 400, res.StatusCode)
+	p := test.AssertErrorBody(t, test.PrintBody(t, res))
+	require.Len(t, p.Errors, 1)
+	assert.Equal(t, "order_id", p.Errors[0].Field)
+}
+
+func TestGET_Batches_5(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Batches already exist on the server
+		When Batches are requested with IDs that aren't integers
+		Ensure the response code is 400
+		And the body is a JSON object representing an error response
+		And the error lists a violation of 'ids'
+	`)
+
+	btest.SetupTest()
+	defer btest.TearDown()
+
+	req := test.APICall{
+		URL:    test.Host + "/batches?ids=abc",
+		Method: "GET",
+	}
+	res := req.Fire()
+	defer res.Body.Close()
+
+	require.Equal(t, 400, res.StatusCode)
+	p := test.AssertErrorBody(t, test.PrintBody(t, res))
+	require.Len(t, p.Errors, 1)
+	assert.Equal(t, "ids", p.Errors[0].Field)
+	assert.Equal(t, "invalid", p.Errors[0].Code)
+}
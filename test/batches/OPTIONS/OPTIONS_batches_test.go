@@ -0,0 +1,70 @@
+package OPTIONS
+
+import (
+	"testing"
+
+	"github.com/PaulioRandall/go-qlueless-api/test"
+	btest "github.com/PaulioRandall/go-qlueless-api/test/batches"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	test.SetWorkingDir("../../../bin")
+}
+
+// ****************************************************************************
+// (OPTIONS) /batches
+// ****************************************************************************
+
+func TestOPTIONS_Batches(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Batches already exist on the server
+		When /batches OPTIONS are requested
+		Ensure the response code is 200
+		And header includes:
+			Access-Control-Allow-Origin:    '*'
+			Access-Control-Allow-Headers:   '*'
+			Access-Control-Allow-Methods:   'GET, POST, PUT, DELETE, OPTIONS'
+		And there is NO response body
+	`)
+
+	btest.SetupTest()
+	defer btest.TearDown()
+
+	req := test.APICall{
+		URL:    test.Host + "/batches",
+		Method: "OPTIONS",
+	}
+	res := req.Fire()
+	defer res.Body.Close()
+
+	require.Equal(t, 200, res.StatusCode)
+	test.AssertCorsHeaders(t, res, "GET, POST, PUT, DELETE, OPTIONS")
+	test.AssertEmptyBody(t, res.Body)
+}
+
+// ****************************************************************************
+// (?) /batches
+// ****************************************************************************
+
+func TestINVALID_Batches(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Batches already exist on the server
+		When /batches is called using invalid methods
+		Ensure the response code is 405
+		And there is NO response body
+	`)
+
+	btest.SetupTest()
+	defer btest.TearDown()
+
+	test.VerifyBadMethods(t, test.Host+"/batches", "GET, POST, PUT, DELETE, OPTIONS", []string{
+		"HEAD",
+		"CONNECT",
+		"TRACE",
+		"PATCH",
+		"CUSTOM",
+	})
+}
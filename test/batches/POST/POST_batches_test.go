@@ -0,0 +1,170 @@
+package POST
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/PaulioRandall/go-qlueless-api/api/batches"
+	"github.com/PaulioRandall/go-qlueless-api/api/orders"
+	"github.com/PaulioRandall/go-qlueless-api/test"
+	btest "github.com/PaulioRandall/go-qlueless-api/test/batches"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	test.SetWorkingDir("../../../bin")
+}
+
+// ****************************************************************************
+// (POST) /batches
+// ****************************************************************************
+
+func TestPOST_Batches_1(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Orders and Batches already exist on the server
+		When a new valid Batch is POSTed
+		Ensure the response code is 201
+		And the body is a JSON object representing the living input Batch
+		And that Batch will have a new, unused, ID
+		And that Batch will belong to the Order specified
+	`)
+
+	btest.SetupTest()
+	defer btest.TearDown()
+
+	input := batches.NewBatch{
+		OrderID:     "2",
+		Description: "Stretch the leather",
+		State:       "Not started",
+	}
+	buf := new(bytes.Buffer)
+	json.NewEncoder(buf).Encode(&input)
+
+	req := test.APICall{
+		URL:    test.Host + "/batches",
+		Method: "POST",
+		Body:   buf,
+	}
+	res := req.Fire()
+	defer res.Body.Close()
+
+	require.Equal(t, 201, res.StatusCode)
+	test.AssertDefaultHeaders(t, res, "application/json", "GET, POST, PUT, DELETE, OPTIONS")
+
+	body := test.PrintBody(t, res)
+
+	result := batches.RequireBatch(t, body)
+	assert.Equal(t, "4", result.ID)
+	assert.Equal(t, input.OrderID, result.OrderID)
+	assert.Equal(t, input.Description, result.Description)
+	assert.Equal(t, input.State, result.State)
+	assert.Equal(t, btest.DBQueryOne(result.ID), result)
+}
+
+func TestPOST_Batches_2(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Orders and Batches already exist on the server
+		When a new Batch belonging to a non-existent Order is POSTed
+		Ensure the response code is 400
+		And the body is a JSON object representing an error response
+		And no Batch has been created
+	`)
+
+	btest.SetupTest()
+	defer btest.TearDown()
+
+	input := batches.NewBatch{
+		OrderID:     "99999",
+		Description: "Stretch the leather",
+		State:       "Not started",
+	}
+	buf := new(bytes.Buffer)
+	json.NewEncoder(buf).Encode(&input)
+
+	req := test.APICall{
+		URL:    test.Host + "/batches",
+		Method: "POST",
+		Body:   buf,
+	}
+	res := req.Fire()
+	defer res.Body.Close()
+
+	require.Equal(t, 400, res.StatusCode)
+	test.AssertDefaultHeaders(t, res, "application/problem+json", "GET, POST, PUT, DELETE, OPTIONS")
+	test.AssertErrorBody(t, test.PrintBody(t, res))
+	assert.Len(t, btest.DBQueryAll(), 3)
+}
+
+func TestPOST_Batches_3(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given an Order but no Batches exist on the server
+		When many new Batches are POSTed concurrently
+		Ensure the response code of each is 201
+		And every Batch created is assigned a unique ID
+		And every Batch created exists within the database
+	`)
+
+	btest.SetupEmptyTest()
+	defer btest.TearDown()
+
+	o := btest.InjectOrder(orders.NewOrder{
+		Description: "Forge the blade",
+		State:       "Not started",
+	})
+
+	const n = 20
+	ids := make(chan string, n)
+	wg := sync.WaitGroup{}
+
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			input := batches.NewBatch{
+				OrderID:     o.ID,
+				Description: fmt.Sprintf("Batch %d", i),
+				State:       "Not started",
+			}
+			buf := new(bytes.Buffer)
+			json.NewEncoder(buf).Encode(&input)
+
+			req := test.APICall{
+				URL:    test.Host + "/batches",
+				Method: "POST",
+				Body:   buf,
+			}
+			res := req.Fire()
+			defer res.Body.Close()
+
+			if !assert.Equal(t, 201, res.StatusCode) {
+				return
+			}
+
+			b := batches.Batch{}
+			err := json.NewDecoder(res.Body).Decode(&b)
+			if assert.Nil(t, err) {
+				ids <- b.ID
+			}
+		}(i)
+	}
+
+	wg.Wait()
+	close(ids)
+
+	unique := map[string]bool{}
+	for id := range ids {
+		assert.False(t, unique[id], "Batch ID '%s' was assigned more than once", id)
+		unique[id] = true
+	}
+
+	assert.Len(t, unique, n)
+	assert.Len(t, btest.DBQueryAll(), n)
+}
@@ -0,0 +1,153 @@
+package PUT
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/PaulioRandall/go-qlueless-api/api/batches"
+	"github.com/PaulioRandall/go-qlueless-api/test"
+	btest "github.com/PaulioRandall/go-qlueless-api/test/batches"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	test.SetWorkingDir("../../../bin")
+}
+
+// ****************************************************************************
+// (PUT) /batches
+// ****************************************************************************
+
+func TestPUT_Batches_1(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Orders and Batches already exist on the server
+		When a Batch is moved to another Order and PUT to the server
+		Ensure the response code is 200
+		And the body is a JSON array containing the updated Batch
+		And the Batch now belongs to the other Order
+	`)
+
+	btest.SetupTest()
+	defer btest.TearDown()
+
+	input := batches.ModBatch{
+		IDs:   "2",
+		Props: "order_id, state",
+		Values: batches.Batch{
+			OrderID: "2",
+			State:   "Finished",
+		},
+	}
+	buf := new(bytes.Buffer)
+	json.NewEncoder(buf).Encode(&input)
+
+	req := test.APICall{
+		URL:    test.Host + "/batches",
+		Method: "PUT",
+		Body:   buf,
+	}
+	res := req.Fire()
+	defer res.Body.Close()
+
+	require.Equal(t, 200, res.StatusCode)
+	test.AssertDefaultHeaders(t, res, "application/json", "GET, POST, PUT, DELETE, OPTIONS")
+
+	body := test.PrintBody(t, res)
+
+	result := batches.RequireSliceOfBatches(t, body)
+	require.Len(t, result, 1)
+
+	after := btest.DBQueryOne("2")
+	assert.Equal(t, "2", after.OrderID)
+	assert.Equal(t, "Finished", after.State)
+}
+
+func TestPUT_Batches_2(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Orders and Batches already exist on the server
+		When a Batch is moved to a non-existent Order and PUT to the server
+		Ensure the response code is 400
+		And the body lists a 'values.order_id' not_found violation
+		And the Batch is unchanged
+	`)
+
+	btest.SetupTest()
+	defer btest.TearDown()
+
+	before := btest.DBQueryOne("2")
+
+	input := batches.ModBatch{
+		IDs:   "2",
+		Props: "order_id",
+		Values: batches.Batch{
+			OrderID: "99999",
+		},
+	}
+	buf := new(bytes.Buffer)
+	json.NewEncoder(buf).Encode(&input)
+
+	req := test.APICall{
+		URL:    test.Host + "/batches",
+		Method: "PUT",
+		Body:   buf,
+	}
+	res := req.Fire()
+	defer res.Body.Close()
+
+	require.Equal(t, 400, res.StatusCode)
+	reply := test.AssertErrorBody(t, test.PrintBody(t, res))
+	require.Len(t, reply.Errors, 1)
+	assert.Equal(t, "values.order_id", reply.Errors[0].Field)
+	assert.Equal(t, "not_found", reply.Errors[0].Code)
+	assert.Equal(t, before, btest.DBQueryOne("2"))
+}
+
+func TestPUT_Batches_3(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Orders and Batches already exist on the server
+		When a Batch is modified by many PUTs in quick succession
+		Ensure the response code of each is 200
+		And the last modified time of each revision is later than the previous
+		And the last modified time within each response matches the one stored
+	`)
+
+	btest.SetupTest()
+	defer btest.TearDown()
+
+	prev := btest.DBQueryOne("1").LastModified
+
+	for i := 0; i < 20; i++ {
+		input := batches.ModBatch{
+			IDs:   "1",
+			Props: "extra",
+			Values: batches.Batch{
+				Extra: fmt.Sprintf("revision: %d", i),
+			},
+		}
+		buf := new(bytes.Buffer)
+		json.NewEncoder(buf).Encode(&input)
+
+		req := test.APICall{
+			URL:    test.Host + "/batches",
+			Method: "PUT",
+			Body:   buf,
+		}
+		res := req.Fire()
+		defer res.Body.Close()
+
+		require.Equal(t, 200, res.StatusCode, "PUT %d", i)
+		result := batches.RequireSliceOfBatches(t, test.PrintBody(t, res))
+		require.Len(t, result, 1)
+
+		after := btest.DBQueryOne("1")
+		assert.Equal(t, after.LastModified, result[0].LastModified, "PUT %d", i)
+		assert.True(t, after.LastModified > prev, "PUT %d", i)
+		prev = after.LastModified
+	}
+}
@@ -0,0 +1,163 @@
+package batches
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/PaulioRandall/go-qlueless-api/api/batches"
+	"github.com/PaulioRandall/go-qlueless-api/api/config"
+	"github.com/PaulioRandall/go-qlueless-api/api/orders"
+	"github.com/PaulioRandall/go-qlueless-api/test"
+)
+
+// SetupEmptyTest is run at the start of a test to setup the server but does
+// not inject any test data.
+func SetupEmptyTest() {
+	test.StartServer(config.Default(), nil)
+}
+
+// SetupTest is run at the start of every test to setup the server and inject
+// the test data.
+func SetupTest() {
+	test.StartServer(config.Default(), func() {
+		DBInjectLiving()
+	})
+}
+
+// TearDown should be deferred straight after SetupTest() is run to close
+// resources at the end of every test.
+func TearDown() {
+	test.StopServer()
+}
+
+// InjectAll injects a slice of Batches into the database.
+func InjectAll(new []batches.NewBatch) []batches.Batch {
+	result := make([]batches.Batch, len(new))
+	for i, b := range new {
+		result[i] = *Inject(b)
+	}
+	return result
+}
+
+// Inject injects a Batch into the database.
+func Inject(new batches.NewBatch) *batches.Batch {
+	b, ok := new.Insert(test.DB())
+	if !ok {
+		panic("Already printed above!")
+	}
+	return b
+}
+
+// InjectOrder injects an Order into the database.
+func InjectOrder(new orders.NewOrder) *orders.Order {
+	o, ok := new.Insert(test.DB())
+	if !ok {
+		panic("Already printed above!")
+	}
+	return o
+}
+
+// DBInjectLiving injects a default set of living Orders and Batches into the
+// database
+func DBInjectLiving() {
+	InjectOrder(orders.NewOrder{
+		Description: "Forge the sword",
+		State:       "In progress",
+	})
+	InjectOrder(orders.NewOrder{
+		Description: "Forge the shield",
+		State:       "Not started",
+	})
+	Inject(batches.NewBatch{
+		OrderID:     "1",
+		Description: "Smelt the ore",
+		State:       "Finished",
+		Extra:       "metal: iron",
+	})
+	Inject(batches.NewBatch{
+		OrderID:     "1",
+		Description: "Hammer the blade",
+		State:       "In progress",
+	})
+	Inject(batches.NewBatch{
+		OrderID:     "2",
+		Description: "Cut the boards",
+		State:       "Not started",
+	})
+}
+
+// DBQueryAll queries the database for all living Batches
+func DBQueryAll() []batches.Batch {
+	rows, err := test.DB().Query(`
+		SELECT id, order_id, last_modified, description, state, extra
+		FROM ql_batch
+		ORDER BY id ASC
+	`)
+
+	if rows != nil {
+		defer rows.Close()
+	}
+
+	if err != nil {
+		panic(err)
+	}
+
+	return mapRows(rows)
+}
+
+// DBQueryMany queries the database for Batches with the specified IDs
+func DBQueryMany(ids string) []batches.Batch {
+	rows, err := test.DB().Query(fmt.Sprintf(`
+		SELECT id, order_id, last_modified, description, state, extra
+		FROM ql_batch
+		WHERE id IN (%s)
+		ORDER BY id ASC`, ids))
+
+	if rows != nil {
+		defer rows.Close()
+	}
+
+	if err != nil {
+		panic(err)
+	}
+
+	return mapRows(rows)
+}
+
+// DBQueryOne queries the database for a specific Batch
+func DBQueryOne(id string) batches.Batch {
+	bats := DBQueryMany(id)
+	if len(bats) != 1 {
+		panic("Expected a single batch from query")
+	}
+	return bats[0]
+}
+
+// mapRows is a file private function that maps rows from a database query into
+// a slice of Batches.
+func mapRows(rows *sql.Rows) []batches.Batch {
+	bats := []batches.Batch{}
+
+	for rows.Next() {
+		bats = append(bats, *mapRow(rows))
+	}
+
+	return bats
+}
+
+// mapRow is a file private function that maps a single row from a database
+// query into a Batch.
+func mapRow(rows *sql.Rows) *batches.Batch {
+	b := batches.Batch{}
+	err := rows.Scan(&b.ID,
+		&b.OrderID,
+		&b.LastModified,
+		&b.Description,
+		&b.State,
+		&b.Extra)
+
+	if err != nil {
+		panic(err)
+	}
+	return &b
+}
@@ -3,7 +3,7 @@ package changelog
 import (
 	"testing"
 
-	server "github.com/PaulioRandall/go-qlueless-api/api/server"
+	config "github.com/PaulioRandall/go-qlueless-api/api/config"
 	test "github.com/PaulioRandall/go-qlueless-api/test"
 	require "github.com/stretchr/testify/require"
 )
@@ -28,11 +28,11 @@ func TestGET_Changelog(t *testing.T) {
 		And the body contains some data
 		...`)
 
-	server.StartUp(true)
-	defer server.Shutdown()
+	test.StartServer(config.Default(), nil)
+	defer test.StopServer()
 
 	req := test.APICall{
-		URL:    "http://localhost:8080/changelog",
+		URL:    test.Host + "/changelog",
 		Method: "GET",
 	}
 	res := req.Fire()
@@ -58,11 +58,11 @@ func TestOPTIONS_Changelog(t *testing.T) {
 		And there is NO response body
 		...`)
 
-	server.StartUp(true)
-	defer server.Shutdown()
+	test.StartServer(config.Default(), nil)
+	defer test.StopServer()
 
 	req := test.APICall{
-		URL:    "http://localhost:8080/changelog",
+		URL:    test.Host + "/changelog",
 		Method: "OPTIONS",
 	}
 	res := req.Fire()
@@ -88,10 +88,10 @@ func TestINVALID_Changelog(t *testing.T) {
 		And there is NO response body
 		...`)
 
-	server.StartUp(true)
-	defer server.Shutdown()
+	test.StartServer(config.Default(), nil)
+	defer test.StopServer()
 
-	test.VerifyBadMethods(t, "http://localhost:8080/changelog", "GET, OPTIONS", []string{
+	test.VerifyBadMethods(t, test.Host+"/changelog", "GET, OPTIONS", []string{
 		"POST",
 		"PUT",
 		"DELETE",
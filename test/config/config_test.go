@@ -0,0 +1,202 @@
+package config
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"testing"
+
+	config "github.com/PaulioRandall/go-qlueless-api/api/config"
+	test "github.com/PaulioRandall/go-qlueless-api/test"
+	assert "github.com/stretchr/testify/assert"
+	require "github.com/stretchr/testify/require"
+)
+
+func init() {
+	test.SetWorkingDir("../../bin")
+}
+
+// ****************************************************************************
+// config.Load()
+// ****************************************************************************
+
+func TestLoad_Defaults(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given no config file, environment variables, or flags
+		When the configuration is loaded
+		Ensure the default configuration is returned
+	`)
+
+	c, err := config.Load([]string{})
+	require.Nil(t, err)
+	assert.Equal(t, config.Default(), c)
+}
+
+func TestLoad_Layers(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given a config file, environment variables, and flags
+		When the configuration is loaded
+		Ensure the config file overrides the defaults
+		And environment variables override the config file
+		And flags override environment variables
+	`)
+
+	f := writeTempFile(t, `{
+		"addr": ":9000",
+		"database_dsn": "./file.db",
+		"openapi_path": "./file-openapi.json",
+		"workflow_path": "./file-workflow.json",
+		"cors_origins": ["http://file.example"]
+	}`)
+	defer os.Remove(f)
+
+	setEnv(t, "QLUELESS_DATABASE_DSN", "./env.db")
+	defer os.Unsetenv("QLUELESS_DATABASE_DSN")
+	setEnv(t, "QLUELESS_OPENAPI", "./env-openapi.json")
+	defer os.Unsetenv("QLUELESS_OPENAPI")
+	setEnv(t, "QLUELESS_WORKFLOW", "./env-workflow.json")
+	defer os.Unsetenv("QLUELESS_WORKFLOW")
+
+	c, err := config.Load([]string{
+		"-config", f,
+		"-openapi", "./flag-openapi.json",
+		"-workflow", "./flag-workflow.json",
+		"-cors-origins", "http://a.example, http://b.example",
+	})
+	require.Nil(t, err)
+
+	assert.Equal(t, ":9000", c.Addr)
+	assert.Equal(t, "./env.db", c.DatabaseDSN)
+	assert.Equal(t, "./flag-openapi.json", c.OpenAPIPath)
+	assert.Equal(t, "./CHANGELOG.md", c.ChangelogPath)
+	assert.Equal(t, "./flag-workflow.json", c.WorkflowPath)
+	assert.Equal(t, []string{"http://a.example", "http://b.example"}, c.CorsOrigins)
+}
+
+func TestLoad_MissingFile(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given a config file that does not exist
+		When the configuration is loaded using that file
+		Ensure an error is returned
+	`)
+
+	_, err := config.Load([]string{"-config", "./does-not-exist.json"})
+	assert.NotNil(t, err)
+}
+
+func TestLoad_Invalid(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given flags that empty required properties
+		When the configuration is loaded
+		Ensure an error is returned
+	`)
+
+	_, err := config.Load([]string{"-addr", ""})
+	assert.NotNil(t, err)
+
+	_, err = config.Load([]string{"-cors-origins", " , "})
+	assert.NotNil(t, err)
+
+	_, err = config.Load([]string{"-workflow", ""})
+	assert.NotNil(t, err)
+}
+
+func TestLoad_WorkflowEnv(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given a config file setting the workflow path
+		And an environment variable setting the workflow path
+		When the configuration is loaded
+		Ensure the environment variable overrides the config file
+	`)
+
+	f := writeTempFile(t, `{"workflow_path": "./file-workflow.json"}`)
+	defer os.Remove(f)
+
+	c, err := config.Load([]string{"-config", f})
+	require.Nil(t, err)
+	assert.Equal(t, "./file-workflow.json", c.WorkflowPath)
+
+	setEnv(t, "QLUELESS_WORKFLOW", "./env-workflow.json")
+	defer os.Unsetenv("QLUELESS_WORKFLOW")
+
+	c, err = config.Load([]string{"-config", f})
+	require.Nil(t, err)
+	assert.Equal(t, "./env-workflow.json", c.WorkflowPath)
+}
+
+// ****************************************************************************
+// CORS origins
+// ****************************************************************************
+
+func TestCorsOrigins_1(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given the server only allows CORS requests from specific origins
+		When a request is made from one of those origins
+		Ensure 'Access-Control-Allow-Origin' is the origin of the request
+	`)
+
+	c := config.Default()
+	c.CorsOrigins = []string{"http://a.example", "http://b.example"}
+	test.StartServer(c, nil)
+	defer test.StopServer()
+
+	res := fireFromOrigin(t, "http://b.example")
+	defer res.Body.Close()
+
+	require.Equal(t, 200, res.StatusCode)
+	assert.Equal(t, "http://b.example", res.Header.Get("Access-Control-Allow-Origin"))
+}
+
+func TestCorsOrigins_2(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given the server only allows CORS requests from specific origins
+		When a request is made from any other origin
+		Ensure there is NO 'Access-Control-Allow-Origin' header
+	`)
+
+	c := config.Default()
+	c.CorsOrigins = []string{"http://a.example"}
+	test.StartServer(c, nil)
+	defer test.StopServer()
+
+	res := fireFromOrigin(t, "http://evil.example")
+	defer res.Body.Close()
+
+	require.Equal(t, 200, res.StatusCode)
+	assert.Empty(t, res.Header.Get("Access-Control-Allow-Origin"))
+}
+
+// fireFromOrigin requests the workflow OPTIONS as if from 'origin'.
+func fireFromOrigin(t *testing.T, origin string) *http.Response {
+	req, err := http.NewRequest("OPTIONS", test.Host+"/workflow", nil)
+	require.Nil(t, err)
+	req.Header.Set("Origin", origin)
+
+	res, err := http.DefaultClient.Do(req)
+	require.Nil(t, err)
+	return res
+}
+
+// writeTempFile writes 'content' to a new temporary file returning its path.
+func writeTempFile(t *testing.T, content string) string {
+	f, err := ioutil.TempFile("", "qlueless-config-*.json")
+	require.Nil(t, err)
+	defer f.Close()
+
+	_, err = f.WriteString(content)
+	require.Nil(t, err)
+	return f.Name()
+}
+
+// setEnv sets the environment variable 'k' to 'v'.
+func setEnv(t *testing.T, k string, v string) {
+	err := os.Setenv(k, v)
+	require.Nil(t, err)
+}
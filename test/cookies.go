@@ -21,6 +21,7 @@ type APICall struct {
 	URL    string
 	Method string
 	Body   io.Reader
+	Header http.Header
 }
 
 // newRequest is a file private function for creating new requests
@@ -29,6 +30,11 @@ func (c *APICall) newRequest() *http.Request {
 	if err != nil {
 		log.Panic("newRequest(): ", err)
 	}
+
+	for k, v := range c.Header {
+		req.Header[k] = v
+	}
+
 	return req
 }
 
@@ -57,8 +63,8 @@ func CallWithJSON(method string, url string, data interface{}) *http.Response {
 	json.NewEncoder(buf).Encode(&data)
 
 	req := APICall{
-		URL:    "http://localhost:8080/ventures",
-		Method: "PUT",
+		URL:    url,
+		Method: method,
 		Body:   buf,
 	}
 
@@ -0,0 +1,136 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	config "github.com/PaulioRandall/go-qlueless-api/api/config"
+	database "github.com/PaulioRandall/go-qlueless-api/api/database"
+	test "github.com/PaulioRandall/go-qlueless-api/test"
+	assert "github.com/stretchr/testify/assert"
+	require "github.com/stretchr/testify/require"
+)
+
+func init() {
+	test.SetWorkingDir("../../bin")
+}
+
+// setup starts a server with all tables created, by its schema migrations,
+// but no data injected.
+func setup() {
+	test.StartServer(config.Default(), nil)
+}
+
+// insertVenture inserts a Venture revision directly into the venture table.
+func insertVenture(t *testing.T, id string, state string, dead bool) {
+	_, err := test.DB().Exec(`INSERT INTO venture (
+		id, description, order_ids, state, is_dead
+	) VALUES (
+		$1, $2, '', $3, $4
+	);`, id, "Venture "+id, state, dead)
+	require.Nil(t, err)
+	time.Sleep(2 * time.Millisecond)
+}
+
+// queryQlVenture returns the state of the Venture within the ql_venture table
+// or an empty string if it's not there.
+func queryQlVenture(t *testing.T, id string) string {
+	rows, err := test.DB().Query(`SELECT state FROM ql_venture WHERE id = $1`, id)
+	require.Nil(t, err)
+	defer rows.Close()
+
+	state := ""
+	if rows.Next() {
+		require.Nil(t, rows.Scan(&state))
+	}
+	return state
+}
+
+// ****************************************************************************
+// database.DialectOf()
+// ****************************************************************************
+
+func TestDialectOf(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some DSNs
+		When the dialect of each is determined
+		Ensure DSNs with the 'postgres' or 'postgresql' scheme are PostgreSQL
+		And everything else is SQLite
+	`)
+
+	assert.Equal(t, database.Postgres, database.DialectOf("postgres://u:p@localhost/db"))
+	assert.Equal(t, database.Postgres, database.DialectOf("postgresql://localhost/db"))
+	assert.Equal(t, database.SQLite, database.DialectOf("./qlueless.db"))
+	assert.Equal(t, database.SQLite, database.DialectOf("file:qlueless.db?cache=shared"))
+}
+
+// ****************************************************************************
+// Query layer projection
+// ****************************************************************************
+
+func TestProjection(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given a database with all tables created
+		When revisions of a Venture are inserted into the venture table
+		Ensure the ql_venture table holds the latest revision while living
+		And the Venture is removed from ql_venture once a dead revision is
+		inserted
+	`)
+
+	setup()
+	defer test.StopServer()
+
+	insertVenture(t, "1", "Not started", false)
+	assert.Equal(t, "Not started", queryQlVenture(t, "1"))
+
+	insertVenture(t, "1", "In progress", false)
+	assert.Equal(t, "In progress", queryQlVenture(t, "1"))
+
+	insertVenture(t, "1", "In progress", true)
+	assert.Empty(t, queryQlVenture(t, "1"))
+}
+
+// ****************************************************************************
+// Append only tables
+// ****************************************************************************
+
+func TestAppendOnly(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given a database with a revision in each of the append only tables
+		When an update or delete is attempted on any of them
+		Ensure an error is returned
+		And the revision is unchanged
+	`)
+
+	setup()
+	defer test.StopServer()
+
+	insertVenture(t, "1", "Not started", false)
+
+	_, err := test.DB().Exec(`INSERT INTO "order" (
+		id, description, state
+	) VALUES (
+		1, 'Order 1', 'Open'
+	);`)
+	require.Nil(t, err)
+
+	_, err = test.DB().Exec(`INSERT INTO batch (
+		id, order_id, description, state
+	) VALUES (
+		1, 1, 'Batch 1', 'Open'
+	);`)
+	require.Nil(t, err)
+
+	for _, table := range []string{"venture", `"order"`, "batch"} {
+		_, err = test.DB().Exec(`UPDATE ` + table + ` SET state = 'Closed'`)
+		assert.NotNil(t, err, "Expected update of %s to fail", table)
+
+		_, err = test.DB().Exec(`DELETE FROM ` + table)
+		assert.NotNil(t, err, "Expected delete from %s to fail", table)
+	}
+
+	assert.Equal(t, "Not started", queryQlVenture(t, "1"))
+}
@@ -0,0 +1,222 @@
+package database
+
+import (
+	"testing"
+
+	batches "github.com/PaulioRandall/go-qlueless-api/api/batches"
+	migrate "github.com/PaulioRandall/go-qlueless-api/api/migrate"
+	orders "github.com/PaulioRandall/go-qlueless-api/api/orders"
+	ventures "github.com/PaulioRandall/go-qlueless-api/api/ventures"
+	test "github.com/PaulioRandall/go-qlueless-api/test"
+	assert "github.com/stretchr/testify/assert"
+	require "github.com/stretchr/testify/require"
+)
+
+// queryTable returns an error if the table 'name' can not be queried.
+func queryTable(name string) error {
+	rows, err := test.DB().Query(`SELECT * FROM ` + name)
+	if err != nil {
+		return err
+	}
+	return rows.Close()
+}
+
+// downTo rolls back migrations until the one named 'name' has been rolled back.
+func downTo(t *testing.T, name string) {
+	for {
+		m, err := migrate.Down(test.DB(), test.Dialect())
+		require.Nil(t, err)
+		require.NotNil(t, m, "Expected migration '%s' to have been applied", name)
+		if m.Name == name {
+			return
+		}
+	}
+}
+
+// ****************************************************************************
+// migrate.Up()
+// ****************************************************************************
+
+func TestMigrate_Startup(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given a new server with an empty database
+		When it has started
+		Ensure every migration has been applied
+		And applying migrations again does nothing
+	`)
+
+	setup()
+	defer test.StopServer()
+
+	recs, err := migrate.Status(test.DB())
+	require.Nil(t, err)
+	require.NotEmpty(t, recs)
+
+	for _, r := range recs {
+		assert.False(t, r.Pending(), "Expected migration %d to be applied", r.Version)
+	}
+
+	done, err := migrate.Up(test.DB(), test.Dialect())
+	require.Nil(t, err)
+	assert.Empty(t, done)
+}
+
+func TestMigrate_UnknownVersion(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given a database migrated by a newer build
+		When migrations are applied
+		Ensure an error is returned
+	`)
+
+	setup()
+	defer test.StopServer()
+
+	_, err := test.DB().Exec(`INSERT INTO schema_migrations (
+		version, name, applied
+	) VALUES (
+		999999, 'from_the_future', 1
+	);`)
+	require.Nil(t, err)
+
+	_, err = migrate.Up(test.DB(), test.Dialect())
+	assert.NotNil(t, err)
+}
+
+// ****************************************************************************
+// migrate.Down()
+// ****************************************************************************
+
+func TestMigrate_DownThenUp(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given a fully migrated database
+		When the latest migration is rolled back
+		Ensure only that migration is pending
+		And its tables have been dropped
+		And applying migrations reapplies only that migration
+	`)
+
+	setup()
+	defer test.StopServer()
+
+	require.Nil(t, queryTable("batch_seq"))
+
+	m, err := migrate.Down(test.DB(), test.Dialect())
+	require.Nil(t, err)
+	require.NotNil(t, m)
+	assert.Equal(t, "create_batch_seq", m.Name)
+	assert.NotNil(t, queryTable("batch_seq"))
+	assert.Nil(t, queryTable("batch"))
+
+	recs, err := migrate.Status(test.DB())
+	require.Nil(t, err)
+	for _, r := range recs {
+		assert.Equal(t, r.Version == m.Version, r.Pending())
+	}
+
+	done, err := migrate.Up(test.DB(), test.Dialect())
+	require.Nil(t, err)
+	require.Len(t, done, 1)
+	assert.Equal(t, m.Version, done[0].Version)
+	assert.Nil(t, queryTable("batch_seq"))
+}
+
+func TestMigrate_SeqSeeded(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given a database with Ventures but no Venture ID sequence
+		When migrations are applied
+		Ensure the next Venture created is assigned the ID after the highest in
+		use
+	`)
+
+	setup()
+	defer test.StopServer()
+
+	downTo(t, "create_venture_seq")
+	insertVenture(t, "7", "Not started", false)
+
+	_, err := migrate.Up(test.DB(), test.Dialect())
+	require.Nil(t, err)
+
+	ven, err := test.Ventures().Create(&ventures.NewVenture{
+		Description: "White wizard",
+		State:       "Not started",
+	})
+	require.Nil(t, err)
+	assert.Equal(t, "8", ven.ID)
+}
+
+func TestMigrate_OrderSeqSeeded(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given a database with Orders but no Order ID sequence
+		When migrations are applied
+		Ensure the next Order created is assigned the ID after the highest in
+		use
+	`)
+
+	setup()
+	defer test.StopServer()
+
+	downTo(t, "create_order_seq")
+
+	_, err := test.DB().Exec(`INSERT INTO "order" (
+		id, description, state
+	) VALUES (
+		7, 'Order 7', 'Open'
+	);`)
+	require.Nil(t, err)
+
+	_, err = migrate.Up(test.DB(), test.Dialect())
+	require.Nil(t, err)
+
+	ord, ok := (&orders.NewOrder{
+		Description: "Ring",
+		State:       "Open",
+	}).Insert(test.DB())
+	require.True(t, ok)
+	assert.Equal(t, "8", ord.ID)
+}
+
+func TestMigrate_BatchSeqSeeded(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given a database with Batches but no Batch ID sequence
+		When migrations are applied
+		Ensure the next Batch created is assigned the ID after the highest in
+		use
+	`)
+
+	setup()
+	defer test.StopServer()
+
+	downTo(t, "create_batch_seq")
+
+	_, err := test.DB().Exec(`INSERT INTO "order" (
+		id, description, state
+	) VALUES (
+		1, 'Order 1', 'Open'
+	);`)
+	require.Nil(t, err)
+
+	_, err = test.DB().Exec(`INSERT INTO batch (
+		id, order_id, description, state
+	) VALUES (
+		7, 1, 'Batch 7', 'Open'
+	);`)
+	require.Nil(t, err)
+
+	_, err = migrate.Up(test.DB(), test.Dialect())
+	require.Nil(t, err)
+
+	b, ok := (&batches.NewBatch{
+		OrderID:     "1",
+		Description: "Hilt",
+		State:       "Open",
+	}).Insert(test.DB())
+	require.True(t, ok)
+	assert.Equal(t, "8", b.ID)
+}
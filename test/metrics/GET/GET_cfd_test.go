@@ -0,0 +1,241 @@
+package GET
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	toastify "github.com/PaulioRandall/go-cookies/toastify"
+	metrics "github.com/PaulioRandall/go-qlueless-api/api/metrics"
+	test "github.com/PaulioRandall/go-qlueless-api/test"
+	mtest "github.com/PaulioRandall/go-qlueless-api/test/metrics"
+	assert "github.com/stretchr/testify/assert"
+	require "github.com/stretchr/testify/require"
+)
+
+const hour int64 = 60 * 60 * 1000
+
+// ****************************************************************************
+// (GET) /metrics/cfd
+// ****************************************************************************
+
+func TestGET_CFD_1(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Ventures and Orders with a history of state changes exist on
+		the server
+		When the CFD of a time window smaller than one bucket is requested
+		Ensure the response code is 200
+		And header includes:
+			Content-Type:                   'application/json; charset=utf-8'
+			Access-Control-Allow-Methods:   'GET, OPTIONS'
+		And the body contains a single bucket ending at the end of the window
+		And the bucket counts the living Ventures in every workflow state
+		And the bucket counts the living Orders in each state
+	`)
+
+	mtest.SetupTest()
+	defer mtest.TearDown()
+
+	req := test.APICall{
+		URL:    test.Host + "/metrics/cfd?from=0&to=6000&bucket=hour",
+		Method: "GET",
+	}
+	res := req.Fire()
+	defer res.Body.Close()
+
+	require.Equal(t, 200, res.StatusCode)
+	test.AssertDefaultHeaders(t, res, "application/json", "GET, OPTIONS")
+
+	c := requireCFD(t, res)
+	assert.Equal(t, int64(0), c.From)
+	assert.Equal(t, int64(6000), c.To)
+	assert.Equal(t, "hour", c.Bucket)
+	require.Len(t, c.Buckets, 1)
+
+	b := c.Buckets[0]
+	assert.Equal(t, int64(0), b.Start)
+	assert.Equal(t, int64(6000), b.End)
+	assert.Equal(t, map[string]int{
+		"Not started": 0,
+		"In progress": 1,
+		"Finished":    1,
+		"Closed":      0,
+	}, b.Ventures)
+	assert.Equal(t, map[string]int{
+		"Shipped": 1,
+	}, b.Orders)
+}
+
+func TestGET_CFD_2(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Ventures change state across several hours
+		When the CFD of those hours is requested in hourly buckets
+		Ensure the response code is 200
+		And the body contains one bucket per hour
+		And each bucket counts the living Ventures in each state at its end
+	`)
+
+	mtest.SetupEmptyTest()
+	defer mtest.TearDown()
+
+	mtest.InjectRevision("1", 0, "Not started", false)
+	mtest.InjectRevision("1", hour+1, "In progress", false)
+	mtest.InjectRevision("2", 2*hour+1, "Not started", false)
+
+	req := test.APICall{
+		URL:    test.Host + "/metrics/cfd?from=0&to=10800000&bucket=hour",
+		Method: "GET",
+	}
+	res := req.Fire()
+	defer res.Body.Close()
+
+	require.Equal(t, 200, res.StatusCode)
+
+	c := requireCFD(t, res)
+	require.Len(t, c.Buckets, 3)
+
+	for i, b := range c.Buckets {
+		assert.Equal(t, int64(i)*hour, b.Start)
+		assert.Equal(t, int64(i+1)*hour, b.End)
+		assert.Empty(t, b.Orders)
+	}
+
+	assert.Equal(t, 1, c.Buckets[0].Ventures["Not started"])
+	assert.Equal(t, 0, c.Buckets[0].Ventures["In progress"])
+	assert.Equal(t, 0, c.Buckets[1].Ventures["Not started"])
+	assert.Equal(t, 1, c.Buckets[1].Ventures["In progress"])
+	assert.Equal(t, 1, c.Buckets[2].Ventures["Not started"])
+	assert.Equal(t, 1, c.Buckets[2].Ventures["In progress"])
+}
+
+func TestGET_CFD_3(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Ventures and Orders with a history of state changes exist on
+		the server
+		When the CFD is requested as CSV
+		Ensure the response code is 200
+		And header includes:
+			Content-Type:                   'text/csv; charset=utf-8'
+			Access-Control-Allow-Methods:   'GET, OPTIONS'
+		And the body contains a header row followed by one row per bucket,
+		resource, and state
+	`)
+
+	mtest.SetupTest()
+	defer mtest.TearDown()
+
+	req := test.APICall{
+		URL:    test.Host + "/metrics/cfd?from=0&to=6000&bucket=hour&format=csv",
+		Method: "GET",
+	}
+	res := req.Fire()
+	defer res.Body.Close()
+
+	require.Equal(t, 200, res.StatusCode)
+	test.AssertCorsHeaders(t, res, "GET, OPTIONS")
+	toastify.AssertHeaderEqual(t, "Content-Type", res.Header, "text/csv; charset=utf-8")
+
+	rows, err := csv.NewReader(test.PrintBody(t, res)).ReadAll()
+	require.Nil(t, err)
+	assert.Equal(t, [][]string{
+		{"start", "end", "resource", "state", "count"},
+		{"0", "6000", "ventures", "Not started", "0"},
+		{"0", "6000", "ventures", "In progress", "1"},
+		{"0", "6000", "ventures", "Finished", "1"},
+		{"0", "6000", "ventures", "Closed", "0"},
+		{"0", "6000", "orders", "Shipped", "1"},
+	}, rows)
+}
+
+func TestGET_CFD_4(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Ventures with a history of state changes exist on the server
+		When the CFD is requested with an unknown bucket size
+		Ensure the response code is 400
+		And the body is a JSON object representing an error response
+		And the error lists a violation of 'bucket'
+	`)
+
+	mtest.SetupTest()
+	defer mtest.TearDown()
+
+	req := test.APICall{
+		URL:    test.Host + "/metrics/cfd?bucket=fortnight",
+		Method: "GET",
+	}
+	res := req.Fire()
+	defer res.Body.Close()
+
+	require.Equal(t, 400, res.StatusCode)
+	p := test.AssertErrorBody(t, test.PrintBody(t, res))
+	require.Len(t, p.Errors, 1)
+	assert.Equal(t, "bucket", p.Errors[0].Field)
+	assert.Equal(t, "invalid", p.Errors[0].Code)
+}
+
+func TestGET_CFD_5(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Ventures with a history of state changes exist on the server
+		When the CFD of a time window that ends before it starts is requested
+		Ensure the response code is 400
+		And the body is a JSON object representing an error response
+		And the error lists a violation of 'from'
+	`)
+
+	mtest.SetupTest()
+	defer mtest.TearDown()
+
+	req := test.APICall{
+		URL:    test.Host + "/metrics/cfd?from=6000&to=4000",
+		Method: "GET",
+	}
+	res := req.Fire()
+	defer res.Body.Close()
+
+	require.Equal(t, 400, res.StatusCode)
+	p := test.AssertErrorBody(t, test.PrintBody(t, res))
+	require.Len(t, p.Errors, 1)
+	assert.Equal(t, "from", p.Errors[0].Field)
+	assert.Equal(t, "invalid", p.Errors[0].Code)
+}
+
+func TestGET_CFD_6(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Ventures with a history of state changes exist on the server
+		When the CFD of a time window containing too many buckets is requested
+		Ensure the response code is 400
+		And the body is a JSON object representing an error response
+		And the error lists a violation of 'bucket'
+	`)
+
+	mtest.SetupTest()
+	defer mtest.TearDown()
+
+	req := test.APICall{
+		URL:    test.Host + "/metrics/cfd?from=0&to=1560000000000&bucket=hour",
+		Method: "GET",
+	}
+	res := req.Fire()
+	defer res.Body.Close()
+
+	require.Equal(t, 400, res.StatusCode)
+	p := test.AssertErrorBody(t, test.PrintBody(t, res))
+	require.Len(t, p.Errors, 1)
+	assert.Equal(t, "bucket", p.Errors[0].Field)
+	assert.Equal(t, "too_many", p.Errors[0].Code)
+}
+
+// requireCFD decodes the response body into a CFD.
+func requireCFD(t *testing.T, res *http.Response) metrics.CFD {
+	var c metrics.CFD
+	err := json.NewDecoder(test.PrintBody(t, res)).Decode(&c)
+	require.Nil(t, err)
+	return c
+}
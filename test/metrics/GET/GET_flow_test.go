@@ -0,0 +1,208 @@
+package GET
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	metrics "github.com/PaulioRandall/go-qlueless-api/api/metrics"
+	test "github.com/PaulioRandall/go-qlueless-api/test"
+	mtest "github.com/PaulioRandall/go-qlueless-api/test/metrics"
+	assert "github.com/stretchr/testify/assert"
+	require "github.com/stretchr/testify/require"
+)
+
+func init() {
+	test.SetWorkingDir("../../../bin")
+}
+
+// ****************************************************************************
+// (GET) /metrics/flow
+// ****************************************************************************
+
+func TestGET_Flow_1(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Ventures with a history of state changes exist on the server
+		When the flow of Ventures up until a specific time is requested
+		Ensure the response code is 200
+		And header includes:
+			Content-Type:                   'application/json; charset=utf-8'
+			Access-Control-Allow-Methods:   'GET, OPTIONS'
+		And the body contains the time in state, cycle time, lead time, and
+		age in state of every Venture
+		And the body contains the aggregate flow of all Ventures
+	`)
+
+	mtest.SetupTest()
+	defer mtest.TearDown()
+
+	req := test.APICall{
+		URL:    test.Host + "/metrics/flow?until=6000",
+		Method: "GET",
+	}
+	res := req.Fire()
+	defer res.Body.Close()
+
+	require.Equal(t, 200, res.StatusCode)
+	test.AssertDefaultHeaders(t, res, "application/json", "GET, OPTIONS")
+
+	f := requireFlow(t, res)
+	assert.Equal(t, int64(0), f.Since)
+	assert.Equal(t, int64(6000), f.Until)
+	require.Len(t, f.Ventures, 3)
+
+	v := f.Ventures[0]
+	assert.Equal(t, "1", v.ID)
+	assert.Equal(t, "Finished", v.State)
+	assert.Equal(t, map[string]int64{
+		"Not started": 1000,
+		"In progress": 3000,
+		"Finished":    1000,
+	}, v.TimeInState)
+	assertMillis(t, 3000, v.CycleTime)
+	assertMillis(t, 4000, v.LeadTime)
+	assertMillis(t, 1000, v.AgeInState)
+
+	v = f.Ventures[1]
+	assert.Equal(t, "2", v.ID)
+	assert.Equal(t, "In progress", v.State)
+	assert.Equal(t, map[string]int64{
+		"Not started": 1500,
+		"In progress": 3000,
+	}, v.TimeInState)
+	assert.Nil(t, v.CycleTime)
+	assert.Nil(t, v.LeadTime)
+	assertMillis(t, 3000, v.AgeInState)
+
+	v = f.Ventures[2]
+	assert.Equal(t, "3", v.ID)
+	assert.True(t, v.Dead)
+	assert.Equal(t, map[string]int64{
+		"Not started": 1500,
+	}, v.TimeInState)
+	assert.Nil(t, v.AgeInState)
+
+	a := f.Aggregate
+	assert.Equal(t, 3, a.Count)
+	assert.Equal(t, map[string]int{
+		"In progress": 1,
+		"Finished":    1,
+	}, a.WIP)
+	assert.Equal(t, map[string]int64{
+		"Not started": 4000,
+		"In progress": 6000,
+		"Finished":    1000,
+	}, a.TimeInState)
+	assertMillis(t, 3000, a.MeanCycleTime)
+	assertMillis(t, 4000, a.MeanLeadTime)
+	assert.Equal(t, map[string]int64{
+		"In progress": 3000,
+		"Finished":    1000,
+	}, a.MeanAgeInState)
+}
+
+func TestGET_Flow_2(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Ventures with a history of state changes exist on the server
+		When the flow of Ventures in a specific state within a time window is
+		requested
+		Ensure the response code is 200
+		And the body only contains the Ventures in that state at the end of the
+		time window
+		And the time in state only includes time spent within the window
+	`)
+
+	mtest.SetupTest()
+	defer mtest.TearDown()
+
+	req := test.APICall{
+		URL:    test.Host + "/metrics/flow?since=4000&until=6000&state=in-progress",
+		Method: "GET",
+	}
+	res := req.Fire()
+	defer res.Body.Close()
+
+	require.Equal(t, 200, res.StatusCode)
+
+	f := requireFlow(t, res)
+	require.Len(t, f.Ventures, 1)
+
+	v := f.Ventures[0]
+	assert.Equal(t, "2", v.ID)
+	assert.Equal(t, map[string]int64{
+		"In progress": 2000,
+	}, v.TimeInState)
+	assertMillis(t, 3000, v.AgeInState)
+	assert.Equal(t, 1, f.Aggregate.Count)
+}
+
+func TestGET_Flow_3(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Ventures with a history of state changes exist on the server
+		When the flow of Ventures in a state outside the workflow is requested
+		Ensure the response code is 400
+		And the body is a JSON object representing an error response
+		And the error lists a violation of 'state'
+	`)
+
+	mtest.SetupTest()
+	defer mtest.TearDown()
+
+	req := test.APICall{
+		URL:    test.Host + "/metrics/flow?state=wip",
+		Method: "GET",
+	}
+	res := req.Fire()
+	defer res.Body.Close()
+
+	require.Equal(t, 400, res.StatusCode)
+	p := test.AssertErrorBody(t, test.PrintBody(t, res))
+	require.Len(t, p.Errors, 1)
+	assert.Equal(t, "state", p.Errors[0].Field)
+	assert.Equal(t, "not_in_workflow", p.Errors[0].Code)
+}
+
+func TestGET_Flow_4(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Ventures with a history of state changes exist on the server
+		When the flow of Ventures within a time window that ends before it
+		starts is requested
+		Ensure the response code is 400
+		And the body is a JSON object representing an error response
+		And the error lists a violation of 'since'
+	`)
+
+	mtest.SetupTest()
+	defer mtest.TearDown()
+
+	req := test.APICall{
+		URL:    test.Host + "/metrics/flow?since=6000&until=4000",
+		Method: "GET",
+	}
+	res := req.Fire()
+	defer res.Body.Close()
+
+	require.Equal(t, 400, res.StatusCode)
+	p := test.AssertErrorBody(t, test.PrintBody(t, res))
+	require.Len(t, p.Errors, 1)
+	assert.Equal(t, "since", p.Errors[0].Field)
+	assert.Equal(t, "invalid", p.Errors[0].Code)
+}
+
+// requireFlow decodes the response body into a Flow.
+func requireFlow(t *testing.T, res *http.Response) metrics.Flow {
+	var f metrics.Flow
+	err := json.NewDecoder(test.PrintBody(t, res)).Decode(&f)
+	require.Nil(t, err)
+	return f
+}
+
+// assertMillis asserts a duration in milliseconds is present and as expected.
+func assertMillis(t *testing.T, exp int64, act *int64) {
+	require.NotNil(t, act)
+	assert.Equal(t, exp, *act)
+}
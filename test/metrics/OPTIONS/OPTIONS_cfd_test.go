@@ -0,0 +1,66 @@
+package OPTIONS
+
+import (
+	"testing"
+
+	test "github.com/PaulioRandall/go-qlueless-api/test"
+	mtest "github.com/PaulioRandall/go-qlueless-api/test/metrics"
+	require "github.com/stretchr/testify/require"
+)
+
+// ****************************************************************************
+// (OPTIONS) /metrics/cfd
+// ****************************************************************************
+
+func TestOPTIONS_CFD(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Ventures already exist on the server
+		When /metrics/cfd OPTIONS are requested
+		Ensure the response code is 200
+		And 'Access-Control-Allow-Methods' is 'GET, OPTIONS'
+		And there is NO response body
+	`)
+
+	mtest.SetupTest()
+	defer mtest.TearDown()
+
+	req := test.APICall{
+		URL:    test.Host + "/metrics/cfd",
+		Method: "OPTIONS",
+	}
+	res := req.Fire()
+
+	defer res.Body.Close()
+	defer test.PrintResponse(t, res.Body)
+
+	require.Equal(t, 200, res.StatusCode)
+	test.AssertCorsHeaders(t, res, "GET, OPTIONS")
+	test.AssertEmptyBody(t, res.Body)
+}
+
+// ****************************************************************************
+// (?) /metrics/cfd
+// ****************************************************************************
+
+func TestINVALID_CFD(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Ventures already exist on the server
+		When /metrics/cfd is called using invalid methods
+		Ensure the response code is 405
+		And 'Access-Control-Allow-Methods' is 'GET, OPTIONS'
+		And there is NO response body
+	`)
+
+	mtest.SetupTest()
+	defer mtest.TearDown()
+
+	test.VerifyBadMethods(t, test.Host+"/metrics/cfd", "GET, OPTIONS", []string{
+		"HEAD",
+		"CONNECT",
+		"TRACE",
+		"PATCH",
+		"CUSTOM",
+	})
+}
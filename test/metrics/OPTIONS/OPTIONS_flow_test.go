@@ -0,0 +1,70 @@
+package OPTIONS
+
+import (
+	"testing"
+
+	test "github.com/PaulioRandall/go-qlueless-api/test"
+	mtest "github.com/PaulioRandall/go-qlueless-api/test/metrics"
+	require "github.com/stretchr/testify/require"
+)
+
+func init() {
+	test.SetWorkingDir("../../../bin")
+}
+
+// ****************************************************************************
+// (OPTIONS) /metrics/flow
+// ****************************************************************************
+
+func TestOPTIONS_Flow(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Ventures already exist on the server
+		When /metrics/flow OPTIONS are requested
+		Ensure the response code is 200
+		And 'Access-Control-Allow-Methods' is 'GET, OPTIONS'
+		And there is NO response body
+	`)
+
+	mtest.SetupTest()
+	defer mtest.TearDown()
+
+	req := test.APICall{
+		URL:    test.Host + "/metrics/flow",
+		Method: "OPTIONS",
+	}
+	res := req.Fire()
+
+	defer res.Body.Close()
+	defer test.PrintResponse(t, res.Body)
+
+	require.Equal(t, 200, res.StatusCode)
+	test.AssertCorsHeaders(t, res, "GET, OPTIONS")
+	test.AssertEmptyBody(t, res.Body)
+}
+
+// ****************************************************************************
+// (?) /metrics/flow
+// ****************************************************************************
+
+func TestINVALID_Flow(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Ventures already exist on the server
+		When /metrics/flow is called using invalid methods
+		Ensure the response code is 405
+		And 'Access-Control-Allow-Methods' is 'GET, OPTIONS'
+		And there is NO response body
+	`)
+
+	mtest.SetupTest()
+	defer mtest.TearDown()
+
+	test.VerifyBadMethods(t, test.Host+"/metrics/flow", "GET, OPTIONS", []string{
+		"HEAD",
+		"CONNECT",
+		"TRACE",
+		"PATCH",
+		"CUSTOM",
+	})
+}
@@ -0,0 +1,76 @@
+package metrics
+
+import (
+	"github.com/PaulioRandall/go-qlueless-api/api/config"
+	"github.com/PaulioRandall/go-qlueless-api/test"
+)
+
+// SetupEmptyTest is run at the start of a test to setup the server but does
+// not inject any test data.
+func SetupEmptyTest() {
+	test.StartServer(config.Default(), nil)
+}
+
+// SetupTest is run at the start of every test to setup the server and inject
+// the test data.
+func SetupTest() {
+	test.StartServer(config.Default(), func() {
+		DBInjectHistory()
+	})
+}
+
+// TearDown should be deferred straight after SetupTest() is run to close
+// resources at the end of every test.
+func TearDown() {
+	test.StopServer()
+}
+
+// InjectRevision injects a single Venture revision, made at the Unix time 'ms'
+// in milliseconds, into the database.
+func InjectRevision(id string, ms int64, state string, dead bool) {
+	_, err := test.DB().Exec(`INSERT INTO venture (
+		id, last_modified, description, order_ids, state, is_dead
+	) VALUES (
+		$1, $2, $3, '', $4, $5
+	);`, id, ms, "Venture "+id, state, dead)
+
+	if err != nil {
+		panic(err)
+	}
+}
+
+// InjectOrderRevision injects a single Order revision, made at the Unix time
+// 'ms' in milliseconds, into the database.
+func InjectOrderRevision(id string, ms int64, state string, dead bool) {
+	_, err := test.DB().Exec(`INSERT INTO "order" (
+		id, last_modified, description, state, is_dead
+	) VALUES (
+		$1, $2, $3, $4, $5
+	);`, id, ms, "Order "+id, state, dead)
+
+	if err != nil {
+		panic(err)
+	}
+}
+
+// DBInjectHistory injects a default history of Venture and Order revisions
+// into the database:
+//
+//	Venture 1: 'Not started' at 1000, 'In progress' at 2000, and 'Finished' at 5000
+//	Venture 2: 'Not started' at 1500, and 'In progress' at 3000
+//	Venture 3: 'Not started' at 1000, and killed at 2500
+//	Order 1:   'Open' at 1000, and 'Shipped' at 3500
+//	Order 2:   'Open' at 2000, and killed at 4500
+func DBInjectHistory() {
+	InjectRevision("1", 1000, "Not started", false)
+	InjectRevision("1", 2000, "In progress", false)
+	InjectRevision("1", 5000, "Finished", false)
+	InjectRevision("2", 1500, "Not started", false)
+	InjectRevision("2", 3000, "In progress", false)
+	InjectRevision("3", 1000, "Not started", false)
+	InjectRevision("3", 2500, "Not started", true)
+	InjectOrderRevision("1", 1000, "Open", false)
+	InjectOrderRevision("1", 3500, "Shipped", false)
+	InjectOrderRevision("2", 2000, "Open", false)
+	InjectOrderRevision("2", 4500, "Open", true)
+}
@@ -4,7 +4,7 @@ import (
 	"encoding/json"
 	"testing"
 
-	server "github.com/PaulioRandall/go-qlueless-api/api/server"
+	config "github.com/PaulioRandall/go-qlueless-api/api/config"
 	test "github.com/PaulioRandall/go-qlueless-api/test"
 	require "github.com/stretchr/testify/require"
 )
@@ -29,11 +29,11 @@ func TestGET_OpenAPI(t *testing.T) {
 		And the body is a valid JSON object
 		...`)
 
-	server.StartUp(true)
-	defer server.Shutdown()
+	test.StartServer(config.Default(), nil)
+	defer test.StopServer()
 
 	req := test.APICall{
-		URL:    "http://localhost:8080/openapi",
+		URL:    test.Host + "/openapi",
 		Method: "GET",
 	}
 	res := req.Fire()
@@ -63,11 +63,11 @@ func TestOPTIONS_OpenAPI(t *testing.T) {
 		And there is NO response body
 		...`)
 
-	server.StartUp(true)
-	defer server.Shutdown()
+	test.StartServer(config.Default(), nil)
+	defer test.StopServer()
 
 	req := test.APICall{
-		URL:    "http://localhost:8080/openapi",
+		URL:    test.Host + "/openapi",
 		Method: "OPTIONS",
 	}
 	res := req.Fire()
@@ -94,10 +94,10 @@ func TestINVALID_OpenAPI(t *testing.T) {
 		And there is NO response body
 		...`)
 
-	server.StartUp(true)
-	defer server.Shutdown()
+	test.StartServer(config.Default(), nil)
+	defer test.StopServer()
 
-	test.VerifyBadMethods(t, "http://localhost:8080/openapi", "GET, OPTIONS", []string{
+	test.VerifyBadMethods(t, test.Host+"/openapi", "GET, OPTIONS", []string{
 		"POST",
 		"PUT",
 		"DELETE",
@@ -0,0 +1,225 @@
+package DELETE
+
+import (
+	"testing"
+
+	"github.com/PaulioRandall/go-qlueless-api/api/batches"
+	"github.com/PaulioRandall/go-qlueless-api/api/orders"
+	"github.com/PaulioRandall/go-qlueless-api/api/ventures"
+	"github.com/PaulioRandall/go-qlueless-api/test"
+	btest "github.com/PaulioRandall/go-qlueless-api/test/batches"
+	otest "github.com/PaulioRandall/go-qlueless-api/test/orders"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	test.SetWorkingDir("../../../bin")
+}
+
+// ****************************************************************************
+// (DELETE) /orders?ids={ids}
+// ****************************************************************************
+
+func TestDELETE_Orders_1(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Orders already exist on the server
+		When existing Orders are deleted
+		Ensure the response code is 200
+		And the body is a JSON array containing the deleted Orders
+		And those Orders are marked as dead
+		And those Orders are no longer among the living
+	`)
+
+	otest.SetupTest()
+	defer otest.TearDown()
+
+	req := test.APICall{
+		URL:    test.Host + "/orders?ids=1,2",
+		Method: "DELETE",
+	}
+	res := req.Fire()
+	defer res.Body.Close()
+
+	require.Equal(t, 200, res.StatusCode)
+	test.AssertDefaultHeaders(t, res, "application/json", "GET, POST, PUT, DELETE, OPTIONS")
+
+	body := test.PrintBody(t, res)
+
+	result := orders.RequireSliceOfOrders(t, body)
+	require.Len(t, result, 2)
+	for _, o := range result {
+		assert.True(t, o.Dead, "Order.Dead")
+	}
+
+	assert.Empty(t, otest.DBQueryMany("1,2"))
+	assert.Len(t, otest.DBQueryAll(), 1)
+}
+
+func TestDELETE_Orders_2(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Orders already exist on the server
+		And some Ventures reference those Orders
+		When existing Orders are deleted
+		Ensure the response code is 200
+		And the deleted Orders are pruned from the Ventures referencing them
+		And Ventures not referencing the deleted Orders are unchanged
+	`)
+
+	otest.SetupTest()
+	defer otest.TearDown()
+
+	pruned := otest.InjectVenture(ventures.NewVenture{
+		Description: "Arm the knight",
+		State:       "In progress",
+		Orders:      "1,2,3",
+	})
+	untouched := otest.InjectVenture(ventures.NewVenture{
+		Description: "Shield the knight",
+		State:       "Not started",
+		Orders:      "3",
+	})
+
+	req := test.APICall{
+		URL:    test.Host + "/orders?ids=1,2",
+		Method: "DELETE",
+	}
+	res := req.Fire()
+	defer res.Body.Close()
+
+	require.Equal(t, 200, res.StatusCode)
+	test.PrintBody(t, res)
+
+	after, err := test.Ventures().Get(pruned.ID)
+	require.Nil(t, err)
+	require.NotNil(t, after)
+	assert.Equal(t, "3", after.Orders)
+	assert.True(t, pruned.LastModified <= after.LastModified)
+
+	unchanged, err := test.Ventures().Get(untouched.ID)
+	require.Nil(t, err)
+	assert.Equal(t, untouched, unchanged)
+}
+
+func TestDELETE_Orders_3(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Orders already exist on the server
+		And several Ventures reference a shared Order alongside others
+		When the shared Order is deleted
+		Ensure the response code is 200
+		And only the deleted Order is pruned from each Venture
+		And each Venture keeps its other Orders
+	`)
+
+	otest.SetupTest()
+	defer otest.TearDown()
+
+	first := otest.InjectVenture(ventures.NewVenture{
+		Description: "Arm the knight",
+		State:       "In progress",
+		Orders:      "1,2",
+	})
+	second := otest.InjectVenture(ventures.NewVenture{
+		Description: "Shield the knight",
+		State:       "Not started",
+		Orders:      "2,3",
+	})
+
+	req := test.APICall{
+		URL:    test.Host + "/orders?ids=2",
+		Method: "DELETE",
+	}
+	res := req.Fire()
+	defer res.Body.Close()
+
+	require.Equal(t, 200, res.StatusCode)
+	test.PrintBody(t, res)
+
+	vens, err := test.Ventures().List([]string{first.ID, second.ID})
+	require.Nil(t, err)
+	require.Len(t, vens, 2)
+	assert.Equal(t, "1", vens[0].Orders)
+	assert.Equal(t, "3", vens[1].Orders)
+}
+
+func TestDELETE_Orders_4(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Orders already exist on the server
+		And each has some Batches
+		When one of the Orders is deleted
+		Ensure the response code is 200
+		And the Batches of the deleted Order are no longer among the living
+		And the Batches of the other Orders are unchanged
+	`)
+
+	otest.SetupTest()
+	defer otest.TearDown()
+
+	killed := btest.Inject(batches.NewBatch{
+		OrderID:     "1",
+		Description: "Smelt the ore",
+		State:       "Finished",
+	})
+	kept := btest.Inject(batches.NewBatch{
+		OrderID:     "2",
+		Description: "Cut the boards",
+		State:       "Not started",
+	})
+
+	req := test.APICall{
+		URL:    test.Host + "/orders?ids=1",
+		Method: "DELETE",
+	}
+	res := req.Fire()
+	defer res.Body.Close()
+
+	require.Equal(t, 200, res.StatusCode)
+	test.PrintBody(t, res)
+
+	assert.Empty(t, btest.DBQueryMany(killed.ID))
+	assert.Equal(t, []batches.Batch{*kept}, btest.DBQueryMany(kept.ID))
+}
+
+func TestDELETE_Orders_5(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Orders already exist on the server
+		When a delete is requested without any IDs or with IDs that aren't
+		integers
+		Ensure the response code is 400
+		And the error lists a violation of 'ids'
+		And no Orders have been deleted
+	`)
+
+	otest.SetupTest()
+	defer otest.TearDown()
+
+	before := otest.DBQueryAll()
+
+	for _, c := range []struct {
+		query string
+		code  string
+	}{
+		{"", "required"},
+		{"?ids=abc", "invalid"},
+	} {
+		req := test.APICall{
+			URL:    test.Host + "/orders" + c.query,
+			Method: "DELETE",
+		}
+		res := req.Fire()
+		defer res.Body.Close()
+
+		require.Equal(t, 400, res.StatusCode, "Query: %s", c.query)
+		p := test.AssertErrorBody(t, test.PrintBody(t, res))
+		require.Len(t, p.Errors, 1, "Query: %s", c.query)
+		assert.Equal(t, "ids", p.Errors[0].Field, "Query: %s", c.query)
+		assert.Equal(t, c.code, p.Errors[0].Code, "Query: %s", c.query)
+	}
+
+	assert.Equal(t, before, otest.DBQueryAll())
+}
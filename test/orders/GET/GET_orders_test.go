@@ -0,0 +1,130 @@
+package GET
+
+import (
+	"testing"
+
+	"github.com/PaulioRandall/go-cookies/toastify"
+	"github.com/PaulioRandall/go-qlueless-api/api/orders"
+	"github.com/PaulioRandall/go-qlueless-api/test"
+	otest "github.com/PaulioRandall/go-qlueless-api/test/orders"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	test.SetWorkingDir("../../../bin")
+}
+
+// ****************************************************************************
+// (GET) /orders
+// ****************************************************************************
+
+func TestGET_Orders_1(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Orders already exist on the server
+		When all Orders are requested
+		Ensure the response code is 200
+		And header includes:
+			Content-Type:                   'application/json; charset=utf-8'
+			Access-Control-Allow-Origin:    '*'
+			Access-Control-Allow-Headers:   '*'
+			Access-Control-Allow-Methods:   'GET, POST, PUT, DELETE, OPTIONS'
+		And the body is a JSON array containing all injected Orders
+	`)
+
+	otest.SetupEmptyTest()
+	defer otest.TearDown()
+
+	injected := otest.InjectAll([]orders.NewOrder{
+		orders.NewOrder{
+			Description: "Forge the sword",
+			State:       "Not started",
+		},
+		orders.NewOrder{
+			Description: "Sharpen the sword",
+			State:       "In progress",
+		},
+	})
+
+	req := test.APICall{
+		URL:    test.Host + "/orders",
+		Method: "GET",
+	}
+	res := req.Fire()
+	defer res.Body.Close()
+
+	require.Equal(t, 200, res.StatusCode)
+	toastify.AssertHeaderEqual(t, "Content-Type", res.Header, "application/json; charset=utf-8")
+	toastify.AssertHeaderEqual(t, "Access-Control-Allow-Origin", res.Header, "*")
+	toastify.AssertHeaderEqual(t, "Access-Control-Allow-Headers", res.Header, "*")
+	toastify.AssertHeaderEqual(t, "Access-Control-Allow-Methods", res.Header, "GET, POST, PUT, DELETE, OPTIONS")
+
+	body := test.PrintBody(t, res)
+
+	result := orders.RequireSliceOfOrders(t, body)
+	stored, err := orders.QueryAll(test.DB())
+	require.Nil(t, err)
+
+	orders.AssertOrdersEqual(t, injected, result, true)
+	orders.AssertOrdersEqual(t, stored, result, true)
+}
+
+// ****************************************************************************
+// (GET) /orders?ids={ids}
+// ****************************************************************************
+
+func TestGET_Orders_2(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Orders already exist on the server
+		When existent and non-existent Orders are requested
+		Ensure the response code is 200
+		And the body is a JSON array containing only the living Orders requested
+	`)
+
+	otest.SetupTest()
+	defer otest.TearDown()
+
+	req := test.APICall{
+		URL:    test.Host + "/orders?ids=1,88888,3",
+		Method: "GET",
+	}
+	res := req.Fire()
+	defer res.Body.Close()
+
+	require.Equal(t, 200, res.StatusCode)
+
+	body := test.PrintBody(t, res)
+
+	result := orders.RequireSliceOfOrders(t, body)
+	require.Len(t, result, 2)
+	orders.AssertOrdersEqual(t, otest.DBQueryMany("1,3"), result, true)
+}
+
+func TestGET_Orders_3(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Orders already exist on the server
+		When Orders are requested with IDs that aren't integers
+		Ensure the response code is 400
+		And the body is a JSON object representing an error response
+		And the error lists a violation of 'ids'
+	`)
+
+	otest.SetupTest()
+	defer otest.TearDown()
+
+	req := test.APICall{
+		URL:    test.Host + "/orders?ids=abc",
+		Method: "GET",
+	}
+	res := req.Fire()
+	defer res.Body.Close()
+
+	require.Equal(t, 400, res.StatusCode)
+	p := test.AssertErrorBody(t, test.PrintBody(t, res))
+	require.Len(t, p.Errors, 1)
+	assert.Equal(t, "ids", p.Errors[0].Field)
+	assert.Equal(t, "invalid", p.Errors[0].Code)
+}
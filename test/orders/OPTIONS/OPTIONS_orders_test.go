@@ -0,0 +1,70 @@
+package OPTIONS
+
+import (
+	"testing"
+
+	"github.com/PaulioRandall/go-qlueless-api/test"
+	otest "github.com/PaulioRandall/go-qlueless-api/test/orders"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	test.SetWorkingDir("../../../bin")
+}
+
+// ****************************************************************************
+// (OPTIONS) /orders
+// ****************************************************************************
+
+func TestOPTIONS_Orders(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Orders already exist on the server
+		When /orders OPTIONS are requested
+		Ensure the response code is 200
+		And header includes:
+			Access-Control-Allow-Origin:    '*'
+			Access-Control-Allow-Headers:   '*'
+			Access-Control-Allow-Methods:   'GET, POST, PUT, DELETE, OPTIONS'
+		And there is NO response body
+	`)
+
+	otest.SetupTest()
+	defer otest.TearDown()
+
+	req := test.APICall{
+		URL:    test.Host + "/orders",
+		Method: "OPTIONS",
+	}
+	res := req.Fire()
+	defer res.Body.Close()
+
+	require.Equal(t, 200, res.StatusCode)
+	test.AssertCorsHeaders(t, res, "GET, POST, PUT, DELETE, OPTIONS")
+	test.AssertEmptyBody(t, res.Body)
+}
+
+// ****************************************************************************
+// (?) /orders
+// ****************************************************************************
+
+func TestINVALID_Orders(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Orders already exist on the server
+		When /orders is called using invalid methods
+		Ensure the response code is 405
+		And there is NO response body
+	`)
+
+	otest.SetupTest()
+	defer otest.TearDown()
+
+	test.VerifyBadMethods(t, test.Host+"/orders", "GET, POST, PUT, DELETE, OPTIONS", []string{
+		"HEAD",
+		"CONNECT",
+		"TRACE",
+		"PATCH",
+		"CUSTOM",
+	})
+}
@@ -0,0 +1,157 @@
+package POST
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/PaulioRandall/go-qlueless-api/api/orders"
+	"github.com/PaulioRandall/go-qlueless-api/test"
+	otest "github.com/PaulioRandall/go-qlueless-api/test/orders"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	test.SetWorkingDir("../../../bin")
+}
+
+// ****************************************************************************
+// (POST) /orders
+// ****************************************************************************
+
+func TestPOST_Orders_1(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Orders already exist on the server
+		When a new valid Order is POSTed
+		Ensure the response code is 201
+		And the body is a JSON object representing the living input Order
+		And that Order will have a new, unused, ID
+		And that Order will have a new 'last_modified' datetime
+	`)
+
+	otest.SetupTest()
+	defer otest.TearDown()
+
+	input := orders.NewOrder{
+		Description: "Polish the sword",
+		State:       "Not started",
+		Extra:       "cloth: silk",
+	}
+	buf := new(bytes.Buffer)
+	json.NewEncoder(buf).Encode(&input)
+
+	req := test.APICall{
+		URL:    test.Host + "/orders",
+		Method: "POST",
+		Body:   buf,
+	}
+	res := req.Fire()
+	defer res.Body.Close()
+
+	require.Equal(t, 201, res.StatusCode)
+	test.AssertDefaultHeaders(t, res, "application/json", "GET, POST, PUT, DELETE, OPTIONS")
+
+	body := test.PrintBody(t, res)
+
+	result := orders.RequireOrder(t, body)
+	assert.Equal(t, "4", result.ID)
+	assert.Equal(t, input.Description, result.Description)
+	assert.Equal(t, input.State, result.State)
+	assert.Equal(t, input.Extra, result.Extra)
+	assert.Equal(t, otest.DBQueryOne(result.ID), result)
+}
+
+func TestPOST_Orders_2(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Orders already exist on the server
+		When a new but invalid Order is POSTed
+		Ensure the response code is 400
+		And the body is a JSON object representing an error response
+	`)
+
+	otest.SetupTest()
+	defer otest.TearDown()
+
+	input := orders.NewOrder{}
+	buf := new(bytes.Buffer)
+	json.NewEncoder(buf).Encode(&input)
+
+	req := test.APICall{
+		URL:    test.Host + "/orders",
+		Method: "POST",
+		Body:   buf,
+	}
+	res := req.Fire()
+	defer res.Body.Close()
+
+	require.Equal(t, 400, res.StatusCode)
+	test.AssertDefaultHeaders(t, res, "application/problem+json", "GET, POST, PUT, DELETE, OPTIONS")
+	test.AssertErrorBody(t, test.PrintBody(t, res))
+}
+
+func TestPOST_Orders_3(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given no Orders exist on the server
+		When many new Orders are POSTed concurrently
+		Ensure the response code of each is 201
+		And every Order created is assigned a unique ID
+		And every Order created exists within the database
+	`)
+
+	otest.SetupEmptyTest()
+	defer otest.TearDown()
+
+	const n = 20
+	ids := make(chan string, n)
+	wg := sync.WaitGroup{}
+
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			input := orders.NewOrder{
+				Description: fmt.Sprintf("Order %d", i),
+				State:       "Not started",
+			}
+			buf := new(bytes.Buffer)
+			json.NewEncoder(buf).Encode(&input)
+
+			req := test.APICall{
+				URL:    test.Host + "/orders",
+				Method: "POST",
+				Body:   buf,
+			}
+			res := req.Fire()
+			defer res.Body.Close()
+
+			if !assert.Equal(t, 201, res.StatusCode) {
+				return
+			}
+
+			o := orders.Order{}
+			err := json.NewDecoder(res.Body).Decode(&o)
+			if assert.Nil(t, err) {
+				ids <- o.ID
+			}
+		}(i)
+	}
+
+	wg.Wait()
+	close(ids)
+
+	unique := map[string]bool{}
+	for id := range ids {
+		assert.False(t, unique[id], "Order ID '%s' was assigned more than once", id)
+		unique[id] = true
+	}
+
+	assert.Len(t, unique, n)
+	assert.Len(t, otest.DBQueryAll(), n)
+}
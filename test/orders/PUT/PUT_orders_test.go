@@ -0,0 +1,148 @@
+package PUT
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/PaulioRandall/go-qlueless-api/api/orders"
+	"github.com/PaulioRandall/go-qlueless-api/test"
+	otest "github.com/PaulioRandall/go-qlueless-api/test/orders"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	test.SetWorkingDir("../../../bin")
+}
+
+// ****************************************************************************
+// (PUT) /orders
+// ****************************************************************************
+
+func TestPUT_Orders_1(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Orders already exist on the server
+		When existing Orders are modified and PUT to the server
+		Ensure the response code is 200
+		And the body is a JSON array containing all updated Orders
+		And only the properties specified have been modified
+	`)
+
+	otest.SetupTest()
+	defer otest.TearDown()
+
+	before := otest.DBQueryOne("1")
+
+	input := orders.ModOrder{
+		IDs:   "1, 2",
+		Props: "state, extra",
+		Values: orders.Order{
+			State: "Finished",
+			Extra: "metal: mithril",
+		},
+	}
+	buf := new(bytes.Buffer)
+	json.NewEncoder(buf).Encode(&input)
+
+	req := test.APICall{
+		URL:    test.Host + "/orders",
+		Method: "PUT",
+		Body:   buf,
+	}
+	res := req.Fire()
+	defer res.Body.Close()
+
+	require.Equal(t, 200, res.StatusCode)
+	test.AssertDefaultHeaders(t, res, "application/json", "GET, POST, PUT, DELETE, OPTIONS")
+
+	body := test.PrintBody(t, res)
+
+	result := orders.RequireSliceOfOrders(t, body)
+	require.Len(t, result, 2)
+
+	after := otest.DBQueryOne("1")
+	assert.Equal(t, before.Description, after.Description)
+	assert.Equal(t, "Finished", after.State)
+	assert.Equal(t, "metal: mithril", after.Extra)
+}
+
+func TestPUT_Orders_2(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Orders already exist on the server
+		When an invalid Order modification is PUT to the server
+		Ensure the response code is 400
+		And the body is a JSON object representing an error response
+	`)
+
+	otest.SetupTest()
+	defer otest.TearDown()
+
+	input := orders.ModOrder{
+		IDs:    "1",
+		Props:  "description, id",
+		Values: orders.Order{},
+	}
+	buf := new(bytes.Buffer)
+	json.NewEncoder(buf).Encode(&input)
+
+	req := test.APICall{
+		URL:    test.Host + "/orders",
+		Method: "PUT",
+		Body:   buf,
+	}
+	res := req.Fire()
+	defer res.Body.Close()
+
+	require.Equal(t, 400, res.StatusCode)
+	test.AssertDefaultHeaders(t, res, "application/problem+json", "GET, POST, PUT, DELETE, OPTIONS")
+	test.AssertErrorBody(t, test.PrintBody(t, res))
+}
+
+func TestPUT_Orders_3(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Orders already exist on the server
+		When an Order is modified by many PUTs in quick succession
+		Ensure the response code of each is 200
+		And the last modified time of each revision is later than the previous
+		And the last modified time within each response matches the one stored
+	`)
+
+	otest.SetupTest()
+	defer otest.TearDown()
+
+	prev := otest.DBQueryOne("1").LastModified
+
+	for i := 0; i < 20; i++ {
+		input := orders.ModOrder{
+			IDs:   "1",
+			Props: "extra",
+			Values: orders.Order{
+				Extra: fmt.Sprintf("revision: %d", i),
+			},
+		}
+		buf := new(bytes.Buffer)
+		json.NewEncoder(buf).Encode(&input)
+
+		req := test.APICall{
+			URL:    test.Host + "/orders",
+			Method: "PUT",
+			Body:   buf,
+		}
+		res := req.Fire()
+		defer res.Body.Close()
+
+		require.Equal(t, 200, res.StatusCode, "PUT %d", i)
+		result := orders.RequireSliceOfOrders(t, test.PrintBody(t, res))
+		require.Len(t, result, 1)
+
+		after := otest.DBQueryOne("1")
+		assert.Equal(t, after.LastModified, result[0].LastModified, "PUT %d", i)
+		assert.True(t, after.LastModified > prev, "PUT %d", i)
+		prev = after.LastModified
+	}
+}
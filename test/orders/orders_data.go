@@ -0,0 +1,150 @@
+package orders
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/PaulioRandall/go-qlueless-api/api/config"
+	"github.com/PaulioRandall/go-qlueless-api/api/orders"
+	"github.com/PaulioRandall/go-qlueless-api/api/ventures"
+	"github.com/PaulioRandall/go-qlueless-api/test"
+)
+
+// SetupEmptyTest is run at the start of a test to setup the server but does
+// not inject any test data.
+func SetupEmptyTest() {
+	test.StartServer(config.Default(), nil)
+}
+
+// SetupTest is run at the start of every test to setup the server and inject
+// the test data.
+func SetupTest() {
+	test.StartServer(config.Default(), func() {
+		DBInjectLiving()
+	})
+}
+
+// TearDown should be deferred straight after SetupTest() is run to close
+// resources at the end of every test.
+func TearDown() {
+	test.StopServer()
+}
+
+// InjectAll injects a slice of Orders into the database.
+func InjectAll(new []orders.NewOrder) []orders.Order {
+	result := make([]orders.Order, len(new))
+	for i, o := range new {
+		result[i] = *Inject(o)
+	}
+	return result
+}
+
+// Inject injects an Order into the database.
+func Inject(new orders.NewOrder) *orders.Order {
+	o, ok := new.Insert(test.DB())
+	if !ok {
+		panic("Already printed above!")
+	}
+	return o
+}
+
+// DBInjectLiving injects a default set of living Orders into the database
+func DBInjectLiving() {
+	Inject(orders.NewOrder{
+		Description: "Forge the sword",
+		State:       "Not started",
+		Extra:       "metal: steel",
+	})
+	Inject(orders.NewOrder{
+		Description: "Sharpen the sword",
+		State:       "In progress",
+	})
+	Inject(orders.NewOrder{
+		Description: "Sheath the sword",
+		State:       "Finished",
+	})
+}
+
+// InjectVenture injects a Venture into the database.
+func InjectVenture(new ventures.NewVenture) *ventures.Venture {
+	ven, err := test.Ventures().Create(&new)
+	if err != nil {
+		panic(err)
+	}
+	return ven
+}
+
+// DBQueryAll queries the database for all living Orders
+func DBQueryAll() []orders.Order {
+	rows, err := test.DB().Query(`
+		SELECT id, last_modified, description, state, extra
+		FROM ql_order
+		ORDER BY id ASC
+	`)
+
+	if rows != nil {
+		defer rows.Close()
+	}
+
+	if err != nil {
+		panic(err)
+	}
+
+	return mapRows(rows)
+}
+
+// DBQueryMany queries the database for Orders with the specified IDs
+func DBQueryMany(ids string) []orders.Order {
+	rows, err := test.DB().Query(fmt.Sprintf(`
+		SELECT id, last_modified, description, state, extra
+		FROM ql_order
+		WHERE id IN (%s)
+		ORDER BY id ASC`, ids))
+
+	if rows != nil {
+		defer rows.Close()
+	}
+
+	if err != nil {
+		panic(err)
+	}
+
+	return mapRows(rows)
+}
+
+// DBQueryOne queries the database for a specific Order
+func DBQueryOne(id string) orders.Order {
+	ords := DBQueryMany(id)
+	if len(ords) != 1 {
+		panic("Expected a single order from query")
+	}
+	return ords[0]
+}
+
+// mapRows is a file private function that maps rows from a database query into
+// a slice of Orders.
+func mapRows(rows *sql.Rows) []orders.Order {
+	ords := []orders.Order{}
+
+	for rows.Next() {
+		ords = append(ords, *mapRow(rows))
+	}
+
+	return ords
+}
+
+// mapRow is a file private function that maps a single row from a database
+// query into an Order.
+func mapRow(rows *sql.Rows) *orders.Order {
+	o := orders.Order{}
+	err := rows.Scan(&o.ID,
+		&o.LastModified,
+		&o.Description,
+		&o.State,
+		&o.Extra)
+
+	if err != nil {
+		panic(err)
+	}
+	return &o
+}
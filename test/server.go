@@ -0,0 +1,155 @@
+package test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/PaulioRandall/go-qlueless-api/api/config"
+	"github.com/PaulioRandall/go-qlueless-api/api/database"
+	"github.com/PaulioRandall/go-qlueless-api/api/server"
+	"github.com/PaulioRandall/go-qlueless-api/api/ventures"
+	"github.com/PaulioRandall/go-qlueless-api/api/workflow"
+)
+
+// PostgresEnv is the environment variable that, when set to the DSN of a local
+// PostgreSQL database, has tests run against PostgreSQL instead of SQLite.
+// Each test process works within its own schema which is dropped afterwards.
+const PostgresEnv = "QLUELESS_TEST_POSTGRES"
+
+// Host is the scheme and host of the server under test. It is set each time a
+// server is started.
+var Host string = ""
+
+var srv *server.Server = nil
+var dsn string = ""
+
+// StartServer starts a new server, using the configuration 'cfg' with a new
+// empty database, on a port chosen by the OS. 'setup' is called before the
+// server starts so tables may be created and test data injected.
+func StartServer(cfg config.Config, setup func()) {
+	var err error
+
+	switch pg := os.Getenv(PostgresEnv); pg {
+	case "":
+		dsn = newSQLiteDatabase()
+	default:
+		dsn = newPostgresSchema(pg)
+	}
+
+	cfg.DatabaseDSN = dsn
+	cfg.Addr = "localhost:0"
+
+	srv, err = server.New(cfg)
+	if err != nil {
+		log.Panic(err)
+	}
+
+	if setup != nil {
+		setup()
+	}
+
+	err = srv.Start()
+	if err != nil {
+		log.Panic(err)
+	}
+
+	Host = "http://" + srv.Addr()
+}
+
+// StopServer should be deferred straight after StartServer() is called to
+// shutdown the server and delete its database.
+func StopServer() {
+	err := srv.Shutdown(context.Background())
+	if err != nil {
+		log.Panic(err)
+	}
+
+	switch Dialect() {
+	case database.Postgres:
+		dropPostgresSchema(os.Getenv(PostgresEnv))
+	default:
+		err = os.Remove(dsn)
+		if err != nil && !os.IsNotExist(err) {
+			log.Panic(err)
+		}
+	}
+
+	srv = nil
+}
+
+// DB returns the database of the server under test.
+func DB() *sql.DB {
+	return srv.DB()
+}
+
+// Workflow returns the workflow of the server under test.
+func Workflow() *workflow.Workflow {
+	return srv.Workflow()
+}
+
+// Ventures returns the VentureStore of the server under test.
+func Ventures() ventures.VentureStore {
+	return srv.Ventures()
+}
+
+// Dialect returns the SQL dialect of the database of the server under test.
+func Dialect() database.Dialect {
+	return database.DialectOf(dsn)
+}
+
+// newSQLiteDatabase is a file private function that creates a new empty SQLite
+// database returning its path.
+func newSQLiteDatabase() string {
+	f, err := ioutil.TempFile("", "qlueless-*.db")
+	if err != nil {
+		log.Panic(err)
+	}
+	f.Close()
+	return f.Name()
+}
+
+// newPostgresSchema is a file private function that creates a new empty schema
+// for this test process within the PostgreSQL database at 'pg' returning a DSN
+// that uses it.
+func newPostgresSchema(pg string) string {
+	dropPostgresSchema(pg)
+	execPostgres(pg, "CREATE SCHEMA "+postgresSchema())
+
+	sep := "?"
+	if strings.Contains(pg, "?") {
+		sep = "&"
+	}
+	return pg + sep + "search_path=" + postgresSchema()
+}
+
+// dropPostgresSchema is a file private function that drops the schema of this
+// test process from the PostgreSQL database at 'pg'.
+func dropPostgresSchema(pg string) {
+	execPostgres(pg, "DROP SCHEMA IF EXISTS "+postgresSchema()+" CASCADE")
+}
+
+// postgresSchema is a file private function that returns the name of the
+// PostgreSQL schema used by this test process.
+func postgresSchema() string {
+	return fmt.Sprintf("qlueless_test_%d", os.Getpid())
+}
+
+// execPostgres is a file private function that executes the statement 's'
+// against the PostgreSQL database at 'pg'.
+func execPostgres(pg string, s string) {
+	db, err := database.Open(pg)
+	if err != nil {
+		log.Panic(err)
+	}
+	defer db.Close()
+
+	_, err = db.Exec(s)
+	if err != nil {
+		log.Panic(err)
+	}
+}
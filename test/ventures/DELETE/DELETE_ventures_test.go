@@ -0,0 +1,200 @@
+package DELETE
+
+import (
+	"testing"
+
+	"github.com/PaulioRandall/go-cookies/toastify"
+	"github.com/PaulioRandall/go-qlueless-api/api/ventures"
+	"github.com/PaulioRandall/go-qlueless-api/test"
+	vtest "github.com/PaulioRandall/go-qlueless-api/test/ventures"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	test.SetWorkingDir("../../../bin")
+}
+
+// ****************************************************************************
+// (DELETE) /ventures?ids={ids}
+// ****************************************************************************
+
+func TestDELETE_Ventures_1(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Ventures already exist on the server
+		When existing Ventures are deleted
+		Ensure the response code is 200
+		And header includes:
+			Content-Type:                   'application/json; charset=utf-8'
+			Access-Control-Allow-Origin:    '*'
+			Access-Control-Allow-Headers:   '*'
+			Access-Control-Allow-Methods:   'GET, POST, PUT, DELETE, OPTIONS'
+		And the body is a JSON array containing the deleted Ventures
+		And those Ventures are marked as dead
+		And those Ventures are no longer among the living
+	`)
+
+	vtest.SetupEmptyTest()
+	defer vtest.TearDown()
+
+	injected := vtest.InjectAll([]ventures.NewVenture{
+		ventures.NewVenture{
+			Description: "White wizard",
+			State:       "Not started",
+		},
+		ventures.NewVenture{
+			Description: "Green lizard",
+			State:       "In progress",
+		},
+		ventures.NewVenture{
+			Description: "Pink gizzard",
+			State:       "Finished",
+		},
+	})
+
+	req := test.APICall{
+		URL:    test.Host + "/ventures?ids=1,3",
+		Method: "DELETE",
+	}
+	res := req.Fire()
+	defer res.Body.Close()
+
+	require.Equal(t, 200, res.StatusCode)
+	toastify.AssertHeaderEqual(t, "Content-Type", res.Header, "application/json; charset=utf-8")
+	toastify.AssertHeaderEqual(t, "Access-Control-Allow-Origin", res.Header, "*")
+	toastify.AssertHeaderEqual(t, "Access-Control-Allow-Headers", res.Header, "*")
+	toastify.AssertHeaderEqual(t, "Access-Control-Allow-Methods", res.Header, "GET, POST, PUT, DELETE, OPTIONS")
+
+	body := test.PrintBody(t, res)
+
+	result := ventures.RequireSliceOfVentures(t, body)
+	require.Len(t, result, 2)
+	for _, ven := range result {
+		assert.True(t, ven.Dead, "Venture.Dead")
+	}
+
+	stored, err := vtest.Store().List(nil)
+	require.Nil(t, err)
+	ventures.AssertVenturesEqual(t, injected[1:2], stored, true)
+}
+
+func TestDELETE_Ventures_2(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Ventures already exist on the server
+		When existing and non-existent Ventures are deleted
+		And the 'wrap' query parameter has been specified
+		Ensure the response code is 200
+		And the wrapped data is a JSON array containing only the deleted Ventures
+		And the wrapped message includes the IDs that could not be found
+	`)
+
+	vtest.SetupTest()
+	defer vtest.TearDown()
+
+	req := test.APICall{
+		URL:    test.Host + "/ventures?wrap&ids=2,88888",
+		Method: "DELETE",
+	}
+	res := req.Fire()
+	defer res.Body.Close()
+
+	require.Equal(t, 200, res.StatusCode)
+
+	body := test.PrintBody(t, res)
+
+	wr, result := ventures.AssertWrappedVentureSliceFromReader(t, body)
+	require.Len(t, result, 1)
+	assert.Equal(t, "2", result[0].ID)
+	assert.True(t, result[0].Dead, "Venture.Dead")
+	assert.Contains(t, wr.Message, "88888")
+
+	assert.Empty(t, vtest.DBQueryMany("2"))
+}
+
+func TestDELETE_Ventures_3(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Ventures already exist on the server
+		When a delete is requested without any IDs
+		Ensure the response code is 400
+		And the body is a JSON object representing an error response
+		And the error lists a violation of 'ids'
+		And no Ventures have been deleted
+	`)
+
+	vtest.SetupTest()
+	defer vtest.TearDown()
+
+	before := vtest.DBQueryAll()
+
+	req := test.APICall{
+		URL:    test.Host + "/ventures",
+		Method: "DELETE",
+	}
+	res := req.Fire()
+	defer res.Body.Close()
+
+	require.Equal(t, 400, res.StatusCode)
+	test.AssertDefaultHeaders(t, res, "application/problem+json", "GET, POST, PUT, DELETE, OPTIONS")
+	p := test.AssertErrorBody(t, test.PrintBody(t, res))
+	require.Len(t, p.Errors, 1)
+	assert.Equal(t, "ids", p.Errors[0].Field)
+	assert.Equal(t, "required", p.Errors[0].Code)
+
+	ventures.AssertVenturesEqual(t, before, vtest.DBQueryAll(), true)
+}
+
+// ****************************************************************************
+// (DELETE) /ventures/{id}
+// ****************************************************************************
+
+func TestDELETE_Venture_1(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Ventures already exist on the server
+		When a single Venture is deleted by its ID
+		Ensure the response code is 200
+		And the body is the deleted Venture marked as dead
+		And the Venture is no longer among the living
+		And deleting it again gives a response code of 404
+	`)
+
+	vtest.SetupEmptyTest()
+	defer vtest.TearDown()
+
+	injected := vtest.InjectAll([]ventures.NewVenture{
+		ventures.NewVenture{
+			Description: "White wizard",
+			State:       "Not started",
+		},
+		ventures.NewVenture{
+			Description: "Green lizard",
+			State:       "In progress",
+		},
+	})
+
+	req := test.APICall{
+		URL:    test.Host + "/ventures/" + injected[0].ID,
+		Method: "DELETE",
+	}
+	res := req.Fire()
+	defer res.Body.Close()
+
+	require.Equal(t, 200, res.StatusCode)
+	test.AssertDefaultHeaders(t, res, "application/json", "GET, PATCH, DELETE, OPTIONS")
+
+	out := ventures.AssertVentureFromReader(t, test.PrintBody(t, res))
+	assert.Equal(t, injected[0].ID, out.ID)
+	assert.True(t, out.Dead, "Venture.Dead")
+
+	assert.Empty(t, vtest.DBQueryMany(injected[0].ID))
+	assert.Len(t, vtest.DBQueryMany(injected[1].ID), 1)
+
+	res = req.Fire()
+	defer res.Body.Close()
+
+	require.Equal(t, 404, res.StatusCode)
+	test.AssertErrorBody(t, test.PrintBody(t, res))
+}
@@ -1,12 +1,20 @@
 package GET
 
 import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/PaulioRandall/go-cookies/cookies"
 	"github.com/PaulioRandall/go-cookies/toastify"
+	"github.com/PaulioRandall/go-qlueless-api/api/orders"
 	"github.com/PaulioRandall/go-qlueless-api/api/ventures"
 	"github.com/PaulioRandall/go-qlueless-api/test"
 	vtest "github.com/PaulioRandall/go-qlueless-api/test/ventures"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -51,7 +59,7 @@ func TestGET_Ventures_1(t *testing.T) {
 	})
 
 	req := test.APICall{
-		URL:    "http://localhost:8080/ventures",
+		URL:    test.Host + "/ventures",
 		Method: "GET",
 	}
 	res := req.Fire()
@@ -66,7 +74,7 @@ func TestGET_Ventures_1(t *testing.T) {
 	body := test.PrintBody(t, res)
 
 	result := ventures.RequireSliceOfVentures(t, body)
-	stored, err := ventures.QueryAll()
+	stored, err := vtest.Store().List(nil)
 	require.Nil(t, err)
 
 	ventures.AssertVenturesEqual(t, injected, result, true)
@@ -96,7 +104,7 @@ func TestGET_Ventures_2(t *testing.T) {
 	defer vtest.TearDown()
 
 	req := test.APICall{
-		URL:    "http://localhost:8080/ventures?wrap",
+		URL:    test.Host + "/ventures?wrap",
 		Method: "GET",
 	}
 	res := req.Fire()
@@ -132,7 +140,7 @@ func TestGET_Ventures_3(t *testing.T) {
 	defer vtest.TearDown()
 
 	req := test.APICall{
-		URL:    "http://localhost:8080/ventures?ids=1",
+		URL:    test.Host + "/ventures?ids=1",
 		Method: "GET",
 	}
 	res := req.Fire()
@@ -166,7 +174,7 @@ func TestGET_Ventures_4(t *testing.T) {
 	defer vtest.TearDown()
 
 	req := test.APICall{
-		URL:    "http://localhost:8080/ventures?ids=1,2,3",
+		URL:    test.Host + "/ventures?ids=1,2,3",
 		Method: "GET",
 	}
 	res := req.Fire()
@@ -200,7 +208,7 @@ func TestGET_Ventures_5(t *testing.T) {
 	defer vtest.TearDown()
 
 	req := test.APICall{
-		URL:    "http://localhost:8080/ventures?ids=888888,999999",
+		URL:    test.Host + "/ventures?ids=888888,999999",
 		Method: "GET",
 	}
 	res := req.Fire()
@@ -231,7 +239,7 @@ func TestGET_Ventures_6(t *testing.T) {
 	defer vtest.TearDown()
 
 	req := test.APICall{
-		URL:    "http://localhost:8080/ventures?ids=1,88888,2,99999",
+		URL:    test.Host + "/ventures?ids=1,88888,2,99999",
 		Method: "GET",
 	}
 	res := req.Fire()
@@ -272,7 +280,7 @@ func TestGET_Ventures_7(t *testing.T) {
 	defer vtest.TearDown()
 
 	req := test.APICall{
-		URL:    "http://localhost:8080/ventures?wrap&ids=1,4,5",
+		URL:    test.Host + "/ventures?wrap&ids=1,4,5",
 		Method: "GET",
 	}
 	res := req.Fire()
@@ -289,3 +297,872 @@ func TestGET_Ventures_7(t *testing.T) {
 	exp := vtest.DBQueryMany("1,4,5")
 	ventures.AssertOrderlessSlicesEqual(t, exp, out)
 }
+
+// ****************************************************************************
+// (GET) /ventures?history&ids={ids}
+// ****************************************************************************
+
+func TestGET_Ventures_8(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given a Venture has been modified then killed
+		When the history of that Venture is requested
+		Ensure the response code is 200
+		And the body is a JSON array containing every revision of the Venture
+		And the revisions are ordered from oldest to newest
+		And the final revision is dead
+	`)
+
+	vtest.SetupEmptyTest()
+	defer vtest.TearDown()
+
+	revs := injectRevisions()
+
+	req := test.APICall{
+		URL:    test.Host + "/ventures?history&ids=1",
+		Method: "GET",
+	}
+	res := req.Fire()
+	defer res.Body.Close()
+
+	require.Equal(t, 200, res.StatusCode)
+	test.AssertDefaultHeaders(t, res, "application/json", "GET, POST, PUT, DELETE, OPTIONS")
+
+	body := test.PrintBody(t, res)
+
+	result := ventures.RequireSliceOfVentures(t, body)
+	require.Len(t, result, 3)
+
+	for i, ven := range result {
+		assert.Equal(t, revs[i].Description, ven.Description)
+		assert.Equal(t, revs[i].State, ven.State)
+		assert.Equal(t, revs[i].Dead, ven.Dead)
+	}
+
+	assert.True(t, result[0].LastModified < result[1].LastModified)
+	assert.True(t, result[1].LastModified < result[2].LastModified)
+}
+
+func TestGET_Ventures_9(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given a Venture has been modified then killed
+		When the history of that Venture is requested between two times
+		Ensure the response code is 200
+		And the body is a JSON array containing only the revisions made
+		between those times
+	`)
+
+	vtest.SetupEmptyTest()
+	defer vtest.TearDown()
+
+	injectRevisions()
+	all, err := vtest.Store().History([]string{"1"}, 0, math.MaxInt64)
+	require.Nil(t, err)
+	require.Len(t, all, 3)
+
+	req := test.APICall{
+		URL: fmt.Sprintf(test.Host+"/ventures?history&ids=1&since=%d&until=%d",
+			all[1].LastModified, all[1].LastModified),
+		Method: "GET",
+	}
+	res := req.Fire()
+	defer res.Body.Close()
+
+	require.Equal(t, 200, res.StatusCode)
+
+	body := test.PrintBody(t, res)
+
+	result := ventures.RequireSliceOfVentures(t, body)
+	require.Len(t, result, 1)
+	assert.Equal(t, all[1], result[0])
+}
+
+func TestGET_Ventures_10(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Ventures already exist on the server
+		When Venture history is requested without any IDs
+		Ensure the response code is 400
+		And the body is a JSON object representing an error response
+	`)
+
+	vtest.SetupTest()
+	defer vtest.TearDown()
+
+	req := test.APICall{
+		URL:    test.Host + "/ventures?history&since=abc",
+		Method: "GET",
+	}
+	res := req.Fire()
+	defer res.Body.Close()
+
+	require.Equal(t, 400, res.StatusCode)
+	test.AssertErrorBody(t, test.PrintBody(t, res))
+}
+
+// injectRevisions injects a Venture then pushes two further revisions of it,
+// the last killing it. The expected revisions are returned oldest first.
+func injectRevisions() []ventures.Venture {
+	ven := *vtest.Inject(ventures.NewVenture{
+		Description: "White wizard",
+		State:       "Not started",
+	})
+	revs := []ventures.Venture{ven}
+
+	for _, mod := range []ventures.ModVenture{
+		ventures.ModVenture{
+			Props:  "state",
+			Values: ventures.Venture{State: "In progress"},
+		},
+		ventures.ModVenture{
+			Props:  "dead",
+			Values: ventures.Venture{Dead: true},
+		},
+	} {
+		time.Sleep(2 * time.Millisecond)
+		mod.IDs = ven.ID
+		out, err := vtest.Store().Modify(&mod)
+		if err != nil {
+			panic(err)
+		}
+		ven = out[0]
+		revs = append(revs, ven)
+	}
+
+	return revs
+}
+
+// ****************************************************************************
+// (GET) /ventures?as_of={unix_millis}
+// ****************************************************************************
+
+func TestGET_Ventures_11(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given a Venture has been modified and another killed
+		When all Ventures are requested as of a time before those changes
+		Ensure the response code is 200
+		And the body is a JSON array containing the Ventures as they were at
+		that time, including the Venture that was later killed
+	`)
+
+	vtest.SetupEmptyTest()
+	defer vtest.TearDown()
+
+	before := vtest.InjectAll([]ventures.NewVenture{
+		ventures.NewVenture{
+			Description: "White wizard",
+			State:       "Not started",
+		},
+		ventures.NewVenture{
+			Description: "Green lizard",
+			State:       "In progress",
+		},
+	})
+
+	asOf := before[1].LastModified
+	time.Sleep(2 * time.Millisecond)
+
+	modVentures(t, ventures.ModVenture{
+		IDs:    "1",
+		Props:  "state",
+		Values: ventures.Venture{State: "Finished"},
+	})
+	modVentures(t, ventures.ModVenture{
+		IDs:    "2",
+		Props:  "dead",
+		Values: ventures.Venture{Dead: true},
+	})
+
+	req := test.APICall{
+		URL:    fmt.Sprintf(test.Host+"/ventures?as_of=%d", asOf),
+		Method: "GET",
+	}
+	res := req.Fire()
+	defer res.Body.Close()
+
+	require.Equal(t, 200, res.StatusCode)
+	test.AssertDefaultHeaders(t, res, "application/json", "GET, POST, PUT, DELETE, OPTIONS")
+
+	body := test.PrintBody(t, res)
+
+	result := ventures.RequireSliceOfVentures(t, body)
+	ventures.AssertVenturesEqual(t, before, result, true)
+}
+
+func TestGET_Ventures_12(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given a Venture has been modified and another killed
+		When specific Ventures are requested as of a time after those changes
+		Ensure the response code is 200
+		And the body is a JSON array containing the latest revision of the
+		living Ventures only
+	`)
+
+	vtest.SetupEmptyTest()
+	defer vtest.TearDown()
+
+	vtest.InjectAll([]ventures.NewVenture{
+		ventures.NewVenture{
+			Description: "White wizard",
+			State:       "Not started",
+		},
+		ventures.NewVenture{
+			Description: "Green lizard",
+			State:       "In progress",
+		},
+	})
+
+	time.Sleep(2 * time.Millisecond)
+
+	modVentures(t, ventures.ModVenture{
+		IDs:    "1",
+		Props:  "state",
+		Values: ventures.Venture{State: "Finished"},
+	})
+	modVentures(t, ventures.ModVenture{
+		IDs:    "2",
+		Props:  "dead",
+		Values: ventures.Venture{Dead: true},
+	})
+
+	asOf := cookies.ToUnixMilli(time.Now()) + 1000
+
+	req := test.APICall{
+		URL:    fmt.Sprintf(test.Host+"/ventures?ids=1,2&as_of=%d", asOf),
+		Method: "GET",
+	}
+	res := req.Fire()
+	defer res.Body.Close()
+
+	require.Equal(t, 200, res.StatusCode)
+
+	body := test.PrintBody(t, res)
+
+	result := ventures.RequireSliceOfVentures(t, body)
+	require.Len(t, result, 1)
+	assert.Equal(t, vtest.DBQueryOne("1"), result[0])
+	assert.Equal(t, "Finished", result[0].State)
+}
+
+// modVentures applies the modification 'mv' directly to the database.
+func modVentures(t *testing.T, mv ventures.ModVenture) {
+	_, err := vtest.Store().Modify(&mv)
+	require.Nil(t, err, "Expected modification to succeed")
+}
+
+// ****************************************************************************
+// (GET) /ventures (ETag)
+// ****************************************************************************
+
+func TestGET_Ventures_13(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Ventures already exist on the server
+		When Ventures are requested before and after one of them is modified
+		Ensure the 'ETag' header represents the Ventures returned
+		And the 'ETag' header changes once the Venture has been modified
+	`)
+
+	vtest.SetupTest()
+	defer vtest.TearDown()
+
+	getETag := func() string {
+		req := test.APICall{
+			URL:    test.Host + "/ventures?ids=1,2",
+			Method: "GET",
+		}
+		res := req.Fire()
+		defer res.Body.Close()
+
+		require.Equal(t, 200, res.StatusCode)
+		result := ventures.RequireSliceOfVentures(t, test.PrintBody(t, res))
+
+		etag := res.Header.Get("ETag")
+		assert.NotEmpty(t, etag)
+		assert.Equal(t, ventures.ETag(result), etag)
+		return etag
+	}
+
+	before := getETag()
+
+	time.Sleep(2 * time.Millisecond)
+	modVentures(t, ventures.ModVenture{
+		IDs:    "2",
+		Props:  "state",
+		Values: ventures.Venture{State: "Finished"},
+	})
+
+	assert.NotEqual(t, before, getETag())
+}
+
+// ****************************************************************************
+// (GET) /ventures?ids (unknown IDs)
+// ****************************************************************************
+
+func TestGET_Ventures_14(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Ventures already exist on the server
+		When existent and non-existent Ventures are requested with 'strict'
+		Ensure the response code is 404
+		And the message lists the IDs that could not be found
+	`)
+
+	vtest.SetupTest()
+	defer vtest.TearDown()
+
+	req := test.APICall{
+		URL:    test.Host + "/ventures?ids=1,888888,2,999999&strict",
+		Method: "GET",
+	}
+	res := req.Fire()
+	defer res.Body.Close()
+
+	require.Equal(t, 404, res.StatusCode)
+
+	wr := test.AssertErrorBody(t, test.PrintBody(t, res))
+	assert.Contains(t, wr.Detail, "'888888, 999999'")
+	assert.Len(t, wr.Errors, 2)
+}
+
+func TestGET_Ventures_15(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Ventures already exist on the server
+		When existent and non-existent Ventures are requested and wrapped
+		Ensure the response code is 200
+		And only the existing Ventures are returned
+		And the message lists the IDs that could not be found
+	`)
+
+	vtest.SetupTest()
+	defer vtest.TearDown()
+
+	req := test.APICall{
+		URL:    test.Host + "/ventures?ids=1,999999&wrap",
+		Method: "GET",
+	}
+	res := req.Fire()
+	defer res.Body.Close()
+
+	require.Equal(t, 200, res.StatusCode)
+
+	wr, out := ventures.AssertWrappedVentureSliceFromReader(t, test.PrintBody(t, res))
+	require.Len(t, out, 1)
+	assert.Contains(t, wr.Message, "'999999'")
+}
+
+// ****************************************************************************
+// (GET) /ventures?state&q&has_order&modified_since&modified_before&sort&fields
+// ****************************************************************************
+
+// injectSearchable injects a set of Ventures for filtering, sorting, and
+// searching returning them in the order they were created. Enough Orders are
+// injected first for every Order the Ventures reference to exist.
+func injectSearchable() []ventures.Venture {
+	for i := 0; i < 12; i++ {
+		vtest.InjectOrder(orders.NewOrder{
+			Description: fmt.Sprintf("Order %d", i),
+			State:       "Not started",
+		})
+	}
+
+	vens := []ventures.Venture{}
+	for _, nv := range []ventures.NewVenture{
+		ventures.NewVenture{
+			Description: "Rincewind",
+			State:       "Not started",
+			Orders:      "1,12",
+			Extra:       "Wizzard",
+		},
+		ventures.NewVenture{
+			Description: "The Luggage",
+			State:       "In progress",
+			Orders:      "2",
+			Extra:       "Sapient pearwood",
+		},
+		ventures.NewVenture{
+			Description: "Twoflower",
+			State:       "In progress",
+			Orders:      "12",
+			Extra:       "Tourist",
+		},
+		ventures.NewVenture{
+			Description: "Death",
+			State:       "Finished",
+			Extra:       "SPEAKS IN CAPITALS",
+		},
+	} {
+		vens = append(vens, *vtest.Inject(nv))
+		time.Sleep(2 * time.Millisecond)
+	}
+	return vens
+}
+
+// getVentures requests the Ventures matching the query string 'query'
+// returning their IDs in the order they were returned.
+func getVentures(t *testing.T, query string) []string {
+	req := test.APICall{
+		URL:    test.Host + "/ventures?" + query,
+		Method: "GET",
+	}
+	res := req.Fire()
+	defer res.Body.Close()
+
+	require.Equal(t, 200, res.StatusCode)
+	out := ventures.AssertVentureSliceFromReader(t, test.PrintBody(t, res))
+
+	ids := []string{}
+	for _, ven := range out {
+		ids = append(ids, ven.ID)
+	}
+	return ids
+}
+
+func TestGET_Ventures_16(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Ventures in different states exist on the server
+		When Ventures are requested by one or many 'state'
+		Ensure only the Ventures in those states are returned
+		And states are matched regardless of case and separators
+	`)
+
+	vtest.SetupEmptyTest()
+	defer vtest.TearDown()
+	vens := injectSearchable()
+
+	assert.Equal(t, []string{vens[1].ID, vens[2].ID}, getVentures(t, "state=in_PROGRESS"))
+	assert.Equal(t, []string{vens[0].ID, vens[3].ID}, getVentures(t, "state=Not%20started,Finished"))
+}
+
+func TestGET_Ventures_17(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Ventures exist on the server
+		When Ventures are searched for with 'q'
+		Ensure only the Ventures whose description or extra contain the search
+		text, regardless of case, are returned
+		And wildcards within the search text are matched literally
+	`)
+
+	vtest.SetupEmptyTest()
+	defer vtest.TearDown()
+	vens := injectSearchable()
+
+	assert.Equal(t, []string{vens[0].ID, vens[3].ID}, getVentures(t, "q=IN"))
+	assert.Equal(t, []string{vens[3].ID}, getVentures(t, "q=capitals"))
+	assert.Equal(t, []string{vens[1].ID}, getVentures(t, "q=wood"))
+	assert.Empty(t, getVentures(t, "q=%25"))
+}
+
+func TestGET_Ventures_18(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Ventures with Orders exist on the server
+		When Ventures are requested with 'has_order'
+		Ensure only the Ventures referencing that exact Order are returned
+	`)
+
+	vtest.SetupEmptyTest()
+	defer vtest.TearDown()
+	vens := injectSearchable()
+
+	assert.Equal(t, []string{vens[0].ID, vens[2].ID}, getVentures(t, "has_order=12"))
+	assert.Equal(t, []string{vens[1].ID}, getVentures(t, "has_order=2"))
+	assert.Empty(t, getVentures(t, "has_order=99"))
+}
+
+func TestGET_Ventures_19(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Ventures modified at different times exist on the server
+		When Ventures are requested with 'modified_since' and 'modified_before'
+		Ensure only the Ventures modified within that window are returned
+		And 'modified_since' is inclusive while 'modified_before' is exclusive
+	`)
+
+	vtest.SetupEmptyTest()
+	defer vtest.TearDown()
+	vens := injectSearchable()
+
+	q := fmt.Sprintf("modified_since=%d&modified_before=%d",
+		vens[1].LastModified, vens[3].LastModified)
+
+	assert.Equal(t, []string{vens[1].ID, vens[2].ID}, getVentures(t, q))
+}
+
+func TestGET_Ventures_20(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Ventures exist on the server
+		When Ventures are requested with 'sort'
+		Ensure the Ventures are returned in that order
+		And states are ordered as they are within the workflow
+		And Ventures equal by every sort property are ordered by ID
+	`)
+
+	vtest.SetupEmptyTest()
+	defer vtest.TearDown()
+	vens := injectSearchable()
+
+	assert.Equal(t, []string{vens[3].ID, vens[2].ID, vens[1].ID, vens[0].ID},
+		getVentures(t, "sort=-id"))
+	assert.Equal(t, []string{vens[3].ID, vens[0].ID, vens[1].ID, vens[2].ID},
+		getVentures(t, "sort=description"))
+	assert.Equal(t, []string{vens[0].ID, vens[1].ID, vens[2].ID, vens[3].ID},
+		getVentures(t, "sort=state"))
+	assert.Equal(t, []string{vens[0].ID, vens[2].ID, vens[1].ID, vens[3].ID},
+		getVentures(t, "sort=state,-id"))
+	assert.Equal(t, []string{vens[3].ID, vens[1].ID, vens[2].ID, vens[0].ID},
+		getVentures(t, "sort=-state"))
+	assert.Equal(t, []string{vens[2].ID, vens[1].ID},
+		getVentures(t, "state=In%20progress&sort=-last_modified"))
+}
+
+func TestGET_Ventures_21(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Ventures exist on the server
+		When Ventures are requested with 'fields'
+		Ensure each Venture returned has only those properties
+	`)
+
+	vtest.SetupEmptyTest()
+	defer vtest.TearDown()
+	vens := injectSearchable()
+
+	req := test.APICall{
+		URL:    test.Host + "/ventures?fields=id,state&state=Finished",
+		Method: "GET",
+	}
+	res := req.Fire()
+	defer res.Body.Close()
+
+	require.Equal(t, 200, res.StatusCode)
+
+	out := []map[string]interface{}{}
+	err := json.NewDecoder(test.PrintBody(t, res)).Decode(&out)
+	require.Nil(t, err)
+
+	assert.Equal(t, []map[string]interface{}{
+		map[string]interface{}{
+			"id":    vens[3].ID,
+			"state": "Finished",
+		},
+	}, out)
+}
+
+func TestGET_Ventures_22(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Ventures exist on the server
+		When Ventures are requested with invalid filter, sort, and projection
+		parameters
+		Ensure the response code is 400
+		And a violation is listed for each invalid parameter
+	`)
+
+	vtest.SetupTest()
+	defer vtest.TearDown()
+
+	req := test.APICall{
+		URL: test.Host + "/ventures?state=Sleeping&has_order=abc" +
+			"&modified_since=yesterday&sort=colour&fields=id,colour",
+		Method: "GET",
+	}
+	res := req.Fire()
+	defer res.Body.Close()
+
+	require.Equal(t, 400, res.StatusCode)
+	test.AssertDefaultHeaders(t, res, "application/problem+json", "GET, POST, PUT, DELETE, OPTIONS")
+
+	reply := test.AssertErrorBody(t, test.PrintBody(t, res))
+
+	fields := []string{}
+	for _, v := range reply.Errors {
+		fields = append(fields, v.Field)
+	}
+
+	assert.ElementsMatch(t, []string{
+		"modified_since",
+		"state",
+		"has_order",
+		"sort",
+		"fields",
+	}, fields)
+}
+
+// ****************************************************************************
+// (GET) /ventures?limit&cursor
+// ****************************************************************************
+
+// idsOf returns the IDs of the Ventures 'vens' in order.
+func idsOf(vens []ventures.Venture) []string {
+	ids := []string{}
+	for _, ven := range vens {
+		ids = append(ids, ven.ID)
+	}
+	return ids
+}
+
+// getPage requests the page of Ventures at 'uri', relative to the host,
+// returning the IDs of the Ventures within it and the URI of each page linked
+// to by the 'Link' header keyed by relation.
+func getPage(t *testing.T, uri string) ([]string, map[string]string) {
+	req := test.APICall{
+		URL:    test.Host + uri,
+		Method: "GET",
+	}
+	res := req.Fire()
+	defer res.Body.Close()
+
+	require.Equal(t, 200, res.StatusCode)
+	out := ventures.AssertVentureSliceFromReader(t, test.PrintBody(t, res))
+
+	links := map[string]string{}
+	for _, l := range strings.Split(res.Header.Get("Link"), ",") {
+		parts := strings.Split(strings.TrimSpace(l), ";")
+		if len(parts) != 2 {
+			continue
+		}
+
+		uri := strings.Trim(strings.TrimSpace(parts[0]), "<>")
+		rel := strings.TrimPrefix(strings.TrimSpace(parts[1]), "rel=")
+		links[strings.Trim(rel, `"`)] = uri
+	}
+
+	return idsOf(out), links
+}
+
+func TestGET_Ventures_23(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Ventures exist on the server
+		When Ventures are requested with a 'limit'
+		Ensure no more than 'limit' Ventures are returned per page
+		And the pages are ordered by last modified time then ID
+		And the 'Link' header links to the next and previous pages
+		And following the links visits every Venture exactly once
+	`)
+
+	vtest.SetupTest()
+	defer vtest.TearDown()
+
+	vens := injectSearchable()
+	ids := strings.Join(idsOf(vens), ",")
+
+	page, links := getPage(t, "/ventures?ids="+ids+"&limit=3")
+	assert.Equal(t, idsOf(vens[:3]), page)
+	assert.NotContains(t, links, "prev")
+	require.Contains(t, links, "next")
+
+	page, links = getPage(t, links["next"])
+	assert.Equal(t, idsOf(vens[3:]), page)
+	assert.NotContains(t, links, "next")
+	require.Contains(t, links, "prev")
+
+	page, links = getPage(t, links["prev"])
+	assert.Equal(t, idsOf(vens[:3]), page)
+	assert.NotContains(t, links, "prev")
+	assert.Contains(t, links, "next")
+}
+
+func TestGET_Ventures_24(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Ventures exist on the server
+		When Ventures are paginated and one already visited is modified between
+		requests
+		Ensure no Venture is skipped or repeated by the following pages
+		And the modified Venture appears once more at the end
+	`)
+
+	vtest.SetupTest()
+	defer vtest.TearDown()
+
+	vens := injectSearchable()
+	ids := strings.Join(idsOf(vens), ",")
+
+	page, links := getPage(t, "/ventures?ids="+ids+"&limit=2")
+	assert.Equal(t, idsOf(vens[:2]), page)
+
+	time.Sleep(2 * time.Millisecond)
+	modVentures(t, ventures.ModVenture{
+		IDs:    vens[0].ID,
+		Props:  "description",
+		Values: ventures.Venture{Description: "Rincewind the wizzard"},
+	})
+
+	page, links = getPage(t, links["next"])
+	assert.Equal(t, idsOf(vens[2:]), page)
+
+	page, links = getPage(t, links["next"])
+	assert.Equal(t, []string{vens[0].ID}, page)
+	assert.NotContains(t, links, "next")
+}
+
+func TestGET_Ventures_25(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Ventures exist on the server
+		When a page of Ventures is requested and wrapped
+		Ensure the reply holds the next and previous page links
+		And the total number of Ventures matched
+	`)
+
+	vtest.SetupTest()
+	defer vtest.TearDown()
+
+	vens := injectSearchable()
+	ids := strings.Join(idsOf(vens), ",")
+
+	_, links := getPage(t, "/ventures?ids="+ids+"&limit=1")
+	require.Contains(t, links, "next")
+
+	req := test.APICall{
+		URL:    test.Host + links["next"] + "&wrap",
+		Method: "GET",
+	}
+	res := req.Fire()
+	defer res.Body.Close()
+
+	require.Equal(t, 200, res.StatusCode)
+
+	wr, out := ventures.AssertWrappedVentureSliceFromReader(t, test.PrintBody(t, res))
+	assert.Equal(t, idsOf(vens[1:2]), idsOf(out))
+	assert.NotEmpty(t, wr.Next)
+	assert.NotEmpty(t, wr.Prev)
+	require.NotNil(t, wr.Total)
+	assert.Equal(t, len(vens), *wr.Total)
+}
+
+func TestGET_Ventures_26(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Ventures exist on the server
+		When Ventures are requested with an invalid 'ids', 'limit', or 'cursor',
+		or are sorted while paginated
+		Ensure the response code is 400
+		And a violation is listed for each invalid parameter
+	`)
+
+	vtest.SetupTest()
+	defer vtest.TearDown()
+
+	for _, q := range []struct {
+		query string
+		field string
+	}{
+		{"limit=0", "limit"},
+		{"limit=many", "limit"},
+		{"cursor=not-a-cursor", "cursor"},
+		{"limit=2&sort=id", "sort"},
+		{"ids=1,two", "ids"},
+		{"ids=1,-2", "ids"},
+	} {
+		req := test.APICall{
+			URL:    test.Host + "/ventures?" + q.query,
+			Method: "GET",
+		}
+		res := req.Fire()
+		defer res.Body.Close()
+
+		require.Equal(t, 400, res.StatusCode, "Query: %s", q.query)
+		reply := test.AssertErrorBody(t, test.PrintBody(t, res))
+		require.Len(t, reply.Errors, 1, "Query: %s", q.query)
+		assert.Equal(t, q.field, reply.Errors[0].Field, "Query: %s", q.query)
+	}
+}
+
+func TestGET_Ventures_27(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Ventures exist on the server
+		When Ventures are requested with a 'strict' value that is not a boolean
+		Ensure the response code is 400
+		And a 'strict' violation is listed
+	`)
+
+	vtest.SetupTest()
+	defer vtest.TearDown()
+
+	req := test.APICall{
+		URL:    test.Host + "/ventures?ids=1,2&strict=maybe",
+		Method: "GET",
+	}
+	res := req.Fire()
+	defer res.Body.Close()
+
+	require.Equal(t, 400, res.StatusCode)
+	reply := test.AssertErrorBody(t, test.PrintBody(t, res))
+	require.Len(t, reply.Errors, 1)
+	assert.Equal(t, "strict", reply.Errors[0].Field)
+	assert.Equal(t, "invalid", reply.Errors[0].Code)
+}
+
+// ****************************************************************************
+// (GET) /ventures/{id}
+// ****************************************************************************
+
+func TestGET_Venture_1(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Ventures exist on the server
+		When a single Venture is requested by its ID
+		Ensure the response code is 200
+		And header includes:
+			Content-Type:                   'application/json; charset=utf-8'
+			Access-Control-Allow-Origin:    '*'
+			Access-Control-Allow-Headers:   '*'
+			Access-Control-Allow-Methods:   'GET, PATCH, DELETE, OPTIONS'
+		And the body is the Venture requested
+		And the 'ETag' header represents the Venture
+	`)
+
+	vtest.SetupEmptyTest()
+	defer vtest.TearDown()
+	vens := injectSearchable()
+
+	req := test.APICall{
+		URL:    test.Host + "/ventures/" + vens[1].ID,
+		Method: "GET",
+	}
+	res := req.Fire()
+	defer res.Body.Close()
+
+	require.Equal(t, 200, res.StatusCode)
+	test.AssertDefaultHeaders(t, res, "application/json", "GET, PATCH, DELETE, OPTIONS")
+
+	out := ventures.AssertVentureFromReader(t, test.PrintBody(t, res))
+	assert.Equal(t, vens[1], out)
+	assert.Equal(t, ventures.ETag([]ventures.Venture{out}), res.Header.Get("ETag"))
+}
+
+func TestGET_Venture_2(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Ventures exist on the server
+		When a single Venture is requested by an ID that does not exist, or
+		is not an ID
+		Ensure the response code is 404
+	`)
+
+	vtest.SetupTest()
+	defer vtest.TearDown()
+
+	for _, id := range []string{"999999", "wizard", "1/description"} {
+		req := test.APICall{
+			URL:    test.Host + "/ventures/" + id,
+			Method: "GET",
+		}
+		res := req.Fire()
+		defer res.Body.Close()
+
+		require.Equal(t, 404, res.StatusCode, "ID: %s", id)
+		test.AssertErrorBody(t, test.PrintBody(t, res))
+	}
+}
@@ -31,7 +31,7 @@ func TestOPTIONS_Ventures(t *testing.T) {
 	defer vtest.TearDown()
 
 	req := test.APICall{
-		URL:    "http://localhost:8080/ventures",
+		URL:    test.Host + "/ventures",
 		Method: "OPTIONS",
 	}
 	res := req.Fire()
@@ -63,7 +63,7 @@ func TestINVALID_Ventures(t *testing.T) {
 	defer vtest.TearDown()
 
 	goodMethods := "GET, POST, PUT, DELETE, OPTIONS"
-	test.VerifyBadMethods(t, "http://localhost:8080/ventures", goodMethods, []string{
+	test.VerifyBadMethods(t, test.Host+"/ventures", goodMethods, []string{
 		"HEAD",
 		"CONNECT",
 		"TRACE",
@@ -71,3 +71,65 @@ func TestINVALID_Ventures(t *testing.T) {
 		"CUSTOM",
 	})
 }
+
+// ****************************************************************************
+// (OPTIONS) /ventures/{id}
+// ****************************************************************************
+
+func TestOPTIONS_Venture(t *testing.T) {
+	t.Log(`Given some Ventures already exist on the server
+		When /ventures/{id} OPTIONS are requested
+		Then ensure the response code is 200
+		And 'Access-Control-Allow-Origin' is '*'
+		And 'Access-Control-Allow-Headers' is '*'
+		And 'Access-Control-Allow-Methods' is 'GET, PATCH, DELETE, OPTIONS'
+		And 'Accept-Patch' lists the patch media types accepted
+		And there is NO response body
+		...`)
+
+	vtest.SetupTest()
+	defer vtest.TearDown()
+
+	req := test.APICall{
+		URL:    test.Host + "/ventures/1",
+		Method: "OPTIONS",
+	}
+	res := req.Fire()
+
+	defer res.Body.Close()
+	defer test.PrintResponse(t, res.Body)
+
+	require.Equal(t, 200, res.StatusCode)
+	test.AssertCorsHeaders(t, res, "GET, PATCH, DELETE, OPTIONS")
+	require.Equal(t, "application/merge-patch+json, application/json-patch+json",
+		res.Header.Get("Accept-Patch"))
+	test.AssertEmptyBody(t, res.Body)
+}
+
+// ****************************************************************************
+// (?) /ventures/{id}
+// ****************************************************************************
+
+func TestINVALID_Venture(t *testing.T) {
+	t.Log(`Given some Ventures already exist on the server
+	 	When /ventures/{id} is called using invalid methods
+		Then ensure the response code is 405
+		And 'Access-Control-Allow-Origin' is '*'
+		And 'Access-Control-Allow-Headers' is '*'
+		And 'Access-Control-Allow-Methods' is 'GET, PATCH, DELETE, OPTIONS'
+		And there is NO response body
+		...`)
+
+	vtest.SetupTest()
+	defer vtest.TearDown()
+
+	goodMethods := "GET, PATCH, DELETE, OPTIONS"
+	test.VerifyBadMethods(t, test.Host+"/ventures/1", goodMethods, []string{
+		"HEAD",
+		"CONNECT",
+		"TRACE",
+		"POST",
+		"PUT",
+		"CUSTOM",
+	})
+}
@@ -0,0 +1,358 @@
+package PATCH
+
+import (
+	"math"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PaulioRandall/go-qlueless-api/api/orders"
+	"github.com/PaulioRandall/go-qlueless-api/api/ventures"
+	"github.com/PaulioRandall/go-qlueless-api/test"
+	vtest "github.com/PaulioRandall/go-qlueless-api/test/ventures"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	test.SetWorkingDir("../../../bin")
+}
+
+// injectOne injects a single Venture to patch.
+func injectOne() *ventures.Venture {
+	ven := vtest.Inject(ventures.NewVenture{
+		Description: "White wizard",
+		State:       "Not started",
+		Extra:       "Staff",
+	})
+	time.Sleep(2 * time.Millisecond)
+	return ven
+}
+
+// patchVenture patches the Venture with the ID 'id' using the patch 'body' of
+// the media type 'contentType'.
+func patchVenture(id string, contentType string, body string, h http.Header) *http.Response {
+	if h == nil {
+		h = http.Header{}
+	}
+	h.Set("Content-Type", contentType)
+
+	req := test.APICall{
+		URL:    test.Host + "/ventures/" + id,
+		Method: "PATCH",
+		Body:   strings.NewReader(body),
+		Header: h,
+	}
+	return req.Fire()
+}
+
+// revisionsOf returns the number of revisions of the Venture with the ID 'id'.
+func revisionsOf(t *testing.T, id string) int {
+	vens, err := vtest.Store().History([]string{id}, 0, math.MaxInt64)
+	require.Nil(t, err)
+	return len(vens)
+}
+
+// ****************************************************************************
+// (PATCH) /ventures/{id} (merge patch)
+// ****************************************************************************
+
+func TestPATCH_Venture_1(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given a Venture exists on the server
+		When it is patched using a JSON Merge Patch
+		Ensure the response code is 200
+		And header includes:
+			Content-Type:                   'application/json; charset=utf-8'
+			Access-Control-Allow-Origin:    '*'
+			Access-Control-Allow-Headers:   '*'
+			Access-Control-Allow-Methods:   'GET, PATCH, DELETE, OPTIONS'
+		And the body is the patched Venture
+		And properties set to null are emptied
+		And the patch is stored as a new revision
+	`)
+
+	vtest.SetupEmptyTest()
+	defer vtest.TearDown()
+
+	before := injectOne()
+
+	res := patchVenture(before.ID, "application/merge-patch+json",
+		`{"description": "Grey wizard", "state": "in-progress", "extra": null}`, nil)
+	defer res.Body.Close()
+
+	require.Equal(t, 200, res.StatusCode)
+	test.AssertDefaultHeaders(t, res, "application/json", "GET, PATCH, DELETE, OPTIONS")
+
+	out := ventures.AssertVentureFromReader(t, test.PrintBody(t, res))
+	assert.Equal(t, before.ID, out.ID)
+	assert.Equal(t, "Grey wizard", out.Description)
+	assert.Equal(t, "In progress", out.State)
+	assert.Empty(t, out.Extra)
+	assert.True(t, out.LastModified > before.LastModified)
+	assert.Equal(t, ventures.ETag([]ventures.Venture{out}), res.Header.Get("ETag"))
+
+	assert.Equal(t, out, vtest.DBQueryOne(before.ID))
+	assert.Equal(t, 2, revisionsOf(t, before.ID))
+}
+
+func TestPATCH_Venture_8(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given a Venture referencing an Order exists on the server
+		When it is patched so its Orders are null or empty
+		Ensure the response code is 200
+		And the Venture no longer references any Orders
+	`)
+
+	vtest.SetupEmptyTest()
+	defer vtest.TearDown()
+
+	o := vtest.InjectOrder(orders.NewOrder{
+		Description: "Hat",
+		State:       "Not started",
+	})
+
+	for _, c := range []struct {
+		contentType string
+		patch       string
+	}{
+		{"application/merge-patch+json", `{"orders": null}`},
+		{"application/merge-patch+json", `{"orders": ""}`},
+		{"application/json-patch+json", `[{"op": "replace", "path": "/orders", "value": ""}]`},
+	} {
+		before := vtest.Inject(ventures.NewVenture{
+			Description: "White wizard",
+			Orders:      o.ID,
+			State:       "Not started",
+		})
+		time.Sleep(2 * time.Millisecond)
+
+		res := patchVenture(before.ID, c.contentType, c.patch, nil)
+		defer res.Body.Close()
+
+		require.Equal(t, 200, res.StatusCode, "Patch: %s", c.patch)
+		out := ventures.AssertVentureFromReader(t, test.PrintBody(t, res))
+		assert.Empty(t, out.Orders, "Patch: %s", c.patch)
+		assert.Equal(t, out, vtest.DBQueryOne(before.ID), "Patch: %s", c.patch)
+	}
+}
+
+// ****************************************************************************
+// (PATCH) /ventures/{id} (JSON patch)
+// ****************************************************************************
+
+func TestPATCH_Venture_2(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given a Venture exists on the server
+		When it is patched using a JSON Patch
+		Ensure the response code is 200
+		And the operations are applied in order
+		And the patch is stored as a new revision
+	`)
+
+	vtest.SetupEmptyTest()
+	defer vtest.TearDown()
+
+	before := injectOne()
+
+	res := patchVenture(before.ID, "application/json-patch+json", `[
+		{"op": "test", "path": "/description", "value": "White wizard"},
+		{"op": "copy", "from": "/extra", "path": "/description"},
+		{"op": "replace", "path": "/extra", "value": "Hat"}
+	]`, nil)
+	defer res.Body.Close()
+
+	require.Equal(t, 200, res.StatusCode)
+
+	out := ventures.AssertVentureFromReader(t, test.PrintBody(t, res))
+	assert.Equal(t, "Staff", out.Description)
+	assert.Equal(t, "Hat", out.Extra)
+	assert.Equal(t, "Not started", out.State)
+
+	assert.Equal(t, out, vtest.DBQueryOne(before.ID))
+	assert.Equal(t, 2, revisionsOf(t, before.ID))
+}
+
+func TestPATCH_Venture_3(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given a Venture exists on the server
+		When it is patched using a JSON Patch that does not apply to it or
+		is malformed
+		Ensure the response code is 409 if it does not apply
+		And the response code is 400 if it is malformed
+		And the Venture has not been modified
+	`)
+
+	vtest.SetupEmptyTest()
+	defer vtest.TearDown()
+
+	before := injectOne()
+
+	for _, c := range []struct {
+		patch  string
+		status int
+	}{
+		{`[{"op": "test", "path": "/description", "value": "Grey wizard"}]`, 409},
+		{`[{"op": "remove", "path": "/hat"}]`, 409},
+		{`[{"op": "jump", "path": "/description"}]`, 400},
+		{`[{"op": "replace", "path": "description", "value": "x"}]`, 400},
+		{`{"description": "Grey wizard"}`, 400},
+	} {
+		res := patchVenture(before.ID, "application/json-patch+json", c.patch, nil)
+		defer res.Body.Close()
+
+		require.Equal(t, c.status, res.StatusCode, "Patch: %s", c.patch)
+		test.AssertErrorBody(t, test.PrintBody(t, res))
+	}
+
+	assert.Equal(t, *before, vtest.DBQueryOne(before.ID))
+	assert.Equal(t, 1, revisionsOf(t, before.ID))
+}
+
+// ****************************************************************************
+// (PATCH) /ventures/{id} (validation)
+// ****************************************************************************
+
+func TestPATCH_Venture_4(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given a Venture exists on the server
+		When it is patched with invalid values or changes to unknown or
+		immutable properties
+		Ensure the response code is 400
+		And a violation is listed for each offending property
+		And the Venture has not been modified
+	`)
+
+	vtest.SetupEmptyTest()
+	defer vtest.TearDown()
+
+	before := injectOne()
+
+	for _, c := range []struct {
+		patch  string
+		fields []string
+	}{
+		{`{"id": "999", "last_modified": 1, "colour": "White"}`, []string{"id", "last_modified", "colour"}},
+		{`{"description": null, "orders": "a,b"}`, []string{"description", "orders"}},
+		{`{"state": "Sleeping", "dead": "yes"}`, []string{"state", "dead"}},
+		{`{"state": "Sleeping"}`, []string{"state"}},
+		{`{"state": "Finished"}`, []string{"state"}},
+	} {
+		res := patchVenture(before.ID, "application/merge-patch+json", c.patch, nil)
+		defer res.Body.Close()
+
+		require.Equal(t, 400, res.StatusCode, "Patch: %s", c.patch)
+		p := test.AssertErrorBody(t, test.PrintBody(t, res))
+
+		fields := []string{}
+		for _, v := range p.Errors {
+			fields = append(fields, v.Field)
+		}
+		assert.ElementsMatch(t, c.fields, fields, "Patch: %s", c.patch)
+	}
+
+	assert.Equal(t, *before, vtest.DBQueryOne(before.ID))
+	assert.Equal(t, 1, revisionsOf(t, before.ID))
+}
+
+func TestPATCH_Venture_5(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given a Venture exists on the server
+		When it is patched using a media type other than a JSON Merge Patch or
+		JSON Patch
+		Ensure the response code is 415
+		And the 'Accept-Patch' header lists the media types accepted
+		And the Venture has not been modified
+	`)
+
+	vtest.SetupEmptyTest()
+	defer vtest.TearDown()
+
+	before := injectOne()
+
+	res := patchVenture(before.ID, "application/json", `{"description": "Grey wizard"}`, nil)
+	defer res.Body.Close()
+
+	require.Equal(t, 415, res.StatusCode)
+	assert.Equal(t, "application/merge-patch+json, application/json-patch+json",
+		res.Header.Get("Accept-Patch"))
+	test.AssertErrorBody(t, test.PrintBody(t, res))
+
+	assert.Equal(t, *before, vtest.DBQueryOne(before.ID))
+}
+
+func TestPATCH_Venture_6(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given a Venture exists on the server
+		When a Venture that does not exist is patched
+		Ensure the response code is 404
+	`)
+
+	vtest.SetupEmptyTest()
+	defer vtest.TearDown()
+
+	injectOne()
+
+	res := patchVenture("999999", "application/merge-patch+json", `{"description": "Grey wizard"}`, nil)
+	defer res.Body.Close()
+
+	require.Equal(t, 404, res.StatusCode)
+	test.AssertErrorBody(t, test.PrintBody(t, res))
+}
+
+// ****************************************************************************
+// (PATCH) /ventures/{id} (If-Match)
+// ****************************************************************************
+
+func TestPATCH_Venture_7(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given a Venture exists on the server
+		When it is patched with an 'If-Match' ETag of an older revision
+		Ensure the response code is 412
+		And the problem body contains the current revision of the Venture
+		And the Venture has not been modified
+		And patching with the 'ETag' of the latest revision succeeds
+	`)
+
+	vtest.SetupEmptyTest()
+	defer vtest.TearDown()
+
+	before := injectOne()
+	stale := ventures.ETag([]ventures.Venture{*before})
+
+	_, err := vtest.Store().Modify(&ventures.ModVenture{
+		IDs:    before.ID,
+		Props:  "extra",
+		Values: ventures.Venture{Extra: "Hat"},
+	})
+	require.Nil(t, err)
+
+	h := http.Header{}
+	h.Set("If-Match", stale)
+	res := patchVenture(before.ID, "application/merge-patch+json", `{"description": "Grey wizard"}`, h)
+	defer res.Body.Close()
+
+	require.Equal(t, 412, res.StatusCode)
+	latest := vtest.DBQueryOne(before.ID)
+	assert.Equal(t, "White wizard", latest.Description)
+
+	_, current := ventures.AssertConflictFromReader(t, test.PrintBody(t, res))
+	require.Len(t, current, 1)
+	assert.Equal(t, latest, current[0])
+
+	h.Set("If-Match", ventures.ETag([]ventures.Venture{latest}))
+	res = patchVenture(before.ID, "application/merge-patch+json", `{"description": "Grey wizard"}`, h)
+	defer res.Body.Close()
+
+	require.Equal(t, 200, res.StatusCode)
+	assert.Equal(t, "Grey wizard", vtest.DBQueryOne(before.ID).Description)
+}
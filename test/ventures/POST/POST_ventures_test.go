@@ -3,6 +3,8 @@ package POST
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
+	"sync"
 	"testing"
 
 	ventures "github.com/PaulioRandall/go-qlueless-api/api/ventures"
@@ -47,7 +49,7 @@ func TestPOST_Venture_1(t *testing.T) {
 	json.NewEncoder(buf).Encode(&input)
 
 	req := test.APICall{
-		URL:    "http://localhost:8080/ventures",
+		URL:    test.Host + "/ventures",
 		Method: "POST",
 		Body:   buf,
 	}
@@ -94,7 +96,7 @@ func TestPOST_Venture_2(t *testing.T) {
 	json.NewEncoder(buf).Encode(&input)
 
 	req := test.APICall{
-		URL:    "http://localhost:8080/ventures",
+		URL:    test.Host + "/ventures",
 		Method: "POST",
 		Body:   buf,
 	}
@@ -104,7 +106,7 @@ func TestPOST_Venture_2(t *testing.T) {
 	defer test.PrintResponse(t, res.Body)
 
 	require.Equal(t, 400, res.StatusCode)
-	test.AssertDefaultHeaders(t, res, "application/json", "GET, POST, PUT, DELETE, OPTIONS")
+	test.AssertDefaultHeaders(t, res, "application/problem+json", "GET, POST, PUT, DELETE, OPTIONS")
 	test.AssertErrorBody(t, res.Body)
 }
 
@@ -140,7 +142,7 @@ func TestPOST_Venture_3(t *testing.T) {
 	json.NewEncoder(buf).Encode(&input)
 
 	req := test.APICall{
-		URL:    "http://localhost:8080/ventures?wrap",
+		URL:    test.Host + "/ventures?wrap",
 		Method: "POST",
 		Body:   buf,
 	}
@@ -162,3 +164,365 @@ func TestPOST_Venture_3(t *testing.T) {
 	assert.Equal(t, input, output)
 	assert.Equal(t, fromDB, output)
 }
+
+func TestPOST_Venture_4(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Orders and Ventures already exist on the server
+		When a new Venture referencing non-existent Orders is POSTed
+		Ensure the response code is 400
+		And the body is a JSON object representing an error response
+		And the error message lists the Order IDs that could not be found
+		And no Venture has been created
+	`)
+
+	vtest.SetupTest()
+	defer vtest.TearDown()
+
+	before := vtest.DBQueryAll()
+
+	input := ventures.NewVenture{
+		Description: "A new Venture",
+		State:       "Not started",
+		Orders:      "1,88888,3,99999",
+	}
+	buf := new(bytes.Buffer)
+	json.NewEncoder(buf).Encode(&input)
+
+	req := test.APICall{
+		URL:    test.Host + "/ventures",
+		Method: "POST",
+		Body:   buf,
+	}
+	res := req.Fire()
+	defer res.Body.Close()
+
+	require.Equal(t, 400, res.StatusCode)
+	test.AssertDefaultHeaders(t, res, "application/problem+json", "GET, POST, PUT, DELETE, OPTIONS")
+
+	reply := test.AssertErrorBody(t, test.PrintBody(t, res))
+	assert.Contains(t, reply.Detail, "88888, 99999")
+	require.Len(t, reply.Errors, 1)
+	assert.Equal(t, "orders", reply.Errors[0].Field)
+	assert.Equal(t, "not_found", reply.Errors[0].Code)
+
+	ventures.AssertVenturesEqual(t, before, vtest.DBQueryAll(), true)
+}
+
+func TestPOST_Venture_5(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Ventures already exist on the server
+		When a new Venture with a state outside the workflow is POSTed
+		Ensure the response code is 400
+		And the body is a JSON object representing an error response
+		And no Venture has been created
+	`)
+
+	vtest.SetupTest()
+	defer vtest.TearDown()
+
+	before := vtest.DBQueryAll()
+
+	input := ventures.NewVenture{
+		Description: "A new Venture",
+		State:       "wip",
+	}
+	buf := new(bytes.Buffer)
+	json.NewEncoder(buf).Encode(&input)
+
+	req := test.APICall{
+		URL:    test.Host + "/ventures",
+		Method: "POST",
+		Body:   buf,
+	}
+	res := req.Fire()
+	defer res.Body.Close()
+
+	require.Equal(t, 400, res.StatusCode)
+	test.AssertDefaultHeaders(t, res, "application/problem+json", "GET, POST, PUT, DELETE, OPTIONS")
+	test.AssertErrorBody(t, test.PrintBody(t, res))
+
+	ventures.AssertVenturesEqual(t, before, vtest.DBQueryAll(), true)
+}
+
+func TestPOST_Venture_6(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given no Ventures exist on the server
+		When a new Venture is POSTed with a state that differs from a workflow
+		state only by case and separators
+		Ensure the response code is 201
+		And the created Venture has the workflow spelling of the state
+	`)
+
+	vtest.SetupEmptyTest()
+	defer vtest.TearDown()
+
+	input := ventures.NewVenture{
+		Description: "A new Venture",
+		State:       "  IN-progress ",
+	}
+	buf := new(bytes.Buffer)
+	json.NewEncoder(buf).Encode(&input)
+
+	req := test.APICall{
+		URL:    test.Host + "/ventures",
+		Method: "POST",
+		Body:   buf,
+	}
+	res := req.Fire()
+	defer res.Body.Close()
+
+	require.Equal(t, 201, res.StatusCode)
+
+	result := ventures.AssertVentureFromReader(t, test.PrintBody(t, res))
+	assert.Equal(t, "In progress", result.State)
+	assert.Equal(t, "In progress", vtest.DBQueryOne(result.ID).State)
+}
+
+func TestPOST_Venture_7(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given no Ventures exist on the server
+		When many new Ventures are POSTed concurrently
+		Ensure the response code of each is 201
+		And every Venture created is assigned a unique ID
+		And every Venture created exists within the database
+	`)
+
+	vtest.SetupEmptyTest()
+	defer vtest.TearDown()
+
+	const n = 20
+	ids := make(chan string, n)
+	wg := sync.WaitGroup{}
+
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			input := ventures.NewVenture{
+				Description: fmt.Sprintf("Venture %d", i),
+				State:       "Not started",
+			}
+			buf := new(bytes.Buffer)
+			json.NewEncoder(buf).Encode(&input)
+
+			req := test.APICall{
+				URL:    test.Host + "/ventures",
+				Method: "POST",
+				Body:   buf,
+			}
+			res := req.Fire()
+			defer res.Body.Close()
+
+			if !assert.Equal(t, 201, res.StatusCode) {
+				return
+			}
+
+			ven := ventures.Venture{}
+			err := json.NewDecoder(res.Body).Decode(&ven)
+			if assert.Nil(t, err) {
+				ids <- ven.ID
+			}
+		}(i)
+	}
+
+	wg.Wait()
+	close(ids)
+
+	unique := map[string]bool{}
+	for id := range ids {
+		assert.False(t, unique[id], "Venture ID '%s' was assigned more than once", id)
+		unique[id] = true
+	}
+
+	assert.Len(t, unique, n)
+	assert.Len(t, vtest.DBQueryAll(), n)
+}
+
+func TestPOST_Venture_8(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given no Ventures exist on the server
+		When a JSON array of new valid Ventures is POSTed
+		Ensure the response code is 201
+		And the body is a JSON array of the created Ventures in input order
+		And each Venture has a new, unique, ID
+		And every Venture created exists within the database
+	`)
+
+	vtest.SetupEmptyTest()
+	defer vtest.TearDown()
+
+	input := []ventures.NewVenture{
+		ventures.NewVenture{
+			Description: "Rincewind",
+			State:       "Not started",
+		},
+		ventures.NewVenture{
+			Description: "The Luggage",
+			State:       "In progress",
+			Extra:       "Sapient pearwood",
+		},
+		ventures.NewVenture{
+			Description: "Twoflower",
+			State:       "Finished",
+		},
+	}
+	buf := new(bytes.Buffer)
+	json.NewEncoder(buf).Encode(&input)
+
+	req := test.APICall{
+		URL:    test.Host + "/ventures",
+		Method: "POST",
+		Body:   buf,
+	}
+	res := req.Fire()
+	defer res.Body.Close()
+
+	require.Equal(t, 201, res.StatusCode)
+	test.AssertDefaultHeaders(t, res, "application/json", "GET, POST, PUT, DELETE, OPTIONS")
+
+	output := ventures.AssertVentureSliceFromReader(t, test.PrintBody(t, res))
+	ventures.AssertGenericVentureSlice(t, output)
+	require.Len(t, output, len(input))
+
+	unique := map[string]bool{}
+	for i, ven := range output {
+		assert.Equal(t, input[i].Description, ven.Description)
+		assert.Equal(t, input[i].State, ven.State)
+		assert.Equal(t, input[i].Extra, ven.Extra)
+		assert.False(t, unique[ven.ID], "Venture ID '%s' was assigned more than once", ven.ID)
+		unique[ven.ID] = true
+	}
+
+	ventures.AssertVenturesEqual(t, output, vtest.DBQueryAll(), true)
+}
+
+func TestPOST_Venture_9(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Ventures already exist on the server
+		When a JSON array of new Ventures is POSTed
+		And one of those Ventures is invalid
+		Ensure the response code is 400
+		And the error message states the index of the invalid Venture
+		And no Ventures are created
+	`)
+
+	vtest.SetupTest()
+	defer vtest.TearDown()
+
+	before := vtest.DBQueryAll()
+
+	input := []ventures.NewVenture{
+		ventures.NewVenture{
+			Description: "Rincewind",
+			State:       "Not started",
+		},
+		ventures.NewVenture{
+			Description: "",
+			State:       "Not started",
+		},
+	}
+	buf := new(bytes.Buffer)
+	json.NewEncoder(buf).Encode(&input)
+
+	req := test.APICall{
+		URL:    test.Host + "/ventures",
+		Method: "POST",
+		Body:   buf,
+	}
+	res := req.Fire()
+	defer res.Body.Close()
+
+	require.Equal(t, 400, res.StatusCode)
+	test.AssertDefaultHeaders(t, res, "application/problem+json", "GET, POST, PUT, DELETE, OPTIONS")
+
+	reply := test.AssertErrorBody(t, test.PrintBody(t, res))
+	assert.Contains(t, reply.Detail, "[1]")
+	assert.NotContains(t, reply.Detail, "[0]")
+	require.Len(t, reply.Errors, 1)
+	assert.Equal(t, "[1].description", reply.Errors[0].Field)
+	assert.Equal(t, "required", reply.Errors[0].Code)
+
+	ventures.AssertVenturesEqual(t, before, vtest.DBQueryAll(), true)
+}
+
+func TestPOST_Venture_10(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Ventures already exist on the server
+		When an empty JSON array is POSTed
+		Ensure the response code is 400
+		And no Ventures are created
+	`)
+
+	vtest.SetupTest()
+	defer vtest.TearDown()
+
+	before := vtest.DBQueryAll()
+
+	req := test.APICall{
+		URL:    test.Host + "/ventures",
+		Method: "POST",
+		Body:   bytes.NewBufferString(" [ ] "),
+	}
+	res := req.Fire()
+	defer res.Body.Close()
+
+	require.Equal(t, 400, res.StatusCode)
+	test.AssertErrorBody(t, test.PrintBody(t, res))
+
+	ventures.AssertVenturesEqual(t, before, vtest.DBQueryAll(), true)
+}
+
+func TestPOST_Venture_11(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given no Ventures exist on the server
+		When a new Venture with many invalid fields is POSTed
+		Ensure the response code is 400
+		And the 'Content-Type' header contains 'application/problem+json'
+		And the body lists a violation for each invalid field
+	`)
+
+	vtest.SetupEmptyTest()
+	defer vtest.TearDown()
+
+	input := ventures.NewVenture{
+		Description: "",
+		State:       "Not a state",
+		Orders:      "invalid",
+	}
+	buf := new(bytes.Buffer)
+	json.NewEncoder(buf).Encode(&input)
+
+	req := test.APICall{
+		URL:    test.Host + "/ventures",
+		Method: "POST",
+		Body:   buf,
+	}
+	res := req.Fire()
+	defer res.Body.Close()
+
+	require.Equal(t, 400, res.StatusCode)
+	test.AssertDefaultHeaders(t, res, "application/problem+json", "GET, POST, PUT, DELETE, OPTIONS")
+
+	reply := test.AssertErrorBody(t, test.PrintBody(t, res))
+	assert.Equal(t, 400, reply.Status)
+
+	codes := map[string]string{}
+	for _, v := range reply.Errors {
+		codes[v.Field] = v.Code
+	}
+
+	assert.Equal(t, map[string]string{
+		"description": "required",
+		"orders":      "invalid",
+		"state":       "not_in_workflow",
+	}, codes)
+}
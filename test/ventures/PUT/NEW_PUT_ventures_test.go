@@ -1,11 +1,17 @@
 package PUT
 
 import (
+	"bytes"
+	"encoding/json"
+	"net/http"
 	"testing"
+	"time"
 
+	orders "github.com/PaulioRandall/go-qlueless-api/api/orders"
 	ventures "github.com/PaulioRandall/go-qlueless-api/api/ventures"
 	test "github.com/PaulioRandall/go-qlueless-api/test"
 	vtest "github.com/PaulioRandall/go-qlueless-api/test/ventures"
+	assert "github.com/stretchr/testify/assert"
 	require "github.com/stretchr/testify/require"
 )
 
@@ -38,7 +44,7 @@ func TestPUT_Ventures_1(t *testing.T) {
 
 	vtest.Inject(ventures.NewVenture{
 		Description: "Black blizzard",
-		State:       "STARTED",
+		State:       "In progress",
 		Orders:      "1,2,3",
 		Extra:       "colour: black",
 	})
@@ -51,12 +57,12 @@ func TestPUT_Ventures_1(t *testing.T) {
 	input := &ventures.Venture{
 		ID:          id,
 		Description: "White wizzard",
-		State:       "FINISHED",
+		State:       "Finished",
 		Orders:      "4,5,6",
 		Extra:       "colour: white",
 	}
 
-	res := test.CallWithJSON("PUT", "http://localhost:8080/ventures", input)
+	res := test.CallWithJSON("PUT", test.Host+"/ventures", input)
 	defer res.Body.Close()
 	defer test.PrintResponse(t, res.Body)
 
@@ -143,3 +149,480 @@ func TestPUT_Ventures_6(t *testing.T) {
 		...`)
 
 }
+
+func TestPUT_Ventures_5(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Orders and Ventures already exist on the server
+		When a Venture is modified to reference non-existent Orders
+		Ensure the response code is 400
+		And the body is a JSON object representing an error response
+		And the error message lists the Order IDs that could not be found
+		And the Venture is unchanged
+	`)
+
+	vtest.SetupTest()
+	defer vtest.TearDown()
+
+	before := vtest.DBQueryOne("1")
+
+	input := ventures.ModVenture{
+		IDs:   "1",
+		Props: "orders",
+		Values: ventures.Venture{
+			Orders: "2,77777",
+		},
+	}
+
+	buf := new(bytes.Buffer)
+	json.NewEncoder(buf).Encode(&input)
+
+	req := test.APICall{
+		URL:    test.Host + "/ventures",
+		Method: "PUT",
+		Body:   buf,
+	}
+	res := req.Fire()
+	defer res.Body.Close()
+
+	require.Equal(t, 400, res.StatusCode)
+	test.AssertDefaultHeaders(t, res, "application/problem+json", "GET, POST, PUT, DELETE, OPTIONS")
+
+	reply := test.AssertErrorBody(t, test.PrintBody(t, res))
+	assert.Contains(t, reply.Detail, "77777")
+	require.Len(t, reply.Errors, 1)
+	assert.Equal(t, "values.orders", reply.Errors[0].Field)
+	assert.Equal(t, before, vtest.DBQueryOne("1"))
+}
+
+// ****************************************************************************
+// (PUT) /ventures (restore)
+// ****************************************************************************
+
+func TestPUT_Ventures_7(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Ventures already exist on the server
+		And one of them is dead
+		When the dead Venture is modified to be not dead
+		Ensure the response code is 200
+		And the body is a JSON array containing the restored Venture
+		And the Venture is among the living again with its last living content
+	`)
+
+	vtest.SetupTest()
+	defer vtest.TearDown()
+
+	before := vtest.DBQueryOne("2")
+	killVenture(t, "2")
+	require.Empty(t, vtest.DBQueryMany("2"))
+
+	res := putMod(ventures.ModVenture{
+		IDs:   "2",
+		Props: "dead",
+		Values: ventures.Venture{
+			Dead: false,
+		},
+	})
+	defer res.Body.Close()
+
+	require.Equal(t, 200, res.StatusCode)
+	test.AssertDefaultHeaders(t, res, "application/json", "GET, POST, PUT, DELETE, OPTIONS")
+
+	body := test.PrintBody(t, res)
+
+	result := ventures.RequireSliceOfVentures(t, body)
+	require.Len(t, result, 1)
+	assert.False(t, result[0].Dead, "Venture.Dead")
+
+	after := vtest.DBQueryOne("2")
+	assert.Equal(t, before.Description, after.Description)
+	assert.Equal(t, before.State, after.State)
+	assert.Equal(t, before.Orders, after.Orders)
+	assert.Equal(t, before.Extra, after.Extra)
+	assert.True(t, after.LastModified > before.LastModified)
+}
+
+func TestPUT_Ventures_8(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given a dead Venture references some Orders
+		And one of those Orders has since died
+		When the dead Venture is modified to be not dead
+		Ensure the response code is 200
+		And the restored Venture no longer references the dead Order
+	`)
+
+	vtest.SetupEmptyTest()
+	defer vtest.TearDown()
+
+	vtest.DBInjectOrders()
+	vtest.Inject(ventures.NewVenture{
+		Description: "White wizard",
+		State:       "Not started",
+		Orders:      "1,2,3",
+	})
+
+	killVenture(t, "1")
+
+	mo := orders.ModOrder{
+		IDs:   "2",
+		Props: "dead",
+		Values: orders.Order{
+			Dead: true,
+		},
+	}
+	_, ok := mo.Update(test.DB())
+	require.True(t, ok, "Expected Order to be killed")
+
+	res := putMod(ventures.ModVenture{
+		IDs:   "1",
+		Props: "dead",
+		Values: ventures.Venture{
+			Dead: false,
+		},
+	})
+	defer res.Body.Close()
+
+	require.Equal(t, 200, res.StatusCode)
+	test.PrintBody(t, res)
+
+	assert.Equal(t, "1,3", vtest.DBQueryOne("1").Orders)
+}
+
+// killVenture kills the Venture with the specified ID directly within the
+// database.
+func killVenture(t *testing.T, id string) {
+	time.Sleep(2 * time.Millisecond)
+	mv := ventures.ModVenture{
+		IDs:   id,
+		Props: "dead",
+		Values: ventures.Venture{
+			Dead: true,
+		},
+	}
+	_, err := vtest.Store().Modify(&mv)
+	require.Nil(t, err, "Expected Venture to be killed")
+	time.Sleep(2 * time.Millisecond)
+}
+
+// putMod PUTs the Venture modification 'mv' to the server.
+func putMod(mv ventures.ModVenture) *http.Response {
+	buf := new(bytes.Buffer)
+	json.NewEncoder(buf).Encode(&mv)
+
+	req := test.APICall{
+		URL:    test.Host + "/ventures",
+		Method: "PUT",
+		Body:   buf,
+	}
+	return req.Fire()
+}
+
+// ****************************************************************************
+// (PUT) /ventures (workflow)
+// ****************************************************************************
+
+func TestPUT_Ventures_9(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Ventures already exist on the server
+		When a Venture is modified to a state the workflow does not allow it to
+		move to from its current state
+		Ensure the response code is 400
+		And the error message lists the transitions that are not allowed
+		And the Venture is unchanged
+	`)
+
+	vtest.SetupTest()
+	defer vtest.TearDown()
+
+	before := vtest.DBQueryOne("1")
+	require.Equal(t, "Not started", before.State)
+
+	res := putMod(ventures.ModVenture{
+		IDs:   "1",
+		Props: "state",
+		Values: ventures.Venture{
+			State: "Finished",
+		},
+	})
+	defer res.Body.Close()
+
+	require.Equal(t, 400, res.StatusCode)
+	test.AssertDefaultHeaders(t, res, "application/problem+json", "GET, POST, PUT, DELETE, OPTIONS")
+
+	reply := test.AssertErrorBody(t, test.PrintBody(t, res))
+	assert.Contains(t, reply.Detail, "1: Not started -> Finished")
+	require.Len(t, reply.Errors, 1)
+	assert.Equal(t, "values.state", reply.Errors[0].Field)
+	assert.Equal(t, "transition", reply.Errors[0].Code)
+	assert.Equal(t, before, vtest.DBQueryOne("1"))
+}
+
+func TestPUT_Ventures_10(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Ventures already exist on the server
+		When a Venture is modified to a state outside the workflow
+		Ensure the response code is 400
+		And the Venture is unchanged
+	`)
+
+	vtest.SetupTest()
+	defer vtest.TearDown()
+
+	before := vtest.DBQueryOne("2")
+
+	res := putMod(ventures.ModVenture{
+		IDs:   "2",
+		Props: "state",
+		Values: ventures.Venture{
+			State: "wip",
+		},
+	})
+	defer res.Body.Close()
+
+	require.Equal(t, 400, res.StatusCode)
+	test.AssertErrorBody(t, test.PrintBody(t, res))
+	assert.Equal(t, before, vtest.DBQueryOne("2"))
+}
+
+// ****************************************************************************
+// (PUT) /ventures (optimistic concurrency)
+// ****************************************************************************
+
+func TestPUT_Ventures_11(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Ventures already exist on the server
+		And a client has read one of them along with its ETag
+		When the client PUTs a modification with the ETag in an 'If-Match' header
+		Ensure the response code is 200
+		And the response includes the ETag of the new revision
+		When the client PUTs another modification with the old ETag
+		Ensure the response code is 412
+		And the problem body contains the current revision of the Venture
+		And the Venture is unchanged
+	`)
+
+	vtest.SetupTest()
+	defer vtest.TearDown()
+
+	etag := ventures.ETag([]ventures.Venture{vtest.DBQueryOne("1")})
+	time.Sleep(2 * time.Millisecond)
+
+	res := putModIfMatch(ventures.ModVenture{
+		IDs:    "1",
+		Props:  "description",
+		Values: ventures.Venture{Description: "Black blizzard"},
+	}, etag)
+	defer res.Body.Close()
+
+	require.Equal(t, 200, res.StatusCode)
+	test.PrintBody(t, res)
+
+	after := vtest.DBQueryOne("1")
+	assert.Equal(t, "Black blizzard", after.Description)
+	assert.Equal(t, ventures.ETag([]ventures.Venture{after}), res.Header.Get("ETag"))
+
+	time.Sleep(2 * time.Millisecond)
+
+	res = putModIfMatch(ventures.ModVenture{
+		IDs:    "1",
+		Props:  "description",
+		Values: ventures.Venture{Description: "Red lizard"},
+	}, etag)
+	defer res.Body.Close()
+
+	require.Equal(t, 412, res.StatusCode)
+
+	_, current := ventures.AssertConflictFromReader(t, test.PrintBody(t, res))
+	require.Len(t, current, 1)
+	assert.Equal(t, after, current[0])
+	assert.Equal(t, ventures.ETag(current), res.Header.Get("ETag"))
+	assert.Equal(t, after, vtest.DBQueryOne("1"))
+}
+
+func TestPUT_Ventures_12(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Ventures already exist on the server
+		When a modification is PUT expecting an out of date 'last_modified' for
+		one of the Ventures
+		Ensure the response code is 409
+		And the problem body contains the current revision of each Venture
+		And none of the Ventures have changed
+		When the modification is PUT expecting the current 'last_modified'
+		Ensure the response code is 200
+	`)
+
+	vtest.SetupTest()
+	defer vtest.TearDown()
+
+	one := vtest.DBQueryOne("1")
+	two := vtest.DBQueryOne("2")
+	time.Sleep(2 * time.Millisecond)
+
+	mv := ventures.ModVenture{
+		IDs:    "1,2",
+		Props:  "extra",
+		Values: ventures.Venture{Extra: "colour: black"},
+		LastModified: map[string]int64{
+			"1": one.LastModified,
+			"2": two.LastModified - 1,
+		},
+	}
+
+	res := putMod(mv)
+	defer res.Body.Close()
+
+	require.Equal(t, 409, res.StatusCode)
+
+	_, current := ventures.AssertConflictFromReader(t, test.PrintBody(t, res))
+	ventures.AssertVenturesEqual(t, []ventures.Venture{one, two}, current, true)
+	assert.Equal(t, one, vtest.DBQueryOne("1"))
+	assert.Equal(t, two, vtest.DBQueryOne("2"))
+
+	mv.LastModified["2"] = two.LastModified
+
+	res = putMod(mv)
+	defer res.Body.Close()
+
+	require.Equal(t, 200, res.StatusCode)
+	test.PrintBody(t, res)
+	assert.Equal(t, "colour: black", vtest.DBQueryOne("2").Extra)
+}
+
+// putModIfMatch PUTs the Venture modification 'mv' to the server with the
+// 'If-Match' header 'etag'.
+func putModIfMatch(mv ventures.ModVenture, etag string) *http.Response {
+	buf := new(bytes.Buffer)
+	json.NewEncoder(buf).Encode(&mv)
+
+	req := test.APICall{
+		URL:    test.Host + "/ventures",
+		Method: "PUT",
+		Body:   buf,
+		Header: http.Header{
+			"If-Match": []string{etag},
+		},
+	}
+	return req.Fire()
+}
+
+// ****************************************************************************
+// (PUT) /ventures (unknown IDs)
+// ****************************************************************************
+
+func TestPUT_Ventures_13(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Ventures already exist on the server
+		When a modification of existing and non-existent Ventures is PUT
+		Ensure the response code is 404
+		And the message lists the IDs that could not be found
+		And none of the Ventures have changed
+	`)
+
+	vtest.SetupTest()
+	defer vtest.TearDown()
+
+	before := vtest.DBQueryAll()
+
+	res := putMod(ventures.ModVenture{
+		IDs:    "1,888888,2,999999",
+		Props:  "extra",
+		Values: ventures.Venture{Extra: "colour: black"},
+	})
+	defer res.Body.Close()
+
+	require.Equal(t, 404, res.StatusCode)
+
+	wr := test.AssertErrorBody(t, test.PrintBody(t, res))
+	assert.Contains(t, wr.Detail, "'888888, 999999'")
+	assert.Len(t, wr.Errors, 2)
+	assert.Equal(t, before, vtest.DBQueryAll())
+}
+
+func TestPUT_Ventures_14(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given some Ventures already exist on the server
+		When a modification of existing and non-existent Ventures is PUT with
+		'strict' disabled
+		Ensure the response code is 200
+		And only the existing Ventures have changed
+		And the message lists the IDs that could not be found
+	`)
+
+	vtest.SetupTest()
+	defer vtest.TearDown()
+
+	mv := ventures.ModVenture{
+		IDs:    "1,999999",
+		Props:  "extra",
+		Values: ventures.Venture{Extra: "colour: black"},
+	}
+	buf := new(bytes.Buffer)
+	json.NewEncoder(buf).Encode(&mv)
+
+	req := test.APICall{
+		URL:    test.Host + "/ventures?strict=false&wrap",
+		Method: "PUT",
+		Body:   buf,
+	}
+	res := req.Fire()
+	defer res.Body.Close()
+
+	require.Equal(t, 200, res.StatusCode)
+
+	wr, out := ventures.AssertWrappedVentureSliceFromReader(t, test.PrintBody(t, res))
+	require.Len(t, out, 1)
+	assert.Equal(t, "1", out[0].ID)
+	assert.Equal(t, "colour: black", vtest.DBQueryOne("1").Extra)
+	assert.Contains(t, wr.Message, "'999999'")
+}
+
+// ****************************************************************************
+// (PUT) /ventures (legacy states)
+// ****************************************************************************
+
+func TestPUT_Ventures_15(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given Ventures stored, before the workflow was adopted, with states
+		spelt differently to the workflow or outside it entirely
+		When each is modified to a state the workflow allows
+		Ensure the response code is 200
+		And a differently spelt state is treated as its workflow state
+		And a state outside the workflow may move to any workflow state
+	`)
+
+	vtest.SetupEmptyTest()
+	defer vtest.TearDown()
+
+	for i, state := range []string{"in-progress", "wip"} {
+		_, err := test.DB().Exec(`INSERT INTO venture (
+			id, last_modified, description, order_ids, state, is_dead
+		) VALUES (
+			$1, $2, $3, '', $4, $5
+		);`, i+1, 1000, "Legacy "+state, state, false)
+		require.Nil(t, err)
+	}
+
+	res := putMod(ventures.ModVenture{
+		IDs:   "1,2",
+		Props: "state",
+		Values: ventures.Venture{
+			State: "Finished",
+		},
+	})
+	defer res.Body.Close()
+
+	require.Equal(t, 200, res.StatusCode)
+	test.PrintBody(t, res)
+
+	assert.Equal(t, "Finished", vtest.DBQueryOne("1").State)
+	assert.Equal(t, "Finished", vtest.DBQueryOne("2").State)
+}
@@ -49,7 +49,7 @@ func TestPUT_Ventures_1_OLD(t *testing.T) {
 	json.NewEncoder(buf).Encode(&input)
 
 	req := test.APICall{
-		URL:    "http://localhost:8080/ventures",
+		URL:    test.Host + "/ventures",
 		Method: "PUT",
 		Body:   buf,
 	}
@@ -76,7 +76,7 @@ func TestPUT_Ventures_1_OLD(t *testing.T) {
 
 func TestPUT_Ventures_2_OLD(t *testing.T) {
 	t.Log(`Given some Ventures already exist on the server
-		When an non-existent Venture is PUT to the server
+		When an non-existent Venture is PUT to the server with 'strict' disabled
 		Then ensure the response code is 200
 		And the 'Content-Type' header contains 'application/json'
 		And 'Access-Control-Allow-Origin' is '*'
@@ -102,7 +102,7 @@ func TestPUT_Ventures_2_OLD(t *testing.T) {
 	json.NewEncoder(buf).Encode(&input)
 
 	req := test.APICall{
-		URL:    "http://localhost:8080/ventures",
+		URL:    test.Host + "/ventures?strict=false",
 		Method: "PUT",
 		Body:   buf,
 	}
@@ -145,7 +145,7 @@ func TestPUT_Ventures_3_OLD(t *testing.T) {
 	json.NewEncoder(buf).Encode(&input)
 
 	req := test.APICall{
-		URL:    "http://localhost:8080/ventures",
+		URL:    test.Host + "/ventures",
 		Method: "PUT",
 		Body:   buf,
 	}
@@ -155,7 +155,7 @@ func TestPUT_Ventures_3_OLD(t *testing.T) {
 	defer test.PrintResponse(t, res.Body)
 
 	require.Equal(t, 400, res.StatusCode)
-	test.AssertDefaultHeaders(t, res, "application/json", "GET, POST, PUT, DELETE, OPTIONS")
+	test.AssertDefaultHeaders(t, res, "application/problem+json", "GET, POST, PUT, DELETE, OPTIONS")
 	test.AssertErrorBody(t, res.Body)
 }
 
@@ -183,7 +183,7 @@ func TestPUT_Ventures_4_OLD(t *testing.T) {
 	json.NewEncoder(buf).Encode(&input)
 
 	req := test.APICall{
-		URL:    "http://localhost:8080/ventures",
+		URL:    test.Host + "/ventures",
 		Method: "PUT",
 		Body:   buf,
 	}
@@ -193,7 +193,7 @@ func TestPUT_Ventures_4_OLD(t *testing.T) {
 	defer test.PrintResponse(t, res.Body)
 
 	require.Equal(t, 400, res.StatusCode)
-	test.AssertDefaultHeaders(t, res, "application/json", "GET, POST, PUT, DELETE, OPTIONS")
+	test.AssertDefaultHeaders(t, res, "application/problem+json", "GET, POST, PUT, DELETE, OPTIONS")
 	test.AssertErrorBody(t, res.Body)
 }
 
@@ -224,7 +224,7 @@ func TestPUT_Ventures_5_OLD(t *testing.T) {
 	json.NewEncoder(buf).Encode(&input)
 
 	req := test.APICall{
-		URL:    "http://localhost:8080/ventures",
+		URL:    test.Host + "/ventures",
 		Method: "PUT",
 		Body:   buf,
 	}
@@ -278,7 +278,7 @@ func TestPUT_Ventures_6_OLD(t *testing.T) {
 	json.NewEncoder(buf).Encode(&input)
 
 	req := test.APICall{
-		URL:    "http://localhost:8080/ventures?wrap",
+		URL:    test.Host + "/ventures?wrap",
 		Method: "PUT",
 		Body:   buf,
 	}
@@ -0,0 +1,824 @@
+package store
+
+import (
+	"database/sql"
+	"math"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	database "github.com/PaulioRandall/go-qlueless-api/api/database"
+	orders "github.com/PaulioRandall/go-qlueless-api/api/orders"
+	ventures "github.com/PaulioRandall/go-qlueless-api/api/ventures"
+	workflow "github.com/PaulioRandall/go-qlueless-api/api/workflow"
+	test "github.com/PaulioRandall/go-qlueless-api/test"
+	vtest "github.com/PaulioRandall/go-qlueless-api/test/ventures"
+	assert "github.com/stretchr/testify/assert"
+	require "github.com/stretchr/testify/require"
+)
+
+func init() {
+	test.SetWorkingDir("../../../bin")
+}
+
+// forEachStore runs 'f' against each VentureStore implementation.
+func forEachStore(t *testing.T, f func(*testing.T, ventures.VentureStore)) {
+	t.Run("SQL", func(t *testing.T) {
+		vtest.SetupEmptyTest()
+		defer vtest.TearDown()
+		f(t, vtest.Store())
+	})
+
+	t.Run("Memory", func(t *testing.T) {
+		f(t, ventures.NewMemStore(&workflow.Default))
+	})
+}
+
+// create creates a new Venture within 's' failing the test on error. A short
+// sleep follows so the next revision is not made within the same millisecond.
+func create(t *testing.T, s ventures.VentureStore, desc string, state string) ventures.Venture {
+	ven, err := s.Create(&ventures.NewVenture{
+		Description: desc,
+		State:       state,
+	})
+	require.Nil(t, err)
+	require.NotNil(t, ven)
+
+	time.Sleep(2 * time.Millisecond)
+	return *ven
+}
+
+// injectOrders makes the Orders with the IDs within 'ids' living within 's'.
+// SQL stores are given new Orders which must be assigned the same IDs.
+func injectOrders(s ventures.VentureStore, ids ...string) {
+	if st, ok := s.(*ventures.MemStore); ok {
+		st.SetOrders(ids...)
+		return
+	}
+
+	for _, id := range ids {
+		o := vtest.InjectOrder(orders.NewOrder{
+			Description: "Order " + id,
+			State:       "Not started",
+		})
+		if o.ID != id {
+			panic("Order assigned an unexpected ID '" + o.ID + "'")
+		}
+	}
+}
+
+// killOrder kills the living Order with the ID 'id' within the database
+// without pruning it from the Ventures that reference it.
+func killOrder(id string) {
+	mo := orders.ModOrder{
+		IDs:   id,
+		Props: "dead",
+		Values: orders.Order{
+			Dead: true,
+		},
+	}
+	if _, ok := mo.Update(test.DB()); !ok {
+		panic("Already printed above!")
+	}
+}
+
+// ****************************************************************************
+// VentureStore.Create() & VentureStore.Get()
+// ****************************************************************************
+
+func TestStore_Create(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given an empty store
+		When Ventures are created
+		Ensure each is assigned the next free ID
+		And each may be got by its ID
+	`)
+
+	forEachStore(t, func(t *testing.T, s ventures.VentureStore) {
+		a := create(t, s, "White wizard", "Not started")
+		b := create(t, s, "Green lizard", "In progress")
+		assert.Equal(t, "1", a.ID)
+		assert.Equal(t, "2", b.ID)
+
+		ven, err := s.Get("2")
+		require.Nil(t, err)
+		require.NotNil(t, ven)
+		assert.Equal(t, "Green lizard", ven.Description)
+
+		ven, err = s.Get("99")
+		require.Nil(t, err)
+		assert.Nil(t, ven)
+	})
+}
+
+func TestStore_CreateAll(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given a store with a Venture
+		When many Ventures are created at once
+		Ensure each is assigned the next free ID
+		And they are returned in the order given
+	`)
+
+	forEachStore(t, func(t *testing.T, s ventures.VentureStore) {
+		create(t, s, "White wizard", "Not started")
+
+		vens, err := s.CreateAll([]ventures.NewVenture{
+			ventures.NewVenture{Description: "Green lizard", State: "In progress"},
+			ventures.NewVenture{Description: "Pink gizzard", State: "Finished"},
+		})
+		require.Nil(t, err)
+		require.Len(t, vens, 2)
+		assert.Equal(t, "2", vens[0].ID)
+		assert.Equal(t, "Green lizard", vens[0].Description)
+		assert.Equal(t, "3", vens[1].ID)
+		assert.Equal(t, "Pink gizzard", vens[1].Description)
+
+		all, err := s.List(nil)
+		require.Nil(t, err)
+		assert.Len(t, all, 3)
+	})
+}
+
+// ****************************************************************************
+// VentureStore.List()
+// ****************************************************************************
+
+func TestStore_List(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given a store with some living and dead Ventures
+		When Ventures are listed
+		Ensure only living Ventures are returned
+		And only those specified if any IDs are given
+	`)
+
+	forEachStore(t, func(t *testing.T, s ventures.VentureStore) {
+		create(t, s, "White wizard", "Not started")
+		create(t, s, "Green lizard", "In progress")
+		create(t, s, "Pink gizzard", "Finished")
+
+		_, err := s.Kill([]string{"2"})
+		require.Nil(t, err)
+
+		all, err := s.List(nil)
+		require.Nil(t, err)
+		require.Len(t, all, 2)
+		assert.Equal(t, "1", all[0].ID)
+		assert.Equal(t, "3", all[1].ID)
+
+		some, err := s.List([]string{"2", "3"})
+		require.Nil(t, err)
+		require.Len(t, some, 1)
+		assert.Equal(t, "3", some[0].ID)
+	})
+}
+
+// ****************************************************************************
+// VentureStore.Find()
+// ****************************************************************************
+
+func TestStore_Find(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given a store with some living and dead Ventures
+		When Ventures are found by a Query
+		Ensure only living Ventures matching every criteria are returned
+		And they are returned in the order specified
+	`)
+
+	forEachStore(t, func(t *testing.T, s ventures.VentureStore) {
+		create(t, s, "White wizard", "In progress")
+		create(t, s, "Green lizard", "In progress")
+		create(t, s, "Pink WIZARD", "In progress")
+		create(t, s, "Blue wizard", "Not started")
+		create(t, s, "Grey wizard", "In progress")
+
+		_, err := s.Kill([]string{"5"})
+		require.Nil(t, err)
+
+		vens, err := s.Find(&ventures.Query{
+			States: []string{"In progress"},
+			Search: "wizard",
+			Sort:   []ventures.SortKey{{Prop: "id", Desc: true}},
+		})
+		require.Nil(t, err)
+		require.Len(t, vens, 2)
+		assert.Equal(t, "3", vens[0].ID)
+		assert.Equal(t, "1", vens[1].ID)
+
+		vens, err = s.Find(&ventures.Query{
+			Sort: []ventures.SortKey{{Prop: "description"}},
+		})
+		require.Nil(t, err)
+		require.Len(t, vens, 4)
+		assert.Equal(t, "4", vens[0].ID)
+		assert.Equal(t, "2", vens[1].ID)
+		assert.Equal(t, "3", vens[2].ID)
+		assert.Equal(t, "1", vens[3].ID)
+	})
+}
+
+func TestStore_FindOrder(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given a store with more than nine Ventures
+		When Ventures are found ordered by ID or state
+		Ensure IDs are ordered numerically
+		And states are ordered as they are within the workflow
+		And the same order is given by sorting with the comparators
+	`)
+
+	forEachStore(t, func(t *testing.T, s ventures.VentureStore) {
+		states := []string{"Finished", "Not started", "In progress"}
+		for i := 0; i < 11; i++ {
+			create(t, s, "Wizard", states[i%len(states)])
+		}
+
+		ids := func(vens []ventures.Venture) []string {
+			r := []string{}
+			for _, ven := range vens {
+				r = append(r, ven.ID)
+			}
+			return r
+		}
+
+		vens, err := s.Find(&ventures.Query{})
+		require.Nil(t, err)
+		assert.Equal(t, []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11"}, ids(vens))
+
+		sort.Sort(ventures.ByVenID(vens))
+		assert.Equal(t, []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11"}, ids(vens))
+
+		vens, err = s.Find(&ventures.Query{
+			Sort: []ventures.SortKey{{Prop: "state"}, {Prop: "id", Desc: true}},
+		})
+		require.Nil(t, err)
+		assert.Equal(t, []string{"11", "8", "5", "2", "9", "6", "3", "10", "7", "4", "1"}, ids(vens))
+
+		sorted := append([]ventures.Venture{}, vens...)
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return ventures.CompareID(&sorted[i], &sorted[j]) < 0
+		})
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return ventures.CompareState(&workflow.Default)(&sorted[i], &sorted[j]) < 0
+		})
+		assert.Equal(t, []string{"2", "5", "8", "11", "3", "6", "9", "1", "4", "7", "10"}, ids(sorted))
+	})
+}
+
+func TestStore_FindOrderByDescription(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given a store with Ventures whose descriptions differ in case and
+		punctuation
+		When Ventures are found ordered by description
+		Ensure descriptions are ordered byte-wise
+		And the same order is given by sorting with the comparators
+	`)
+
+	forEachStore(t, func(t *testing.T, s ventures.VentureStore) {
+		for _, desc := range []string{"apple", "_cherry", "Banana", "Apple"} {
+			create(t, s, desc, "Not started")
+		}
+
+		descs := func(vens []ventures.Venture) []string {
+			r := []string{}
+			for _, ven := range vens {
+				r = append(r, ven.Description)
+			}
+			return r
+		}
+
+		vens, err := s.Find(&ventures.Query{
+			Sort: []ventures.SortKey{{Prop: "description"}},
+		})
+		require.Nil(t, err)
+		assert.Equal(t, []string{"Apple", "Banana", "_cherry", "apple"}, descs(vens))
+
+		sorted := append([]ventures.Venture{}, vens...)
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return ventures.CompareID(&sorted[i], &sorted[j]) < 0
+		})
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return ventures.CompareDescription(&sorted[i], &sorted[j]) < 0
+		})
+		assert.Equal(t, descs(vens), descs(sorted))
+	})
+}
+
+// ****************************************************************************
+// VentureStore.Find() (paged) & VentureStore.Count()
+// ****************************************************************************
+
+func TestStore_FindPaged(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given a store with some living and dead Ventures
+		When a page of Ventures is found by a Query with a Limit and Cursor
+		Ensure no more than Limit Ventures are returned
+		And they are those after, or before, the Cursor by last modified time
+		And counting ignores the Limit and Cursor
+	`)
+
+	forEachStore(t, func(t *testing.T, s ventures.VentureStore) {
+		vens := []ventures.Venture{}
+		for _, d := range []string{"White", "Green", "Pink", "Blue", "Grey"} {
+			vens = append(vens, create(t, s, d+" wizard", "In progress"))
+		}
+
+		_, err := s.Kill([]string{"5"})
+		require.Nil(t, err)
+
+		ids := func(vens []ventures.Venture) []string {
+			r := []string{}
+			for _, ven := range vens {
+				r = append(r, ven.ID)
+			}
+			return r
+		}
+
+		page, err := s.Find(&ventures.Query{Limit: 3})
+		require.Nil(t, err)
+		assert.Equal(t, []string{"1", "2", "3"}, ids(page))
+
+		c := ventures.After(&vens[1])
+		page, err = s.Find(&ventures.Query{Limit: 3, Cursor: &c})
+		require.Nil(t, err)
+		assert.Equal(t, []string{"3", "4"}, ids(page))
+
+		c = ventures.Before(&vens[3])
+		page, err = s.Find(&ventures.Query{Limit: 2, Cursor: &c})
+		require.Nil(t, err)
+		assert.Equal(t, []string{"2", "3"}, ids(page))
+
+		n, err := s.Count(&ventures.Query{Limit: 1, Cursor: &c})
+		require.Nil(t, err)
+		assert.Equal(t, 4, n)
+	})
+}
+
+// ****************************************************************************
+// VentureStore.Modify()
+// ****************************************************************************
+
+func TestStore_Modify(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given a store with some Ventures
+		When a Venture is modified
+		Ensure only the properties set are changed
+		And the new revision is returned
+	`)
+
+	forEachStore(t, func(t *testing.T, s ventures.VentureStore) {
+		create(t, s, "White wizard", "Not started")
+
+		vens, err := s.Modify(&ventures.ModVenture{
+			IDs:   "1",
+			Props: "state",
+			Values: ventures.Venture{
+				Description: "Ignored",
+				State:       "In progress",
+			},
+		})
+		require.Nil(t, err)
+		require.Len(t, vens, 1)
+		assert.Equal(t, "In progress", vens[0].State)
+
+		ven, err := s.Get("1")
+		require.Nil(t, err)
+		assert.Equal(t, "White wizard", ven.Description)
+		assert.Equal(t, "In progress", ven.State)
+	})
+}
+
+func TestStore_ModifyRapidly(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given a store with a Venture
+		When it is created, modified, and killed without pause, i.e. possibly
+		within the same millisecond
+		Ensure every change succeeds
+		And each revision has a later last modified time than the one before
+	`)
+
+	forEachStore(t, func(t *testing.T, s ventures.VentureStore) {
+		ven, err := s.Create(&ventures.NewVenture{
+			Description: "White wizard",
+			State:       "Not started",
+		})
+		require.Nil(t, err)
+
+		last := ven.LastModified
+		for _, state := range []string{"In progress", "Finished", "Closed"} {
+			vens, err := s.Modify(&ventures.ModVenture{
+				IDs:    ven.ID,
+				Props:  "state",
+				Values: ventures.Venture{State: state},
+			})
+			require.Nil(t, err)
+			require.Len(t, vens, 1)
+			assert.True(t, vens[0].LastModified > last)
+			last = vens[0].LastModified
+		}
+
+		vens, err := s.Kill([]string{ven.ID})
+		require.Nil(t, err)
+		require.Len(t, vens, 1)
+		assert.True(t, vens[0].LastModified > last)
+
+		revs, err := s.History([]string{ven.ID}, 0, math.MaxInt64)
+		require.Nil(t, err)
+		assert.Len(t, revs, 5)
+	})
+}
+
+func TestStore_MissingOrders(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given a store with a living Order
+		When Ventures referencing Orders that aren't living are created or
+		modified to reference them
+		Ensure an *InvalidError is returned naming the missing Orders
+		And nothing is written
+		And Ventures referencing only living Orders are written
+	`)
+
+	forEachStore(t, func(t *testing.T, s ventures.VentureStore) {
+		injectOrders(s, "1")
+
+		_, err := s.Create(&ventures.NewVenture{
+			Description: "White wizard",
+			Orders:      "1,2",
+			State:       "Not started",
+		})
+		requireInvalid(t, err, "orders", "not_found", "'2'")
+
+		_, err = s.CreateAll([]ventures.NewVenture{
+			{Description: "White wizard", Orders: "1", State: "Not started"},
+			{Description: "Black blizzard", Orders: "3", State: "Not started"},
+		})
+		requireInvalid(t, err, "[1].orders", "not_found", "'3'")
+
+		vens, err := s.List(nil)
+		require.Nil(t, err)
+		require.Empty(t, vens)
+
+		ven, err := s.Create(&ventures.NewVenture{
+			Description: "White wizard",
+			Orders:      "1",
+			State:       "Not started",
+		})
+		require.Nil(t, err)
+
+		_, err = s.Modify(&ventures.ModVenture{
+			IDs:    ven.ID,
+			Props:  "orders",
+			Values: ventures.Venture{Orders: "1,2"},
+		})
+		requireInvalid(t, err, "orders", "not_found", "'2'")
+
+		after, err := s.Get(ven.ID)
+		require.Nil(t, err)
+		assert.Equal(t, *ven, *after)
+	})
+}
+
+// requireInvalid requires 'err' to be an *InvalidError holding a single
+// Violation of the field 'field', with the code 'code', whose message contains
+// 'msg'.
+func requireInvalid(t *testing.T, err error, field string, code string, msg string) {
+	ie, ok := err.(*ventures.InvalidError)
+	require.True(t, ok, "Expected *InvalidError, got %v", err)
+	require.Len(t, ie.Violations, 1)
+	assert.Equal(t, field, ie.Violations[0].Field)
+	assert.Equal(t, code, ie.Violations[0].Code)
+	assert.Contains(t, ie.Violations[0].Message, msg)
+}
+
+func TestStore_Restore(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given a store with a dead Venture that references a living and a dead
+		Order
+		When the Venture is restored
+		Ensure the Venture is living again
+		And only the dead Order has been pruned from it
+	`)
+
+	forEachStore(t, func(t *testing.T, s ventures.VentureStore) {
+		injectOrders(s, "1", "2")
+
+		ven, err := s.Create(&ventures.NewVenture{
+			Description: "White wizard",
+			Orders:      "1,2",
+			State:       "Not started",
+		})
+		require.Nil(t, err)
+		time.Sleep(2 * time.Millisecond)
+
+		_, err = s.Kill([]string{ven.ID})
+		require.Nil(t, err)
+		time.Sleep(2 * time.Millisecond)
+
+		switch st := s.(type) {
+		case *ventures.MemStore:
+			st.SetOrders("1")
+		default:
+			killOrder("2")
+		}
+
+		vens, err := s.Modify(&ventures.ModVenture{
+			IDs:   ven.ID,
+			Props: "dead",
+		})
+		require.Nil(t, err)
+		require.Len(t, vens, 1)
+		assert.False(t, vens[0].Dead)
+		assert.Equal(t, "1", vens[0].Orders)
+	})
+}
+
+func TestStore_Transitions(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given a store with a living and a dead Venture
+		When either is modified, or restored, to a state the workflow does not
+		allow it to move to from its current state
+		Ensure an *InvalidError is returned naming the transition
+		And nothing is written
+	`)
+
+	forEachStore(t, func(t *testing.T, s ventures.VentureStore) {
+		living := create(t, s, "White wizard", "Not started")
+		dead := create(t, s, "Black blizzard", "Not started")
+
+		_, err := s.Kill([]string{dead.ID})
+		require.Nil(t, err)
+
+		_, err = s.Modify(&ventures.ModVenture{
+			IDs:    living.ID,
+			Props:  "state",
+			Values: ventures.Venture{State: "Finished"},
+		})
+		requireInvalid(t, err, "state", "transition", living.ID+": Not started -> Finished")
+
+		_, err = s.Modify(&ventures.ModVenture{
+			IDs:    dead.ID,
+			Props:  "dead,state",
+			Values: ventures.Venture{State: "Finished"},
+		})
+		requireInvalid(t, err, "state", "transition", dead.ID+": Not started -> Finished")
+
+		vens, err := s.List(nil)
+		require.Nil(t, err)
+		require.Len(t, vens, 1)
+		assert.Equal(t, living, vens[0])
+	})
+}
+
+// pruneOrders prunes the Orders with the IDs within 'ids' from 's', within a
+// transaction on the database if 's' is backed by one.
+func pruneOrders(s ventures.VentureStore, ids ...string) error {
+	if _, ok := s.(*ventures.MemStore); ok {
+		return s.PruneOrders(nil, ids)
+	}
+
+	return database.InTx(test.DB(), func(tx *sql.Tx) error {
+		return s.PruneOrders(tx, ids)
+	})
+}
+
+func TestStore_PruneOrders(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given a store with Ventures referencing Orders
+		When Orders are pruned
+		Ensure they are removed from every living Venture referencing them
+		And Ventures not referencing them are unchanged
+	`)
+
+	forEachStore(t, func(t *testing.T, s ventures.VentureStore) {
+		injectOrders(s, "1", "2")
+
+		pruned, err := s.Create(&ventures.NewVenture{
+			Description: "White wizard",
+			Orders:      "1,2",
+			State:       "Not started",
+		})
+		require.Nil(t, err)
+
+		untouched, err := s.Create(&ventures.NewVenture{
+			Description: "Black blizzard",
+			Orders:      "1",
+			State:       "Not started",
+		})
+		require.Nil(t, err)
+		time.Sleep(2 * time.Millisecond)
+
+		err = pruneOrders(s, "2")
+		require.Nil(t, err)
+
+		after, err := s.Get(pruned.ID)
+		require.Nil(t, err)
+		assert.Equal(t, "1", after.Orders)
+		assert.True(t, after.LastModified > pruned.LastModified)
+
+		after, err = s.Get(untouched.ID)
+		require.Nil(t, err)
+		assert.Equal(t, *untouched, *after)
+	})
+}
+
+// ****************************************************************************
+// VentureStore.History() & VentureStore.AsOf()
+// ****************************************************************************
+
+func TestStore_History(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given a store with a Venture that has been modified then killed
+		When the history of the Venture is requested
+		Ensure every revision is returned oldest first
+		And the Venture as of before it was killed is living
+	`)
+
+	forEachStore(t, func(t *testing.T, s ventures.VentureStore) {
+		create(t, s, "White wizard", "Not started")
+
+		mod, err := s.Modify(&ventures.ModVenture{
+			IDs:    "1",
+			Props:  "state",
+			Values: ventures.Venture{State: "In progress"},
+		})
+		require.Nil(t, err)
+		time.Sleep(2 * time.Millisecond)
+
+		_, err = s.Kill([]string{"1"})
+		require.Nil(t, err)
+
+		revs, err := s.History([]string{"1"}, 0, math.MaxInt64)
+		require.Nil(t, err)
+		require.Len(t, revs, 3)
+		assert.Equal(t, "Not started", revs[0].State)
+		assert.Equal(t, "In progress", revs[1].State)
+		assert.True(t, revs[2].Dead)
+
+		vens, err := s.AsOf(nil, revs[1].LastModified)
+		require.Nil(t, err)
+		require.Len(t, vens, 1)
+		assert.Equal(t, mod[0].State, vens[0].State)
+
+		vens, err = s.AsOf(nil, math.MaxInt64)
+		require.Nil(t, err)
+		assert.Empty(t, vens)
+	})
+}
+
+// ****************************************************************************
+// SQLStore
+// ****************************************************************************
+
+// failInsertsOf creates a trigger within the database of the server under test
+// that fails every insert of a revision of the Venture with the ID 'id'.
+func failInsertsOf(t *testing.T, id string) {
+	stmts := []string{`CREATE TRIGGER fail_insert
+		BEFORE INSERT ON venture
+		FOR EACH ROW
+		WHEN (NEW.id = ` + id + `)
+		BEGIN
+			SELECT RAISE(ABORT, 'Insert failed on purpose');
+		END;`}
+
+	if test.Dialect() == database.Postgres {
+		stmts = []string{`CREATE FUNCTION fail_insert() RETURNS TRIGGER AS $$
+			BEGIN
+				RAISE EXCEPTION 'Insert failed on purpose';
+			END;
+		$$ LANGUAGE plpgsql;`,
+			`CREATE TRIGGER fail_insert
+			BEFORE INSERT ON venture
+			FOR EACH ROW
+			WHEN (NEW.id = ` + id + `)
+			EXECUTE PROCEDURE fail_insert();`}
+	}
+
+	for _, s := range stmts {
+		_, err := test.DB().Exec(s)
+		require.Nil(t, err)
+	}
+}
+
+func TestSQLStore_ModifyAtomic(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given a SQL store with some Ventures
+		When a modification of many Ventures fails part way through
+		Ensure an error is returned
+		And none of the Ventures have been modified
+	`)
+
+	vtest.SetupEmptyTest()
+	defer vtest.TearDown()
+
+	s := vtest.Store()
+	for i := 0; i < 5; i++ {
+		create(t, s, "White wizard", "Not started")
+	}
+
+	failInsertsOf(t, "3")
+
+	vens, err := s.Modify(&ventures.ModVenture{
+		IDs:    "1,2,3,4,5",
+		Props:  "state",
+		Values: ventures.Venture{State: "In progress"},
+	})
+	assert.NotNil(t, err)
+	assert.Nil(t, vens)
+
+	all, err := s.List(nil)
+	require.Nil(t, err)
+	require.Len(t, all, 5)
+	for _, ven := range all {
+		assert.Equal(t, "Not started", ven.State)
+	}
+
+	revs, err := s.History(nil, 0, math.MaxInt64)
+	require.Nil(t, err)
+	assert.Len(t, revs, 5)
+}
+
+func TestSQLStore_CreateAllAtomic(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given an empty SQL store
+		When creating many Ventures at once fails part way through
+		Ensure an error is returned
+		And none of the Ventures have been created
+		And no IDs have been used up
+	`)
+
+	vtest.SetupEmptyTest()
+	defer vtest.TearDown()
+
+	s := vtest.Store()
+	failInsertsOf(t, "2")
+
+	vens, err := s.CreateAll([]ventures.NewVenture{
+		ventures.NewVenture{Description: "White wizard", State: "Not started"},
+		ventures.NewVenture{Description: "Green lizard", State: "Not started"},
+		ventures.NewVenture{Description: "Pink gizzard", State: "Not started"},
+	})
+	assert.NotNil(t, err)
+	assert.Nil(t, vens)
+
+	all, err := s.List(nil)
+	require.Nil(t, err)
+	assert.Empty(t, all)
+
+	ven := create(t, s, "Blue dragon", "Not started")
+	assert.Equal(t, "1", ven.ID)
+}
+
+// ****************************************************************************
+// MemStore
+// ****************************************************************************
+
+func TestMemStore_Concurrent(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given an empty in-memory store
+		When many Ventures are created concurrently
+		Ensure every Venture is assigned a unique ID
+	`)
+
+	s := ventures.NewMemStore(&workflow.Default)
+	wg := sync.WaitGroup{}
+
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := s.Create(&ventures.NewVenture{
+				Description: "White wizard",
+				State:       "Not started",
+			})
+			assert.Nil(t, err)
+		}()
+	}
+
+	wg.Wait()
+
+	vens, err := s.List(nil)
+	require.Nil(t, err)
+	require.Len(t, vens, 50)
+
+	ids := map[string]bool{}
+	for _, ven := range vens {
+		ids[ven.ID] = true
+	}
+	assert.Len(t, ids, 50)
+}
@@ -5,62 +5,38 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"os"
 	"testing"
 
 	"github.com/PaulioRandall/go-cookies/toastify"
-	"github.com/PaulioRandall/go-qlueless-api/api/database"
-	"github.com/PaulioRandall/go-qlueless-api/api/server"
+	"github.com/PaulioRandall/go-qlueless-api/api/config"
+	"github.com/PaulioRandall/go-qlueless-api/api/orders"
 	"github.com/PaulioRandall/go-qlueless-api/api/ventures"
 	"github.com/PaulioRandall/go-qlueless-api/shared/wrapped"
+	"github.com/PaulioRandall/go-qlueless-api/test"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
-var dbPath string = ""
-
 // SetupEmptyTest is run at the start of a test to setup the server but does
 // not inject any test data.
 func SetupEmptyTest() {
-	dbPath = getDbPath()
-	ResetDatabase()
-	server.StartUp(true)
+	test.StartServer(config.Default(), nil)
 }
 
 // SetupTest is run at the start of every test to setup the server and inject
 // the test data.
 func SetupTest() {
-	dbPath = getDbPath()
-	ResetDatabase()
-	DBInjectLiving()
-	DBInjectDead()
-	server.StartUp(true)
+	test.StartServer(config.Default(), func() {
+		DBInjectOrders()
+		DBInjectLiving()
+		DBInjectDead()
+	})
 }
 
 // TearDown should be deferred straight after SetupTest() is run to close
 // resources at the end of every test.
 func TearDown() {
-	server.Shutdown()
-	CloseDatabase()
-}
-
-// ResetDatabase will reset the database by closing and deleting it then
-// creating a new one.
-func ResetDatabase() {
-	CloseDatabase()
-	deleteIfExists(dbPath)
-
-	database.Open()
-
-	err := ventures.CreateTables()
-	if err != nil {
-		panic(err)
-	}
-}
-
-// CloseDatabase closes the test database.
-func CloseDatabase() {
-	database.Close()
+	test.StopServer()
 }
 
 // InjectAll injects a slice of Ventures into the database.
@@ -72,13 +48,45 @@ func InjectAll(new []ventures.NewVenture) []ventures.Venture {
 	return result
 }
 
+// Store returns the Venture store shared by the handlers of the server under
+// test.
+func Store() ventures.VentureStore {
+	return test.Ventures()
+}
+
 // Inject injects a Venture into the database.
 func Inject(new ventures.NewVenture) *ventures.Venture {
-	ven, ok := new.Insert()
+	ven, err := Store().Create(&new)
+	if err != nil {
+		panic(err)
+	}
+	return ven
+}
+
+// InjectOrder injects an Order into the database.
+func InjectOrder(new orders.NewOrder) *orders.Order {
+	o, ok := new.Insert(test.DB())
 	if !ok {
 		panic("Already printed above!")
 	}
-	return ven
+	return o
+}
+
+// DBInjectOrders injects a default set of living Orders into the database so
+// Ventures may reference them
+func DBInjectOrders() {
+	InjectOrder(orders.NewOrder{
+		Description: "Cast the spell",
+		State:       "Not started",
+	})
+	InjectOrder(orders.NewOrder{
+		Description: "Brew the potion",
+		State:       "In progress",
+	})
+	InjectOrder(orders.NewOrder{
+		Description: "Read the scroll",
+		State:       "Finished",
+	})
 }
 
 // DBInjectLiving injects a default set of living Ventures into the database
@@ -98,48 +106,47 @@ func DBInjectLiving() {
 	})
 	Inject(ventures.NewVenture{
 		Description: "Eddie Izzard",
-		State:       "In Progress",
+		State:       "In progress",
 	})
 	Inject(ventures.NewVenture{
 		Description: "The Count of Tuscany",
-		State:       "In Progress",
+		State:       "In progress",
 	})
 }
 
-// DBInjectDead injects a default set of dead Ventures into the database
+// DBInjectDead injects a default set of dead Ventures into the database. Each
+// is created then killed by a dead revision inserted directly, a millisecond
+// after it was created, so the revisions never share a last modified time.
 func DBInjectDead() {
-	s := []ventures.Venture{
-		*Inject(ventures.NewVenture{
-			Description: "Rose",
-			State:       "Finised",
-		}),
-		*Inject(ventures.NewVenture{
-			Description: "Lily",
-			State:       "Closed",
-		}),
+	for _, nv := range []ventures.NewVenture{
+		{Description: "Rose", State: "Finished"},
+		{Description: "Lily", State: "Closed"},
+	} {
+		ven := Inject(nv)
+		InjectRevision(ven, ven.LastModified+1, true)
 	}
+}
 
-	mod := ventures.ModVenture{
-		Props: "is_dead",
-		Values: ventures.Venture{
-			Dead: true,
-		},
-	}
+// InjectRevision inserts a revision of the Venture 'ven', made at the Unix time
+// 'ms' in milliseconds, directly into the database setting it dead or alive.
+func InjectRevision(ven *ventures.Venture, ms int64, dead bool) {
+	_, err := test.DB().Exec(`INSERT INTO venture (
+		id, last_modified, description, order_ids, state, is_dead, extra
+	) VALUES (
+		$1, $2, $3, $4, $5, $6, $7
+	);`, ven.ID, ms, ven.Description, ven.Orders, ven.State, dead, ven.Extra)
 
-	for _, ven := range s {
-		mod.ApplyMod(&ven)
-		err := ven.Update()
-		if err != nil {
-			panic(err)
-		}
+	if err != nil {
+		panic(err)
 	}
 }
 
 // DBQueryAll queries the database for all living ventures
 func DBQueryAll() []ventures.Venture {
-	rows, err := database.Get().Query(`
+	rows, err := test.DB().Query(`
 		SELECT id, last_modified, description, order_ids, state, extra
 		FROM ql_venture
+		ORDER BY id ASC
 	`)
 
 	if rows != nil {
@@ -155,10 +162,11 @@ func DBQueryAll() []ventures.Venture {
 
 // DBQueryMany queries the database for Ventures with the specified IDs
 func DBQueryMany(ids string) []ventures.Venture {
-	rows, err := database.Get().Query(fmt.Sprintf(`
+	rows, err := test.DB().Query(fmt.Sprintf(`
 		SELECT id, last_modified, description, order_ids, state, extra
 		FROM ql_venture
-		WHERE id IN (%s)`, ids))
+		WHERE id IN (%s)
+		ORDER BY id ASC`, ids))
 
 	if rows != nil {
 		defer rows.Close()
@@ -189,23 +197,6 @@ func DBQueryFirst() *ventures.Venture {
 	return nil
 }
 
-// getDbPath gets the path to the database or panics if there is an error.
-func getDbPath() string {
-	wd, err := os.Getwd()
-	if err != nil {
-		panic(err)
-	}
-	return wd + "/qlueless.db"
-}
-
-// deleteIfExists deletes the file at the path specified if it exist.
-func deleteIfExists(path string) {
-	err := os.Remove(path)
-	if err != nil && os.IsExist(err) {
-		panic(err)
-	}
-}
-
 // mapRows is a file private function that maps rows from a database query into
 // a slice of Ventures.
 func mapRows(rows *sql.Rows) []ventures.Venture {
@@ -0,0 +1,236 @@
+package workflow
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	config "github.com/PaulioRandall/go-qlueless-api/api/config"
+	server "github.com/PaulioRandall/go-qlueless-api/api/server"
+	workflow "github.com/PaulioRandall/go-qlueless-api/api/workflow"
+	test "github.com/PaulioRandall/go-qlueless-api/test"
+	assert "github.com/stretchr/testify/assert"
+	require "github.com/stretchr/testify/require"
+)
+
+func init() {
+	test.SetWorkingDir("../../bin")
+}
+
+// ****************************************************************************
+// (GET) /workflow
+// ****************************************************************************
+
+func TestGET_Workflow(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given no workflow file is present
+		When the workflow is requested
+		Ensure the response code is 200
+		And the 'Content-Type' header contains 'application/json'
+		And 'Access-Control-Allow-Methods' only contains GET and OPTIONS
+		And the body is the default workflow
+	`)
+
+	test.StartServer(config.Default(), nil)
+	defer test.StopServer()
+
+	req := test.APICall{
+		URL:    test.Host + "/workflow",
+		Method: "GET",
+	}
+	res := req.Fire()
+	defer res.Body.Close()
+
+	require.Equal(t, 200, res.StatusCode)
+	test.AssertDefaultHeaders(t, res, "application/json", "GET, OPTIONS")
+
+	wf, err := workflow.DecodeWorkflow(test.PrintBody(t, res))
+	require.Nil(t, err)
+	assert.Equal(t, workflow.Default, wf)
+}
+
+// ****************************************************************************
+// (OPTIONS) /workflow
+// ****************************************************************************
+
+func TestOPTIONS_Workflow(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		When only /workflow OPTIONS are requested
+		Ensure the response code is 200
+		And 'Access-Control-Allow-Methods' only contains GET and OPTIONS
+		And there is NO response body
+	`)
+
+	test.StartServer(config.Default(), nil)
+	defer test.StopServer()
+
+	req := test.APICall{
+		URL:    test.Host + "/workflow",
+		Method: "OPTIONS",
+	}
+	res := req.Fire()
+	defer res.Body.Close()
+	defer test.PrintResponse(t, res.Body)
+
+	require.Equal(t, 200, res.StatusCode)
+	test.AssertCorsHeaders(t, res, "GET, OPTIONS")
+	test.AssertEmptyBody(t, res.Body)
+}
+
+// ****************************************************************************
+// workflow.Load()
+// ****************************************************************************
+
+// writeWorkflow writes 'content' to a workflow file within a new temporary
+// directory returning the path of the file and a function that removes it.
+func writeWorkflow(t *testing.T, content string) (string, func()) {
+	dir, err := ioutil.TempDir("", "workflow")
+	require.Nil(t, err)
+
+	path := filepath.Join(dir, "workflow.json")
+	err = ioutil.WriteFile(path, []byte(content), 0644)
+	require.Nil(t, err)
+
+	return path, func() {
+		os.RemoveAll(dir)
+	}
+}
+
+func TestLoad_Missing(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given no workflow file exists
+		When the workflow is loaded
+		Ensure the default workflow is returned
+	`)
+
+	wf, err := workflow.Load(filepath.Join(os.TempDir(), "no-such-dir", "workflow.json"))
+	require.Nil(t, err)
+	assert.Equal(t, workflow.Default, *wf)
+}
+
+func TestLoad_Valid(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given a valid workflow file
+		When the workflow is loaded
+		Ensure the workflow within the file is returned
+	`)
+
+	path, cleanup := writeWorkflow(t, `{
+		"states": ["Todo", "Done"],
+		"transitions": {"Todo": ["Done"]},
+		"done": ["Done"]
+	}`)
+	defer cleanup()
+
+	wf, err := workflow.Load(path)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"Todo", "Done"}, wf.States)
+	assert.True(t, wf.Allows("todo", "Done"))
+	assert.False(t, wf.Allows("Done", "Todo"))
+}
+
+func TestLoad_Invalid(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given a workflow file that is not JSON or describes an invalid workflow
+		When the workflow is loaded
+		Ensure an error is returned
+		And the error lists the reasons the workflow is invalid
+	`)
+
+	path, cleanup := writeWorkflow(t, `{"states": [`)
+	defer cleanup()
+
+	_, err := workflow.Load(path)
+	assert.NotNil(t, err)
+
+	path, cleanup = writeWorkflow(t, `{
+		"states": ["Todo"],
+		"transitions": {"Todo": ["Doing"]},
+		"done": ["Done"]
+	}`)
+	defer cleanup()
+
+	_, err = workflow.Load(path)
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "'Doing'")
+	assert.Contains(t, err.Error(), "'Done'")
+}
+
+func TestLoad_Unreadable(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given a workflow path that exists but can't be read as a file
+		When the workflow is loaded
+		Ensure an error is returned
+	`)
+
+	dir, err := ioutil.TempDir("", "workflow")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	_, err = workflow.Load(dir)
+	assert.NotNil(t, err)
+}
+
+// ****************************************************************************
+// Server startup
+// ****************************************************************************
+
+func TestStartup_Workflow(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given the configured workflow file holds a valid workflow
+		When the server is started
+		Ensure the workflow is the one within the file
+	`)
+
+	path, cleanup := writeWorkflow(t, `{
+		"states": ["Todo", "Done"],
+		"transitions": {"Todo": ["Done"]},
+		"done": ["Done"]
+	}`)
+	defer cleanup()
+
+	cfg := config.Default()
+	cfg.WorkflowPath = path
+	test.StartServer(cfg, nil)
+	defer test.StopServer()
+
+	req := test.APICall{
+		URL:    test.Host + "/workflow",
+		Method: "GET",
+	}
+	res := req.Fire()
+	defer res.Body.Close()
+
+	require.Equal(t, 200, res.StatusCode)
+	wf, err := workflow.DecodeWorkflow(test.PrintBody(t, res))
+	require.Nil(t, err)
+	assert.Equal(t, []string{"Todo", "Done"}, wf.States)
+}
+
+func TestStartup_InvalidWorkflow(t *testing.T) {
+
+	test.PrintTestDescription(t, `
+		Given the configured workflow file holds an invalid workflow
+		When the server is created
+		Ensure an error is returned
+		And the error lists the reasons the workflow is invalid
+	`)
+
+	path, cleanup := writeWorkflow(t, `{"states": []}`)
+	defer cleanup()
+
+	cfg := config.Default()
+	cfg.WorkflowPath = path
+
+	_, err := server.New(cfg)
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "at least one state")
+}